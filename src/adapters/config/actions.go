@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Actions holds the configured actions in the order of their declaration in the YAML.
+	// It is written as a map in the YAML, but a go map would lose the order.
+	Actions []NamedAction
+
+	// NamedAction is a single action along with its name (the key in the YAML).
+	NamedAction struct {
+		Name string
+		Action
+	}
+)
+
+// UnmarshalYAML decodes the actions mapping while keeping the order of the keys.
+func (a *Actions) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: actions must be a mapping of action names to actions", value.Line)
+	}
+
+	result := Actions{}
+	seen := map[string]bool{}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		keyNode, valueNode := value.Content[i], value.Content[i+1]
+
+		name := keyNode.Value
+		if seen[name] {
+			return fmt.Errorf("line %d: action %s is defined more than once", keyNode.Line, name)
+		}
+		seen[name] = true
+
+		action := Action{}
+		if err := valueNode.Decode(&action); err != nil {
+			return fmt.Errorf("failed to decode action %s: %w", name, err)
+		}
+		result = append(result, NamedAction{Name: name, Action: action})
+	}
+
+	*a = result
+	return nil
+}
+
+// GetNames returns the names of the actions in their declaration order.
+func (a Actions) GetNames() []string {
+	names := []string{}
+	for _, action := range a {
+		names = append(names, action.Name)
+	}
+	return names
+}
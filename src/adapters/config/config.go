@@ -1,17 +1,70 @@
 // Package config loads, parses, verifies and enables the retrieval of the configuration.
 package config
 
-import "net.kopias.oscbridge/app/usecase/usecaseifs"
+import (
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
 
 var _ usecaseifs.IConfiguration = &MainConfig{}
 
 type (
 	// MainConfig represents the config YAML structure.
 	MainConfig struct {
-		OSCSources     OSCSource       `yaml:"osc_sources"`
-		OBSConnections []OBSConnection `yaml:"obs_connections" `
-		App            `yaml:"app"`
-		Actions        map[string]Action `yaml:"actions"`
+		OSCSources       OSCSource         `yaml:"osc_sources"`
+		OBSConnections   []OBSConnection   `yaml:"obs_connections" `
+		VISCAConnections []VISCAConnection `yaml:"visca_connections"`
+		App              `yaml:"app"`
+		Actions          Actions                   `yaml:"actions"`
+		ExclusiveGroups  map[string]ExclusiveGroup `yaml:"exclusive_groups"`
+		Modes            Modes                     `yaml:"modes"`
+		AdminAPI         AdminAPI                  `yaml:"admin_api"`
+		Macros           map[string]Macro          `yaml:"macros"`
+		SyncLinks        []SyncLink                `yaml:"sync_links"`
+	}
+
+	// SyncLink mirrors the changes of the mapped addresses between two OSC connections, in both directions.
+	SyncLink struct {
+		Name        string            `yaml:"name"`
+		Enabled     bool              `yaml:"enabled"`
+		ConnectionA string            `yaml:"connection_a"`
+		ConnectionB string            `yaml:"connection_b"`
+		Mappings    []SyncLinkMapping `yaml:"mappings"`
+		// EchoWindowMillis is the time within which a message equal to a mirrored one is taken as its echo.
+		EchoWindowMillis int64 `yaml:"echo_window_millis"`
+		// ReconcileDelayMillis is the wait at start, before the stored values of the two sides are reconciled.
+		ReconcileDelayMillis int64 `yaml:"reconcile_delay_millis"`
+	}
+
+	// SyncLinkMapping pairs the addresses (or patterns) of the two sides of a sync link.
+	SyncLinkMapping struct {
+		AddressA string `yaml:"address_a"`
+		// AddressB is the same as AddressA if empty.
+		AddressB string `yaml:"address_b"`
+		// Scale and Offset transform the numeric arguments from A to B as b = a * scale + offset, and back.
+		Scale  *float64 `yaml:"scale"`
+		Offset float64  `yaml:"offset"`
+	}
+
+	// Macro is a named, reusable sequence of tasks without a trigger chain, executed by the run_action task.
+	Macro struct {
+		Tasks []ActionTask `yaml:"tasks"`
+		// OnError determines what happens after a failed task: continue (default) or abort.
+		OnError string `yaml:"on_error"`
+	}
+
+	// AdminAPI serves HTTP endpoints to manage the bridge at runtime, e.g. to enable, disable or trigger actions.
+	AdminAPI struct {
+		Enabled bool   `yaml:"enabled"`
+		Port    int64  `yaml:"port"`
+		Host    string `yaml:"host"`
+	}
+
+	// Modes lists the named modes (profiles), that gate sets of actions.
+	Modes struct {
+		Available []string `yaml:"available"`
+		// Default is the active mode at start, if there is no (valid) persisted one.
+		Default string `yaml:"default"`
 	}
 
 	// OSCSource is the tree for all the different sources where OSC Messages can be received.
@@ -19,8 +72,10 @@ type (
 		ConsoleBridges   []ConsoleBridge   `yaml:"console_bridges"`
 		DummyConnections []DummyConnection `yaml:"dummy_connections"`
 		OBSBridges       []OBSBridge       `yaml:"obs_bridges"`
+		VISCABridges     []VISCABridge     `yaml:"visca_bridges"`
 		HTTPBridges      []HTTPBridge      `yaml:"http_bridges"`
 		Tickers          []Ticker          `yaml:"tickers"`
+		StatusBridges    []StatusBridge    `yaml:"status_bridges"`
 	}
 
 	// ConsoleBridge connects to an OSC source device, e.g. to a mixer console and receives messages, executes subscription commands.
@@ -35,6 +90,36 @@ type (
 		InitCommand       *OSCCommand           `yaml:"init_command"`
 		CheckAddress      string                `yaml:"check_address"`
 		CheckPattern      string                `yaml:"check_pattern"`
+		// KeepRunningOnFailure marks the connection down instead of exiting, when the connection check fails.
+		KeepRunningOnFailure bool `yaml:"keep_running_on_failure"`
+		// X32 configures the x32 osc implementation, it is ignored by the others.
+		X32  ConsoleX32  `yaml:"x32"`
+		Sync ConsoleSync `yaml:"sync"`
+	}
+
+	// ConsoleSync lists the addresses to query at start and after a reconnect, to have their current values in the store.
+	ConsoleSync struct {
+		// Addresses may contain {foo,bar} lists and {01..32} ranges.
+		Addresses           []string `yaml:"addresses"`
+		MaxQueriesPerSecond int      `yaml:"max_queries_per_second"`
+	}
+
+	// ConsoleX32 configures the Behringer X32/M32/XR specific features of a console bridge.
+	ConsoleX32 struct {
+		// Channels is the number of input channels, whose config (name, color, etc.) is queried at start.
+		Channels int `yaml:"channels"`
+		// DisableDiscovery skips querying the channel configs at start.
+		DisableDiscovery bool           `yaml:"disable_discovery"`
+		Meters           []ConsoleMeter `yaml:"meters"`
+	}
+
+	// ConsoleMeter subscribes to a meter bank (/meters/ID) of an X32 console, the levels are stored per meter.
+	ConsoleMeter struct {
+		ID int `yaml:"id"`
+		// Arguments are sent after the bank, some banks require them (e.g. the channel of /meters/6).
+		Arguments []int `yaml:"arguments"`
+		// TimeFactor sets the update interval to TimeFactor*50 milliseconds, the console's default is used if 0.
+		TimeFactor int `yaml:"time_factor"`
 	}
 
 	// ConsoleSubscription contains messages to be repeated at certain intervals to subscribe events on a mixer console.
@@ -65,6 +150,34 @@ type (
 		Prefix     string `yaml:"prefix"`
 		Enabled    bool   `yaml:"enabled"`
 		Connection string `yaml:"connection"`
+		// MediaRefreshRateMillis is the interval of polling the cursor of the playing media inputs.
+		MediaRefreshRateMillis int64 `yaml:"media_refresh_rate_millis"`
+		// EventMappings convert further OBS events to OSCMessages.
+		EventMappings []OBSEventMapping `yaml:"event_mappings"`
+		// PublishUnhandledEvents publishes every field of the events, that are neither built-in nor mapped, under /obs/events/.
+		PublishUnhandledEvents bool `yaml:"publish_unhandled_events"`
+	}
+
+	// OBSEventMapping converts an OBS event to an OSCMessage, the address may refer to the fields of the event as {Field}.
+	OBSEventMapping struct {
+		Event     string                    `yaml:"event"`
+		Address   string                    `yaml:"address"`
+		Arguments []OBSEventMappingArgument `yaml:"arguments"`
+	}
+
+	// OBSEventMappingArgument converts a field of an OBS event to an argument.
+	OBSEventMappingArgument struct {
+		Type  string `yaml:"type"`
+		Field string `yaml:"field"`
+	}
+
+	// A VISCABridge is an OSCSource, that uses a VISCAConnection by its name to poll the state of a camera and convert it to OSCMessages.
+	VISCABridge struct {
+		Name              string `yaml:"name"`
+		Prefix            string `yaml:"prefix"`
+		Enabled           bool   `yaml:"enabled"`
+		Connection        string `yaml:"connection"`
+		RefreshRateMillis int64  `yaml:"refresh_rate_millis"`
 	}
 
 	// A HTTPBridge is an OSCSource, that listens on a port for requests and converts them to OSCMessages.
@@ -84,6 +197,14 @@ type (
 		RefreshRateMillis int64  `yaml:"refresh_rate_millis"`
 	}
 
+	// A StatusBridge is an OSCSource, that emits OSCMessages about the health of the bridge itself.
+	StatusBridge struct {
+		Name              string `yaml:"name"`
+		Prefix            string `yaml:"prefix"`
+		Enabled           bool   `yaml:"enabled"`
+		RefreshRateMillis int64  `yaml:"refresh_rate_millis"`
+	}
+
 	// OSCCommand represent an OSC message
 	OSCCommand struct {
 		Address   string        `yaml:"address"`
@@ -105,10 +226,25 @@ type (
 		Password string `yaml:"password"`
 	}
 
+	// VISCAConnection represents a single PTZ camera controlled with VISCA over IP.
+	VISCAConnection struct {
+		Name string `yaml:"name"`
+		Port int64  `yaml:"port"`
+		Host string `yaml:"host"`
+		// TimeoutMillis is the time to wait for the camera to complete a command, e.g. to arrive at a preset.
+		TimeoutMillis int64 `yaml:"timeout_millis"`
+	}
+
 	// App contains general app settings.
 	App struct {
 		Debug            Debug  `yaml:"debug"`
 		StorePersistPath string `yaml:"store_persist_path"`
+
+		// MaxConcurrentActions limits how many action executions can run at the same time, 0 means unlimited.
+		MaxConcurrentActions int `yaml:"max_concurrent_actions"`
+
+		// ShutdownGraceMillis is the time given to the running actions to finish upon shutdown, before they are cancelled.
+		ShutdownGraceMillis int64 `yaml:"shutdown_grace_millis" env-default:"5000"`
 	}
 
 	Debug struct {
@@ -116,6 +252,7 @@ type (
 		DebugOSCConditions bool `yaml:"debug_osc_conditions"`
 		DebugTasks         bool `yaml:"debug_tasks"`
 		DebugOBSRemote     bool `yaml:"debug_obs_remote"`
+		DebugVISCARemote   bool `yaml:"debug_visca_remote"`
 	}
 
 	// Action contains a set of conditions that may trigger a set of tasks. E.g. If Channel 1 is muted, then do an HTTP request.
@@ -123,11 +260,53 @@ type (
 		DebounceMillis int64                  `yaml:"debounce_millis"`
 		TriggerChain   ActionConditionChecker `yaml:"trigger_chain"`
 		Tasks          []ActionTask           `yaml:"tasks"`
+
+		// Concurrency determines what happens when the action is triggered while it is still running.
+		Concurrency string `yaml:"concurrency"`
+		// QueueSize limits the number of waiting executions for the "queue" concurrency.
+		QueueSize int `yaml:"queue_size"`
+		// TimeoutMillis cancels the execution of the tasks if they take longer.
+		TimeoutMillis int64 `yaml:"timeout_millis"`
+
+		// Priority orders the evaluation of the actions, higher comes first, equal ones keep the declaration order.
+		Priority int `yaml:"priority"`
+		// StopAfterMatch skips the evaluation of the rest of the actions if this one fires.
+		StopAfterMatch bool `yaml:"stop_after_match"`
+		// ExclusiveGroup names the group of actions, within which only one may execute at a time.
+		ExclusiveGroup string `yaml:"exclusive_group"`
+
+		// CooldownMillis is the minimum time between two executions.
+		CooldownMillis int64 `yaml:"cooldown_millis"`
+		// MaxExecutionsPerMinute limits the number of executions within any one minute.
+		MaxExecutionsPerMinute int `yaml:"max_executions_per_minute"`
+		// Trailing runs the action once more at the end of a suppressed burst, with the latest state.
+		Trailing bool `yaml:"trailing"`
+		// Modes lists the modes in which the action is active, empty means all of them.
+		Modes []string `yaml:"modes"`
+		// OnError determines what happens after a failed task: continue (default) or abort.
+		OnError string `yaml:"on_error"`
+	}
+
+	// ExclusiveGroup configures how the contention is resolved between the actions of the same exclusive group.
+	ExclusiveGroup struct {
+		// Policy is one of first_wins (default), highest_priority, latest_wins.
+		Policy string `yaml:"policy"`
+		// MinHoldMillis is the time, after an action started, before another member of the group can execute.
+		MinHoldMillis int64 `yaml:"min_hold_millis"`
 	}
 
 	ActionTask struct {
 		Type       string                 `yaml:"type"`
 		Parameters map[string]interface{} `yaml:"parameters"`
+		// TimeoutMillis cancels the execution of this single task if it takes longer.
+		TimeoutMillis int64 `yaml:"timeout_millis"`
+
+		// The following are used by the control flow tasks only (if, parallel, retry, try).
+		Condition    *ActionConditionChecker `yaml:"condition"`
+		Tasks        []ActionTask            `yaml:"tasks"`
+		Then         []ActionTask            `yaml:"then"`
+		Else         []ActionTask            `yaml:"else"`
+		OnErrorTasks []ActionTask            `yaml:"on_error_tasks"`
 	}
 
 	ActionConditionChecker struct {
@@ -140,3 +319,28 @@ type (
 func (c *MainConfig) ShouldDebugOSCConditions() bool {
 	return c.App.Debug.DebugOSCConditions
 }
+
+func (c *MainConfig) GetMaxConcurrentActions() int {
+	return c.App.MaxConcurrentActions
+}
+
+func (c *MainConfig) GetShutdownGraceMillis() int64 {
+	return c.App.ShutdownGraceMillis
+}
+
+// GetExclusiveGroup returns the settings of the named exclusive group, with defaults if it is not configured.
+func (c *MainConfig) GetExclusiveGroup(name string) (policy string, minHoldMillis int64) {
+	group := c.ExclusiveGroups[name]
+	if group.Policy == "" {
+		group.Policy = entities.ExclusiveFirstWins
+	}
+	return group.Policy, group.MinHoldMillis
+}
+
+func (c *MainConfig) GetModes() []string {
+	return c.Modes.Available
+}
+
+func (c *MainConfig) GetDefaultMode() string {
+	return c.Modes.Default
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
 	"net.kopias.oscbridge/app/pkg/slicetools"
 )
 
@@ -17,14 +19,111 @@ func validateConfig(cfg *MainConfig) error {
 }
 
 func validateAPPConfig(cfg *MainConfig) error {
-	oscImpls := []string{"l" /* "s" */}
+	oscImpls := []string{"l", "x32" /* "s" */}
 
 	for _, cd := range cfg.OSCSources.ConsoleBridges {
 		if slicetools.IndexOf(oscImpls, cd.OSCImplementation) == -1 {
 			return fmt.Errorf("invalid osc implementation at %s: %s, valid values: %s", cd.Name, cd.OSCImplementation, strings.Join(oscImpls, ","))
 		}
+		for i, m := range cd.X32.Meters {
+			if m.ID < 0 || m.TimeFactor < 0 || m.TimeFactor > 99 {
+				return fmt.Errorf("invalid meter at %s x32.meters[%d]: the id must not be negative, the time_factor must be 0-99", cd.Name, i)
+			}
+		}
+		if cd.X32.Channels < 0 {
+			return fmt.Errorf("invalid x32.channels at %s: %d", cd.Name, cd.X32.Channels)
+		}
+		for _, address := range cd.Sync.Addresses {
+			if _, err := oscpattern.Expand(address); err != nil {
+				return fmt.Errorf("invalid sync address at %s: %w", cd.Name, err)
+			}
+		}
+		if cd.Sync.MaxQueriesPerSecond < 0 {
+			return fmt.Errorf("invalid sync.max_queries_per_second at %s: %d", cd.Name, cd.Sync.MaxQueriesPerSecond)
+		}
+	}
+
+	// A zero refresh rate is not set, it is defaulted at start.
+	for _, vb := range cfg.OSCSources.VISCABridges {
+		if vb.RefreshRateMillis < 0 {
+			return fmt.Errorf("invalid refresh_rate_millis at visca bridge %s: %d", vb.Name, vb.RefreshRateMillis)
+		}
+	}
+
+	for _, sb := range cfg.OSCSources.StatusBridges {
+		if sb.RefreshRateMillis < 0 {
+			return fmt.Errorf("invalid refresh_rate_millis at status bridge %s: %d", sb.Name, sb.RefreshRateMillis)
+		}
+	}
+
+	for name, group := range cfg.ExclusiveGroups {
+		if group.Policy != "" && slicetools.IndexOf(entities.ExclusivePolicies, group.Policy) == -1 {
+			return fmt.Errorf("invalid policy at exclusive group %s: %s, valid values: %s", name, group.Policy, strings.Join(entities.ExclusivePolicies, ","))
+		}
+	}
+
+	if err := validateModes(cfg); err != nil {
+		return err
+	}
+
+	if err := validateSyncLinks(cfg); err != nil {
+		return err
+	}
+
+	for _, name := range cfg.Actions.GetNames() {
+		if _, ok := cfg.Macros[name]; ok {
+			return fmt.Errorf("the name %s is used by both an action and a macro", name)
+		}
 	}
 
 	// @TODO add checks for connection-name integrity
 	return nil
 }
+
+func validateModes(cfg *MainConfig) error {
+	available := cfg.Modes.Available
+
+	if len(available) == 0 && cfg.Modes.Default != "" {
+		return fmt.Errorf("default mode %s is set, but there are no modes available", cfg.Modes.Default)
+	}
+	if len(available) > 0 && slicetools.IndexOf(available, cfg.Modes.Default) == -1 {
+		return fmt.Errorf("invalid default mode: '%s', valid values: %s", cfg.Modes.Default, strings.Join(available, ","))
+	}
+
+	for _, action := range cfg.Actions {
+		for _, mode := range action.Modes {
+			if slicetools.IndexOf(available, mode) == -1 {
+				return fmt.Errorf("invalid mode at action %s: %s, valid values: %s", action.Name, mode, strings.Join(available, ","))
+			}
+		}
+	}
+	return nil
+}
+
+func validateSyncLinks(cfg *MainConfig) error {
+	for _, link := range cfg.SyncLinks {
+		if link.ConnectionA == "" || link.ConnectionA == link.ConnectionB {
+			return fmt.Errorf("invalid sync link %s: connection_a and connection_b must be two different connections", link.Name)
+		}
+		if link.EchoWindowMillis < 0 || link.ReconcileDelayMillis < 0 {
+			return fmt.Errorf("invalid sync link %s: echo_window_millis and reconcile_delay_millis must not be negative", link.Name)
+		}
+		if len(link.Mappings) == 0 {
+			return fmt.Errorf("invalid sync link %s: there are no mappings", link.Name)
+		}
+
+		for i, m := range link.Mappings {
+			addressB := m.AddressB
+			if addressB == "" {
+				addressB = m.AddressA
+			}
+			if err := oscpattern.ValidateTranslation(m.AddressA, addressB); err != nil {
+				return fmt.Errorf("invalid sync link %s mappings[%d]: %w", link.Name, i, err)
+			}
+			if m.Scale != nil && *m.Scale == 0 {
+				return fmt.Errorf("invalid sync link %s mappings[%d]: the scale must not be 0", link.Name, i)
+			}
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"net.kopias.oscbridge/app/drivers/osc_connections/obs_bridge"
+	"net.kopias.oscbridge/app/drivers/osc_connections/visca_bridge"
 
 	"net.kopias.oscbridge/app/drivers/osc_conditions"
 
@@ -18,6 +19,7 @@ import (
 
 	"net.kopias.oscbridge/app/adapters/config"
 	"net.kopias.oscbridge/app/drivers/actioncomposer"
+	"net.kopias.oscbridge/app/drivers/adminapi"
 	"net.kopias.oscbridge/app/drivers/messagestore"
 	"net.kopias.oscbridge/app/drivers/obsremote"
 	"net.kopias.oscbridge/app/drivers/osc_conditions/cond_and"
@@ -25,13 +27,25 @@ import (
 	"net.kopias.oscbridge/app/drivers/osc_conditions/cond_or"
 	"net.kopias.oscbridge/app/drivers/osc_conditions/cond_osc_msg_match"
 	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_l"
+	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_x32"
 	"net.kopias.oscbridge/app/drivers/osc_connections/http_bridge"
+	"net.kopias.oscbridge/app/drivers/osc_connections/status_bridge"
 	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/synclink"
+	"net.kopias.oscbridge/app/drivers/tasks/action_control"
+	"net.kopias.oscbridge/app/drivers/tasks/controlflow"
 	"net.kopias.oscbridge/app/drivers/tasks/delay"
 	"net.kopias.oscbridge/app/drivers/tasks/httpreq"
 	"net.kopias.oscbridge/app/drivers/tasks/obstasks"
+	"net.kopias.oscbridge/app/drivers/tasks/query_osc"
+	"net.kopias.oscbridge/app/drivers/tasks/ramp_osc"
+	"net.kopias.oscbridge/app/drivers/tasks/run_action"
 	"net.kopias.oscbridge/app/drivers/tasks/run_command"
 	"net.kopias.oscbridge/app/drivers/tasks/send_osc_message"
+	"net.kopias.oscbridge/app/drivers/tasks/set_mode"
+	"net.kopias.oscbridge/app/drivers/tasks/variables"
+	"net.kopias.oscbridge/app/drivers/tasks/viscatasks"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
 	"net.kopias.oscbridge/app/entities"
 	"net.kopias.oscbridge/app/pkg/logger"
 	"net.kopias.oscbridge/app/usecase"
@@ -49,6 +63,15 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "mock-obs" {
+		ctx := context.Background()
+		if err := runMockOBS(ctx, logger.New(), os.Args[2:]); err != nil {
+			logger.New().Err(ctx, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	for {
 		ctx := context.Background()
 
@@ -99,6 +122,33 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 		obsConnections[c.Name] = obsRemote
 	}
 
+	// == VISCA Connections
+	log.Infof(ctx, "Initializing VISCA connections...")
+	viscaConnections := map[string]*viscaremote.VISCARemote{}
+	defer stopViscaConnections(ctx, viscaConnections)
+
+	for _, c := range cfg.VISCAConnections {
+		log.Infof(ctx, "\tConnecting to %s...", c.Name)
+		viscaRemoteCfg := viscaremote.Config{
+			Host:          c.Host,
+			Port:          c.Port,
+			TimeoutMillis: c.TimeoutMillis,
+			Debug:         cfg.App.Debug.DebugVISCARemote,
+		}
+		if viscaRemoteCfg.Port == 0 {
+			viscaRemoteCfg.Port = viscaremote.DefaultPort
+		}
+		if viscaRemoteCfg.TimeoutMillis == 0 {
+			viscaRemoteCfg.TimeoutMillis = 10000
+		}
+		viscaRemote := viscaremote.NewVISCARemote(log, viscaRemoteCfg)
+
+		if err = viscaRemote.Start(ctx); err != nil {
+			return err
+		}
+		viscaConnections[c.Name] = viscaRemote
+	}
+
 	oscConnections := []entities.OscConnectionDetails{}
 	defer stopOscConnections(ctx, oscConnections)
 
@@ -119,6 +169,13 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 		obsCfg := obs_bridge.Config{
 			Debug:      cfg.App.Debug.DebugOSCConnection,
 			Connection: conn,
+
+			MediaRefreshRateMillis: c.MediaRefreshRateMillis,
+			EventMappings:          c.EventMappings,
+			PublishUnhandledEvents: c.PublishUnhandledEvents,
+		}
+		if obsCfg.MediaRefreshRateMillis == 0 {
+			obsCfg.MediaRefreshRateMillis = 1000
 		}
 
 		oscConn = obs_bridge.NewOBSBridge(log, obsCfg)
@@ -129,6 +186,37 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 		oscConnections = append(oscConnections, *entities.NewOscConnectionDetails(c.Name, c.Prefix, oscConn))
 	}
 
+	// VISCA Bridges
+	log.Infof(ctx, "Initializing VISCA bridges...")
+	for _, c := range cfg.OSCSources.VISCABridges {
+		if !c.Enabled {
+			continue
+		}
+
+		var oscConn usecaseifs.IOSCConnection
+
+		log.Infof(ctx, "\tStarting visca bridge %s...", c.Name)
+		conn, ok := viscaConnections[c.Connection]
+		if !ok {
+			return fmt.Errorf("failed to start visca bridge: there is no connection named '%s'", c.Connection)
+		}
+		viscaCfg := visca_bridge.Config{
+			Debug:             cfg.App.Debug.DebugOSCConnection,
+			Connection:        conn,
+			RefreshRateMillis: c.RefreshRateMillis,
+		}
+		if viscaCfg.RefreshRateMillis == 0 {
+			viscaCfg.RefreshRateMillis = 1000
+		}
+
+		oscConn = visca_bridge.NewVISCABridge(log, viscaCfg)
+		if err = oscConn.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start visca bridge: %w", err)
+		}
+
+		oscConnections = append(oscConnections, *entities.NewOscConnectionDetails(c.Name, c.Prefix, oscConn))
+	}
+
 	// == Console Bridges
 	log.Infof(ctx, "Initializing Open Sound Control (mixer consoles, etc) connections...")
 	for _, c := range cfg.OSCSources.ConsoleBridges {
@@ -138,20 +226,33 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 		var oscConn usecaseifs.IOSCConnection
 
 		log.Infof(ctx, "\tConnecting to %s...", c.Name)
+		oscConnCfg := console_bridge_l.Config{
+			Debug:         cfg.App.Debug.DebugOSCConnection,
+			Subscriptions: c.Subscriptions,
+			Port:          c.Port,
+			Host:          c.Host,
+			CheckAddress:  c.CheckAddress,
+			CheckPattern:  c.CheckPattern,
+
+			KeepRunningOnFailure: c.KeepRunningOnFailure,
+			Sync:                 c.Sync,
+		}
+
 		switch c.OSCImplementation {
 		case "l":
-			oscConnCfg := console_bridge_l.Config{
-				Debug:         cfg.App.Debug.DebugOSCConnection,
-				Subscriptions: c.Subscriptions,
-				Port:          c.Port,
-				Host:          c.Host,
-				CheckAddress:  c.CheckAddress,
-				CheckPattern:  c.CheckPattern,
-			}
 			oscConn = console_bridge_l.NewConnection(log, oscConnCfg)
 			if err := oscConn.Start(ctx); err != nil {
 				return fmt.Errorf("failed to start osc[l] connection: %w", err)
 			}
+		case "x32":
+			oscConn = console_bridge_x32.NewConnection(log, console_bridge_x32.Config{
+				Debug:      cfg.App.Debug.DebugOSCConnection,
+				Connection: oscConnCfg,
+				X32:        c.X32,
+			})
+			if err := oscConn.Start(ctx); err != nil {
+				return fmt.Errorf("failed to start osc[x32] connection: %w", err)
+			}
 		// case "s":
 		// 	oscConnCfg := console_bridge_s.Config{
 		// 		Debug:         cfg.App.Debug.DebugOSCConnection,
@@ -250,22 +351,91 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 		oscConnections = append(oscConnections, *entities.NewOscConnectionDetails(c.Name, c.Prefix, oscConn))
 	}
 
+	// == Status bridges
+	// These are initialized last, as they report on all the other connections.
+	log.Infof(ctx, "Initializing status bridges...")
+	bridgeStatus := entities.NewBridgeStatus()
+	reportedConnections := append([]entities.OscConnectionDetails{}, oscConnections...)
+
+	for _, c := range cfg.OSCSources.StatusBridges {
+		if !c.Enabled {
+			continue
+		}
+
+		var oscConn usecaseifs.IOSCConnection
+
+		log.Infof(ctx, "\tStarting status bridge %s...", c.Name)
+		sbCfg := status_bridge.Config{
+			Debug:             cfg.App.Debug.DebugOSCConnection,
+			RefreshRateMillis: c.RefreshRateMillis,
+		}
+		if sbCfg.RefreshRateMillis == 0 {
+			sbCfg.RefreshRateMillis = 1000
+		}
+
+		oscConn = status_bridge.NewStatusBridge(log, sbCfg, bridgeStatus, reportedConnections)
+		if err := oscConn.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start status bridge: %w", err)
+		}
+
+		oscConnections = append(oscConnections, *entities.NewOscConnectionDetails(c.Name, c.Prefix, oscConn))
+	}
+
 	// == OSC Connection map
 	oscConnectionMap := map[string]usecaseifs.IOSCConnection{}
 	for _, c := range oscConnections {
 		oscConnectionMap[c.Name] = c.Connection
 	}
 
+	messageStore := messagestore.NewMessageStore()
+
+	// == Compose use cases
+	// The actions are added later, as some tasks depend on the use cases.
+	log.Infof(ctx, "Initializing Use cases...")
+	ucs := usecase.New(
+		log,
+		cfg,
+		oscConnections,
+		messageStore,
+		cfg.StorePersistPath,
+		bridgeStatus,
+	)
+
 	// == Tasks
 	log.Infof(ctx, "Initializing Tasks ...")
 
 	registeredTasks := map[string]usecaseifs.ActionTaskFactory{
-		"obs_scene_change":   obstasks.NewSceneChangerFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
-		"obs_vendor_request": obstasks.NewVendorRequestFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
-		"delay":              delay.NewFactory(log, cfg.App.Debug.DebugTasks),
-		"http_request":       httpreq.NewFactory(log, cfg.App.Debug.DebugTasks),
-		"send_osc_message":   send_osc_message.NewFactory(log, cfg.App.Debug.DebugTasks, oscConnectionMap),
-		"run_command":        run_command.NewFactory(log, cfg.App.Debug.DebugTasks),
+		"obs_scene_change":      obstasks.NewSceneChangerFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_vendor_request":    obstasks.NewVendorRequestFactory(obsConnections, ucs, log, cfg.App.Debug.DebugTasks),
+		"obs_output":            obstasks.NewOutputFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_input_audio":       obstasks.NewInputAudioFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_text":              obstasks.NewTextFactory(obsConnections, ucs, log, cfg.App.Debug.DebugTasks),
+		"obs_scene_item":        obstasks.NewSceneItemFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_studio_transition": obstasks.NewStudioTransitionFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_hotkey":            obstasks.NewHotkeyFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_filter":            obstasks.NewFilterFactory(obsConnections, log, cfg.App.Debug.DebugTasks),
+		"obs_screenshot":        obstasks.NewScreenshotFactory(obsConnections, ucs, log, cfg.App.Debug.DebugTasks),
+		"visca_preset":          viscatasks.NewPresetFactory(viscaConnections, log, cfg.App.Debug.DebugTasks),
+		"visca_pan_tilt":        viscatasks.NewPanTiltFactory(viscaConnections, log, cfg.App.Debug.DebugTasks),
+		"visca_zoom":            viscatasks.NewZoomFactory(viscaConnections, log, cfg.App.Debug.DebugTasks),
+		"visca_power":           viscatasks.NewPowerFactory(viscaConnections, log, cfg.App.Debug.DebugTasks),
+		"delay":                 delay.NewFactory(log, cfg.App.Debug.DebugTasks),
+		"http_request":          httpreq.NewFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"send_osc_message":      send_osc_message.NewFactory(log, cfg.App.Debug.DebugTasks, oscConnectionMap),
+		"query_osc":             query_osc.NewFactory(ucs, ucs, log, cfg.App.Debug.DebugTasks, oscConnectionMap),
+		"ramp_osc":              ramp_osc.NewFactory(ucs, ucs, log, cfg.App.Debug.DebugTasks, oscConnectionMap),
+		"run_command":           run_command.NewFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"set_variable":          variables.NewSetVariableFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"increment_variable":    variables.NewIncrementVariableFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"toggle_variable":       variables.NewToggleVariableFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"clear_variable":        variables.NewClearVariableFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"set_mode":              set_mode.NewFactory(ucs, log, cfg.App.Debug.DebugTasks, cfg.GetModes()),
+		"action_control":        action_control.NewFactory(ucs, log, cfg.App.Debug.DebugTasks, cfg.Actions.GetNames()),
+		"run_action":            run_action.NewFactory(log, cfg.App.Debug.DebugTasks),
+		"if":                    controlflow.NewIfFactory(ucs, log, cfg.App.Debug.DebugTasks),
+		"parallel":              controlflow.NewParallelFactory(log, cfg.App.Debug.DebugTasks),
+		"retry":                 controlflow.NewRetryFactory(log, cfg.App.Debug.DebugTasks),
+		"try":                   controlflow.NewTryFactory(log, cfg.App.Debug.DebugTasks),
 	}
 
 	// == Conditions
@@ -281,30 +451,55 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 	}
 
 	// == Composing actions
-	actionComposer := actioncomposer.NewActionComposer(cfg.Actions, registeredConditions, registeredTasks)
+	actionComposer := actioncomposer.NewActionComposer(cfg.Actions, cfg.Macros, registeredConditions, registeredTasks)
 	actions, err := actionComposer.GetActionList()
 	if err != nil {
 		return err
 	}
 
-	messageStore := messagestore.NewMessageStore()
-
-	// == Compose use cases
-	log.Infof(ctx, "Initializing Use cases...")
-	ucs := usecase.New(
-		log,
-		cfg,
-		oscConnections,
-		messageStore,
-		actions,
-		cfg.StorePersistPath,
-	)
+	ucs.SetActions(actions)
 
 	if err := ucs.Start(ctx); err != nil {
 		return err
 	}
 	defer ucs.Stop(ctx)
 
+	// == Sync links
+	// They are started after the use cases, as they rely on the incoming messages and the store.
+	log.Infof(ctx, "Initializing sync links...")
+	for _, c := range cfg.SyncLinks {
+		if !c.Enabled {
+			continue
+		}
+
+		log.Infof(ctx, "\tStarting sync link %s...", c.Name)
+		link, err := newSyncLink(log, cfg, c, oscConnections, ucs)
+		if err != nil {
+			return fmt.Errorf("failed to start sync link: %w", err)
+		}
+		if err := link.Start(ctx); err != nil {
+			return err
+		}
+		defer link.Stop(ctx)
+	}
+
+	// == Admin API
+	// It is stopped before the use cases, so no actions are triggered during the shutdown.
+	var adminAPINotify <-chan error
+	if cfg.AdminAPI.Enabled {
+		log.Infof(ctx, "Initializing the admin API...")
+		adminAPI := adminapi.NewAdminAPI(log, adminapi.Config{
+			Debug: cfg.App.Debug.DebugTasks,
+			Host:  cfg.AdminAPI.Host,
+			Port:  cfg.AdminAPI.Port,
+		}, ucs, ucs)
+		if err := adminAPI.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start the admin API: %w", err)
+		}
+		defer adminAPI.Stop(ctx)
+		adminAPINotify = adminAPI.Notify()
+	}
+
 	// == CTRL-C trap
 	interrupt, trapStop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM)
 	defer trapStop()
@@ -324,11 +519,61 @@ func startApp(ctx context.Context, log *logger.Logger) error {
 	case err := <-obsConnNotify(ctx, obsConnections):
 		return fmt.Errorf("OBS remote encountered an issue: %w", err)
 
+	case err := <-adminAPINotify:
+		return fmt.Errorf("admin API encountered an issue: %w", err)
+
 	case err := <-ucs.Notify():
 		return fmt.Errorf("USESCASES encountered an issue: %w", err)
 	}
 }
 
+// newSyncLink resolves the connections of a sync link by their names.
+func newSyncLink(log *logger.Logger, cfg *config.MainConfig, c config.SyncLink, oscConnections []entities.OscConnectionDetails, ucs *usecase.UseCases) (*synclink.SyncLink, error) {
+	linkCfg := synclink.Config{
+		Debug:          cfg.App.Debug.DebugOSCConnection,
+		Name:           c.Name,
+		EchoWindow:     time.Duration(c.EchoWindowMillis) * time.Millisecond,
+		ReconcileDelay: time.Duration(c.ReconcileDelayMillis) * time.Millisecond,
+	}
+	if linkCfg.EchoWindow == 0 {
+		linkCfg.EchoWindow = time.Second
+	}
+	if linkCfg.ReconcileDelay == 0 {
+		linkCfg.ReconcileDelay = 3 * time.Second
+	}
+
+	for side, name := range []string{c.ConnectionA, c.ConnectionB} {
+		found := false
+		for _, cd := range oscConnections {
+			if cd.Name == name {
+				linkCfg.Sides[side] = synclink.Side{Name: cd.Name, Prefix: cd.Prefix, Connection: cd.Connection}
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("there is no osc connection named '%s'", name)
+		}
+	}
+
+	// The stored values of the two sides are told apart by their prefixes.
+	if linkCfg.Sides[0].Prefix == linkCfg.Sides[1].Prefix {
+		return nil, fmt.Errorf("the connections of %s must have different prefixes", c.Name)
+	}
+
+	for _, m := range c.Mappings {
+		mapping := synclink.Mapping{Addresses: [2]string{m.AddressA, m.AddressB}, Scale: 1, Offset: m.Offset}
+		if m.AddressB == "" {
+			mapping.Addresses[1] = m.AddressA
+		}
+		if m.Scale != nil {
+			mapping.Scale = *m.Scale
+		}
+		linkCfg.Mappings = append(linkCfg.Mappings, mapping)
+	}
+
+	return synclink.NewSyncLink(log, linkCfg, ucs, ucs), nil
+}
+
 func stopObsConnections(ctx context.Context, connections map[string]*obsremote.OBSRemote) {
 	for _, c := range connections {
 		c.Stop(ctx)
@@ -349,6 +594,12 @@ func obsConnNotify(ctx context.Context, connections map[string]*obsremote.OBSRem
 	return e
 }
 
+func stopViscaConnections(ctx context.Context, connections map[string]*viscaremote.VISCARemote) {
+	for _, c := range connections {
+		c.Stop(ctx)
+	}
+}
+
 func stopOscConnections(ctx context.Context, connectionDetails []entities.OscConnectionDetails) {
 	for _, cd := range connectionDetails {
 		cd.Connection.Stop(ctx)
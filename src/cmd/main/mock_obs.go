@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"net.kopias.oscbridge/app/drivers/obsremote/standin"
+	"net.kopias.oscbridge/app/pkg/logger"
+)
+
+// runMockOBS runs a stand-in OBS (see the standin package) until it is interrupted.
+//
+// Lines on the standard input are executed as if they happened in OBS itself:
+//
+//	SetCurrentProgramScene {"sceneName": "PULPIT"}   executes a request, and emits its events
+//	emit CustomEvent {"foo": "bar"}                  emits an event
+//	state                                            prints the current state
+func runMockOBS(ctx context.Context, log *logger.Logger, args []string) error {
+	flags := flag.NewFlagSet("mock-obs", flag.ContinueOnError)
+	listen := flags.String("listen", "127.0.0.1:4455", "the address to listen on")
+	password := flags.String("password", "", "the password of the clients, no authentication if empty")
+	stateFile := flags.String("state", "", "a JSON file with the initial state, a small default studio if empty")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	state := standin.DefaultState()
+	if *stateFile != "" {
+		var err error
+		if state, err = standin.LoadState(*stateFile); err != nil {
+			return err
+		}
+	}
+
+	server, err := standin.NewServer(*listen, *password, state)
+	if err != nil {
+		return fmt.Errorf("failed to start the stand-in OBS: %w", err)
+	}
+
+	server.OnRequest = func(requestType string, data standin.RequestData, code int) {
+		log.Infof(ctx, "%s %v => %d", requestType, map[string]interface{}(data), code)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		_ = server.Close()
+	}()
+
+	go readMockOBSCommands(ctx, log, server)
+
+	log.Infof(ctx, "Stand-in OBS (obs-websocket %s) listening on %s", standin.Version, server.Addr())
+	return server.Serve()
+}
+
+func readMockOBSCommands(ctx context.Context, log *logger.Logger, server *standin.Server) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		command, rest, _ := strings.Cut(line, " ")
+		if command == "state" {
+			state, _ := json.MarshalIndent(server.GetState(), "", "  ")
+			// nolint:forbidigo
+			fmt.Println(string(state))
+			continue
+		}
+
+		if command == "emit" {
+			command, rest, _ = strings.Cut(strings.TrimSpace(rest), " ")
+		}
+
+		data := map[string]interface{}{}
+		if strings.TrimSpace(rest) != "" {
+			if err := json.Unmarshal([]byte(rest), &data); err != nil {
+				log.Errorf(ctx, "invalid JSON data: %s", err)
+				continue
+			}
+		}
+
+		if strings.HasPrefix(line, "emit ") {
+			server.EmitEvent(command, data)
+			continue
+		}
+
+		response, err := server.Execute(command, data)
+		if err != nil {
+			log.Err(ctx, err)
+			continue
+		}
+		if response != nil {
+			encoded, _ := json.Marshal(response)
+			log.Infof(ctx, "=> %s", encoded)
+		}
+	}
+}
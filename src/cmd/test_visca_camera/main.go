@@ -0,0 +1,33 @@
+// Command test_visca_camera runs a stand-in VISCA over IP camera, to test the visca connections, bridges and tasks
+// without the actual hardware.
+//
+// Run it with: go run ./cmd/test_visca_camera -listen 127.0.0.1:52381
+//
+// It logs every executed command with the resulting state. Presets must be stored before they can be recalled.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"net.kopias.oscbridge/app/drivers/viscaremote/standin"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:52381", "the UDP address to listen on")
+	flag.Parse()
+
+	camera, err := standin.NewCamera(*listen)
+	if err != nil {
+		log.Fatalf("failed to start the camera: %s", err)
+	}
+
+	camera.OnCommand = func(payload []byte, state standin.State) {
+		log.Printf("% X => power: %t pan: %d tilt: %d zoom: %d preset: %d", payload, state.Power, state.Pan, state.Tilt, state.Zoom, state.Preset)
+	}
+
+	log.Printf("Stand-in VISCA camera listening on %s", camera.Addr())
+	if err := camera.Serve(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+// Command test_x32_console runs a stand-in Behringer X32 console, to test the console bridges without the actual
+// hardware.
+//
+// Run it with: go run ./cmd/test_x32_console -listen 127.0.0.1:10023
+//
+// It logs every received message. Lines on the standard input change a parameter as if it was changed on the console
+// itself, e.g. `/ch/01/mix/fader 0.5`, `/ch/01/mix/on 0` or `/ch/01/config/name "Kick"`.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_x32/standin"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:10023", "the UDP address to listen on")
+	flag.Parse()
+
+	console, err := standin.NewConsole(*listen)
+	if err != nil {
+		log.Fatalf("failed to start the console: %s", err)
+	}
+
+	console.OnMessage = func(from *net.UDPAddr, address string, args []any) {
+		log.Printf("%s => %s %v", from, address, args)
+	}
+
+	go readChanges(console)
+
+	log.Printf("Stand-in X32 console listening on %s", console.Addr())
+	if err := console.Serve(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readChanges sets the parameters written to the standard input.
+func readChanges(console *standin.Console) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		address, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if address == "" || value == "" {
+			continue
+		}
+
+		if console.Get(address) == nil {
+			log.Printf("unknown parameter: %s", address)
+			continue
+		}
+		console.Set(address, parseValue(strings.TrimSpace(value)))
+	}
+}
+
+// parseValue returns a quoted value as a string, an integer as an int32, and a float as a float32.
+func parseValue(value string) any {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	if i, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return int32(i)
+	}
+	if f, err := strconv.ParseFloat(value, 32); err == nil {
+		return float32(f)
+	}
+	return value
+}
@@ -2,63 +2,179 @@ package actioncomposer
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+
+	"net.kopias.oscbridge/app/pkg/slicetools"
 
 	"net.kopias.oscbridge/app/adapters/config"
+	"net.kopias.oscbridge/app/drivers/tasks/controlflow"
 	"net.kopias.oscbridge/app/entities"
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
 
 // ActionComposer is responsible for composing actions from the YAML structure into an instance-structure.
 type ActionComposer struct {
-	// actions holds the name->configured action structure pairs
-	actions map[string]config.Action
+	// actions holds the configured actions in their declaration order.
+	actions config.Actions
+
+	// macros holds the configured task sequences, that are executed by other actions.
+	macros map[string]config.Macro
 
 	// conditions holds name->factory pairs for the conditions.
 	conditions map[string]usecaseifs.ActionConditionFactory
 
 	// tasks holds name->factory pairs for the tasks.
 	tasks map[string]usecaseifs.ActionTaskFactory
+
+	// composed holds the actions and macros composed so far, by their names.
+	composed map[string]usecaseifs.IAction
+
+	// composing is the chain of the actions and macros being composed, used to detect recursive references.
+	composing []string
 }
 
 func NewActionComposer(
-	actions map[string]config.Action,
+	actions config.Actions,
+	macros map[string]config.Macro,
 	conditions map[string]usecaseifs.ActionConditionFactory,
 	tasks map[string]usecaseifs.ActionTaskFactory,
 ) *ActionComposer {
 	return &ActionComposer{
 		actions:    actions,
+		macros:     macros,
 		conditions: conditions,
 		tasks:      tasks,
+		composed:   map[string]usecaseifs.IAction{},
 	}
 }
 
-// GetActionList processes the input actions and returns a list of IActions.
+// GetActionList processes the input actions and returns a list of IActions, in the order they should be evaluated:
+// by descending priority, and by the declaration order in case of equal priorities.
 func (a *ActionComposer) GetActionList() ([]usecaseifs.IAction, error) {
 	actionList := []usecaseifs.IAction{}
-	childIndex := 0
 
-	for actionName, cfgAction := range a.actions {
-		// Convert the conditions for this action.
-		condition, err := a.convertCondition(cfgAction.TriggerChain, actionName, childIndex)
+	for _, namedAction := range a.actions {
+		action, err := a.getComposed(namedAction.Name)
 		if err != nil {
 			return nil, err
 		}
+		actionList = append(actionList, action)
+	}
 
-		// Validate the condition parameters.
-		if err := condition.Validate(); err != nil {
-			return nil, fmt.Errorf("failed to validate %s's triggers: %w", actionName, err)
+	// The macros are composed even if they are not referenced, so their errors are not hidden.
+	macroNames := []string{}
+	for name := range a.macros {
+		macroNames = append(macroNames, name)
+	}
+	sort.Strings(macroNames)
+
+	for _, name := range macroNames {
+		if _, err := a.getComposed(name); err != nil {
+			return nil, err
 		}
+	}
 
-		// Convert the tasks for this action.
-		tasks, err := a.convertTasks(actionName, cfgAction.Tasks)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load tasks: %w", err)
+	sort.SliceStable(actionList, func(i, j int) bool {
+		return actionList[i].GetPriority() > actionList[j].GetPriority()
+	})
+
+	return actionList, nil
+}
+
+// getComposed returns the named action or macro, composing it first if it was not yet.
+func (a *ActionComposer) getComposed(name string) (usecaseifs.IAction, error) {
+	if action, ok := a.composed[name]; ok {
+		return action, nil
+	}
+
+	if slicetools.Contains(a.composing, name) {
+		return nil, fmt.Errorf("recursion detected: %s -> %s", strings.Join(a.composing, " -> "), name)
+	}
+	a.composing = append(a.composing, name)
+	defer func() { a.composing = a.composing[:len(a.composing)-1] }()
+
+	var action usecaseifs.IAction
+	var err error
+
+	if macro, ok := a.macros[name]; ok {
+		action, err = a.composeMacro(name, macro)
+	} else {
+		index := slicetools.IndexOf(a.actions.GetNames(), name)
+		if index == -1 {
+			return nil, fmt.Errorf("there is no action or macro named '%s'", name)
 		}
+		action, err = a.composeAction(a.actions[index], index)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.composed[name] = action
+	return action, nil
+}
 
-		actionList = append(actionList, entities.NewAction(actionName, condition, tasks, cfgAction.DebounceMillis))
-		childIndex++
+// composeAction composes a single action, [childIndex] is its declaration index.
+func (a *ActionComposer) composeAction(namedAction config.NamedAction, childIndex int) (usecaseifs.IAction, error) {
+	actionName, cfgAction := namedAction.Name, namedAction.Action
+
+	// Convert the conditions for this action.
+	condition, err := a.convertCondition(cfgAction.TriggerChain, actionName, childIndex)
+	if err != nil {
+		return nil, err
 	}
-	return actionList, nil
+
+	// Validate the condition parameters.
+	if err := condition.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate %s's triggers: %w", actionName, err)
+	}
+
+	// Convert the tasks for this action.
+	tasks, err := a.convertTasks(actionName, cfgAction.Tasks, cfgAction.OnError)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	if cfgAction.Concurrency != "" && !slicetools.Contains(entities.ConcurrencyModes, cfgAction.Concurrency) {
+		return nil, fmt.Errorf("invalid concurrency for action %s: '%s', valid values: %s",
+			actionName, cfgAction.Concurrency, strings.Join(entities.ConcurrencyModes, ", "))
+	}
+
+	if err := validateOnError(actionName, cfgAction.OnError); err != nil {
+		return nil, err
+	}
+
+	options := entities.ActionOptions{
+		DebounceMillis: cfgAction.DebounceMillis,
+		Concurrency:    cfgAction.Concurrency,
+		QueueSize:      cfgAction.QueueSize,
+		TimeoutMillis:  cfgAction.TimeoutMillis,
+		Priority:       cfgAction.Priority,
+		StopAfterMatch: cfgAction.StopAfterMatch,
+		ExclusiveGroup: cfgAction.ExclusiveGroup,
+
+		CooldownMillis:         cfgAction.CooldownMillis,
+		MaxExecutionsPerMinute: cfgAction.MaxExecutionsPerMinute,
+		Trailing:               cfgAction.Trailing,
+		Modes:                  cfgAction.Modes,
+		OnError:                cfgAction.OnError,
+	}
+
+	return entities.NewAction(actionName, condition, tasks, options), nil
+}
+
+// composeMacro composes a macro into an action without a trigger chain, it is never evaluated on its own.
+func (a *ActionComposer) composeMacro(name string, macro config.Macro) (usecaseifs.IAction, error) {
+	if err := validateOnError(name, macro.OnError); err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.convertTasks(name, macro.Tasks, macro.OnError)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks of macro %s: %w", name, err)
+	}
+
+	return entities.NewAction(name, nil, tasks, entities.ActionOptions{OnError: macro.OnError}), nil
 }
 
 // convertCondition instantiates and configures a single condition
@@ -84,8 +200,9 @@ func (a *ActionComposer) convertCondition(chain config.ActionConditionChecker, p
 	return cond, nil
 }
 
-// convertTasks instantiates and configures the tasks.
-func (a *ActionComposer) convertTasks(actionName string, tasks []config.ActionTask) ([]usecaseifs.IActionTask, error) {
+// convertTasks instantiates and configures the tasks, and the nested tasks of the control flow tasks recursively.
+// [onError] is the error policy of the action, that applies to the nested tasks as well.
+func (a *ActionComposer) convertTasks(actionName string, tasks []config.ActionTask, onError string) ([]usecaseifs.IActionTask, error) {
 	result := []usecaseifs.IActionTask{}
 
 	for i, task := range tasks {
@@ -94,12 +211,100 @@ func (a *ActionComposer) convertTasks(actionName string, tasks []config.ActionTa
 			return nil, fmt.Errorf("no such task type registered: %s", task.Type)
 		}
 
-		newTask := taskFactory()
+		createdTask := taskFactory()
+		newTask := createdTask
+		if task.TimeoutMillis > 0 {
+			newTask = entities.NewTimeoutTask(newTask, task.TimeoutMillis)
+		}
 		newTask.SetParameters(task.Parameters)
+
+		if err := a.convertNestedTasks(createdTask, task, actionName, i, onError); err != nil {
+			return nil, err
+		}
+
 		if err := newTask.Validate(); err != nil {
 			return nil, fmt.Errorf("failed to validate %s action's [%d-%s] task: %w", actionName, i, task.Type, err)
 		}
+
+		if referencingTask, ok := createdTask.(usecaseifs.IActionReferencingTask); ok {
+			if err := a.resolveReference(referencingTask); err != nil {
+				return nil, fmt.Errorf("failed to resolve %s action's [%d-%s] task: %w", actionName, i, task.Type, err)
+			}
+		}
 		result = append(result, newTask)
 	}
 	return result, nil
 }
+
+// resolveReference composes the action referenced by [task] (if it was not yet), and sets it on the task.
+func (a *ActionComposer) resolveReference(task usecaseifs.IActionReferencingTask) error {
+	name, checkConditions := task.GetReferencedAction()
+
+	if _, isMacro := a.macros[name]; isMacro && checkConditions {
+		return fmt.Errorf("macro %s has no trigger chain to check", name)
+	}
+
+	action, err := a.getComposed(name)
+	if err != nil {
+		return err
+	}
+
+	task.SetReferencedAction(action)
+	return nil
+}
+
+// convertNestedTasks composes the condition and the nested task lists of [task], if it is a control flow task.
+func (a *ActionComposer) convertNestedTasks(created usecaseifs.IActionTask, task config.ActionTask, actionName string, index int, onError string) error {
+	branches := map[string][]config.ActionTask{
+		controlflow.BranchTasks:   task.Tasks,
+		controlflow.BranchThen:    task.Then,
+		controlflow.BranchElse:    task.Else,
+		controlflow.BranchOnError: task.OnErrorTasks,
+	}
+
+	controlFlowTask, ok := created.(usecaseifs.IControlFlowTask)
+	if !ok {
+		for name, branch := range branches {
+			if branch != nil {
+				return fmt.Errorf("%s action's [%d-%s] task can not have nested '%s' tasks", actionName, index, task.Type, name)
+			}
+		}
+		if task.Condition != nil {
+			return fmt.Errorf("%s action's [%d-%s] task can not have a condition", actionName, index, task.Type)
+		}
+		return nil
+	}
+
+	if task.Condition != nil {
+		condition, err := a.convertCondition(*task.Condition, fmt.Sprintf("%s/%s", actionName, task.Type), index)
+		if err != nil {
+			return err
+		}
+		if err := condition.Validate(); err != nil {
+			return fmt.Errorf("failed to validate %s action's [%d-%s] task's condition: %w", actionName, index, task.Type, err)
+		}
+		controlFlowTask.SetCondition(condition)
+	}
+
+	converted := map[string][]usecaseifs.IActionTask{}
+	for name, branch := range branches {
+		if branch == nil {
+			continue
+		}
+
+		tasks, err := a.convertTasks(fmt.Sprintf("%s/%s:%d/%s", actionName, task.Type, index, name), branch, onError)
+		if err != nil {
+			return err
+		}
+		converted[name] = tasks
+	}
+	controlFlowTask.SetBranches(converted, onError)
+	return nil
+}
+
+func validateOnError(actionName string, onError string) error {
+	if onError != "" && !slicetools.Contains(entities.OnErrorPolicies, onError) {
+		return fmt.Errorf("invalid on_error for %s: '%s', valid values: %s", actionName, onError, strings.Join(entities.OnErrorPolicies, ", "))
+	}
+	return nil
+}
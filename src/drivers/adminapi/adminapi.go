@@ -0,0 +1,252 @@
+// Package adminapi serves an HTTP API to manage the bridge at runtime.
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"net.kopias.oscbridge/app/pkg/slicetools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+type Config struct {
+	Debug bool
+	Host  string
+	Port  int64
+}
+
+// AdminAPI serves the following endpoints:
+//
+//	GET  /actions                  lists the actions with their enabled state
+//	GET  /actions/<name>           returns a single action with its enabled state
+//	POST /actions/<name>/enable    enables the action
+//	POST /actions/<name>/disable   disables the action
+//	POST /actions/<name>/toggle    toggles the enabled state of the action
+//	POST /actions/<name>/trigger   executes the action, regardless of its trigger chain
+//	GET  /mode                     returns the active mode and the available ones
+//	POST /mode/<name>              switches to the mode, unknown modes are rejected
+type AdminAPI struct {
+	log        usecaseifs.ILogger
+	cfg        Config
+	controller usecaseifs.IActionController
+	modes      usecaseifs.IModeController
+	notify     chan error
+	srv        *http.Server
+}
+
+// actionState is the JSON representation of an action.
+type actionState struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// modeState is the JSON representation of the modes.
+type modeState struct {
+	Mode      string   `json:"mode"`
+	Available []string `json:"available"`
+}
+
+const (
+	commandEnable  = "enable"
+	commandDisable = "disable"
+	commandToggle  = "toggle"
+	commandTrigger = "trigger"
+)
+
+func NewAdminAPI(log usecaseifs.ILogger, cfg Config, controller usecaseifs.IActionController, modes usecaseifs.IModeController) *AdminAPI {
+	return &AdminAPI{
+		log:        log,
+		cfg:        cfg,
+		controller: controller,
+		modes:      modes,
+		notify:     make(chan error, 1),
+	}
+}
+
+func (a *AdminAPI) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/actions", a.handleActions)
+	mux.HandleFunc("/actions/", a.handleAction)
+	mux.HandleFunc("/mode", a.handleMode)
+	mux.HandleFunc("/mode/", a.handleSetMode)
+
+	a.srv = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", a.cfg.Host, a.cfg.Port),
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		BaseContext: func(listener net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go a.run(ctx)
+	return nil
+}
+
+func (a *AdminAPI) run(ctx context.Context) {
+	a.log.Infof(ctx, "Starting admin API at %s:%d", a.cfg.Host, a.cfg.Port)
+
+	if err := a.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		a.notify <- fmt.Errorf("admin API failed: %w", err)
+	}
+}
+
+// handleActions serves GET /actions.
+func (a *AdminAPI) handleActions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		a.respondError(r.Context(), w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+		return
+	}
+
+	states := []actionState{}
+	for _, name := range a.controller.GetActionNames() {
+		enabled, err := a.controller.IsActionEnabled(name)
+		if err != nil {
+			a.respondError(r.Context(), w, http.StatusInternalServerError, err)
+			return
+		}
+		states = append(states, actionState{Name: name, Enabled: enabled})
+	}
+
+	a.respond(r.Context(), w, states)
+}
+
+// handleAction serves GET /actions/<name> and POST /actions/<name>/<command>.
+func (a *AdminAPI) handleAction(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/actions/")
+
+	name, command := path, ""
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		name, command = path[:i], path[i+1:]
+	}
+
+	if !slicetools.Contains(a.controller.GetActionNames(), name) {
+		a.respondError(r.Context(), w, http.StatusNotFound, fmt.Errorf("there is no action named '%s'", name))
+		return
+	}
+
+	expectedMethod := http.MethodPost
+	if command == "" {
+		expectedMethod = http.MethodGet
+	}
+	if r.Method != expectedMethod {
+		a.respondError(r.Context(), w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+		return
+	}
+
+	if a.cfg.Debug && command != "" {
+		a.log.Infof(r.Context(), "Admin API: %s action %s", command, name)
+	}
+
+	var err error
+	switch command {
+	case "":
+	case commandEnable, commandDisable, commandToggle:
+		_, err = a.controller.SetActionEnabled(r.Context(), name, func(enabled bool) bool {
+			switch command {
+			case commandEnable:
+				return true
+			case commandDisable:
+				return false
+			default:
+				return !enabled
+			}
+		})
+	case commandTrigger:
+		err = a.controller.TriggerAction(r.Context(), name)
+	default:
+		a.respondError(r.Context(), w, http.StatusNotFound, fmt.Errorf("unknown command: '%s'", command))
+		return
+	}
+
+	if err != nil {
+		a.respondError(r.Context(), w, http.StatusConflict, err)
+		return
+	}
+
+	enabled, err := a.controller.IsActionEnabled(name)
+	if err != nil {
+		a.respondError(r.Context(), w, http.StatusInternalServerError, err)
+		return
+	}
+	a.respond(r.Context(), w, actionState{Name: name, Enabled: enabled})
+}
+
+// handleMode serves GET /mode.
+func (a *AdminAPI) handleMode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		a.respondError(r.Context(), w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+		return
+	}
+
+	a.respondMode(r.Context(), w)
+}
+
+// handleSetMode serves POST /mode/<name>.
+func (a *AdminAPI) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.respondError(r.Context(), w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+		return
+	}
+
+	if len(a.modes.GetModes()) == 0 {
+		a.respondError(r.Context(), w, http.StatusNotFound, fmt.Errorf("there are no modes configured"))
+		return
+	}
+
+	mode := strings.TrimPrefix(r.URL.Path, "/mode/")
+	if !slicetools.Contains(a.modes.GetModes(), mode) {
+		a.respondError(r.Context(), w, http.StatusBadRequest, fmt.Errorf("invalid mode: '%s', valid values: %s", mode, strings.Join(a.modes.GetModes(), ",")))
+		return
+	}
+
+	if a.cfg.Debug {
+		a.log.Infof(r.Context(), "Admin API: set mode %s", mode)
+	}
+
+	if err := a.modes.SetMode(r.Context(), mode); err != nil {
+		a.respondError(r.Context(), w, http.StatusConflict, err)
+		return
+	}
+
+	a.respondMode(r.Context(), w)
+}
+
+func (a *AdminAPI) respondMode(ctx context.Context, w http.ResponseWriter) {
+	mode, _ := a.modes.GetActiveMode()
+	a.respond(ctx, w, modeState{Mode: mode, Available: a.modes.GetModes()})
+}
+
+func (a *AdminAPI) respond(ctx context.Context, w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		a.log.Err(ctx, fmt.Errorf("failed to respond to request: %w", err))
+	}
+}
+
+func (a *AdminAPI) respondError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		a.log.Err(ctx, fmt.Errorf("failed to respond to request: %w", err))
+	}
+}
+
+// Notify returns the notification channel that can be used to listen for the server's exit
+func (a *AdminAPI) Notify() <-chan error {
+	return a.notify
+}
+
+func (a *AdminAPI) Stop(ctx context.Context) {
+	if a.srv != nil {
+		_ = a.srv.Shutdown(ctx)
+	}
+}
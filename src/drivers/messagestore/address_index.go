@@ -0,0 +1,128 @@
+package messagestore
+
+import (
+	"strings"
+
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// addressIndexNode is a node of a persistent trie, built from the address parts of the records.
+// E.g. /ch/01/mix/on is stored under the path "" -> "ch" -> "01" -> "mix" -> "on".
+// This enables pattern and prefix lookups without scanning every record.
+//
+// Nodes are never modified once they are reachable from a root, updates copy the nodes along the path instead.
+// Therefore, a root pointer is an immutable snapshot of the whole store, that can be shared freely.
+type addressIndexNode struct {
+	children map[string]*addressIndexNode
+
+	// record is set if there is a record stored with the address leading to this node.
+	record usecaseifs.IMessageStoreRecord
+}
+
+// emptyAddressIndexNode is shared by every new trie, as it is never modified.
+var emptyAddressIndexNode = &addressIndexNode{children: map[string]*addressIndexNode{}}
+
+// get returns the record stored exactly under [address].
+func (n *addressIndexNode) get(address string) (usecaseifs.IMessageStoreRecord, bool) {
+	node := n
+	for _, part := range oscpattern.Split(address) {
+		child, ok := node.children[part]
+		if !ok {
+			return nil, false
+		}
+		node = child
+	}
+	return node.record, node.record != nil
+}
+
+// with returns a new trie in which [record] is stored under [address], sharing every untouched node with the receiver.
+// The second return value tells if the address was not present before.
+func (n *addressIndexNode) with(address string, record usecaseifs.IMessageStoreRecord) (*addressIndexNode, bool) {
+	return n.withParts(oscpattern.Split(address), record)
+}
+
+func (n *addressIndexNode) withParts(parts []string, record usecaseifs.IMessageStoreRecord) (*addressIndexNode, bool) {
+	if len(parts) == 0 {
+		return &addressIndexNode{children: n.children, record: record}, n.record == nil
+	}
+
+	child, ok := n.children[parts[0]]
+	if !ok {
+		child = emptyAddressIndexNode
+	}
+	newChild, added := child.withParts(parts[1:], record)
+
+	children := make(map[string]*addressIndexNode, len(n.children)+1)
+	for name, c := range n.children {
+		children[name] = c
+	}
+	children[parts[0]] = newChild
+
+	return &addressIndexNode{children: children, record: n.record}, added
+}
+
+// findByPattern returns all the records whose address matches the OSC [pattern].
+func (n *addressIndexNode) findByPattern(pattern string) []usecaseifs.IMessageStoreRecord {
+	result := []usecaseifs.IMessageStoreRecord{}
+	n.walkPattern(oscpattern.Split(pattern), &result)
+	return result
+}
+
+func (n *addressIndexNode) walkPattern(parts []string, result *[]usecaseifs.IMessageStoreRecord) {
+	if len(parts) == 0 {
+		if n.record != nil {
+			*result = append(*result, n.record)
+		}
+		return
+	}
+
+	// Literal parts are a simple lookup, only wildcards need iteration over the children.
+	if oscpattern.IsLiteral(parts[0]) {
+		if child, ok := n.children[parts[0]]; ok {
+			child.walkPattern(parts[1:], result)
+		}
+		return
+	}
+
+	for name, child := range n.children {
+		if oscpattern.MatchPart(parts[0], name) {
+			child.walkPattern(parts[1:], result)
+		}
+	}
+}
+
+// findByPrefix returns all the records whose address starts with [prefix].
+// The prefix does not need to end on a part boundary, e.g. "/ch/0" matches "/ch/01/mix/on".
+func (n *addressIndexNode) findByPrefix(prefix string) []usecaseifs.IMessageStoreRecord {
+	result := []usecaseifs.IMessageStoreRecord{}
+	parts := oscpattern.Split(prefix)
+
+	node := n
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node.children[part]
+		if !ok {
+			return result
+		}
+		node = child
+	}
+
+	lastPart := parts[len(parts)-1]
+	for name, child := range node.children {
+		if strings.HasPrefix(name, lastPart) {
+			child.collect(&result)
+		}
+	}
+	return result
+}
+
+// collect appends every record from this subtree to [result].
+func (n *addressIndexNode) collect(result *[]usecaseifs.IMessageStoreRecord) {
+	if n.record != nil {
+		*result = append(*result, n.record)
+	}
+	for _, child := range n.children {
+		child.collect(result)
+	}
+}
@@ -0,0 +1,117 @@
+package messagestore
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// indexedAddresses contain records on inner nodes (/ch/01), empty parts and similar part names on purpose.
+var indexedAddresses = []string{
+	"/",
+	"/ch/01",
+	"/ch/01/mix/on",
+	"/ch/01/mix/fader",
+	"/ch/02/mix/on",
+	"/ch/10/mix/on",
+	"/ch/10/config/name",
+	"/ch//mix/on",
+	"/chx/01/mix/on",
+	"/bus/01/mix/on",
+	"/main/st/mix/on",
+	"/ch/01/mix/on/",
+}
+
+func newIndexedStore() *MessageStore {
+	store := NewMessageStore()
+	for i, address := range indexedAddresses {
+		store.SetRecord(newMessage(address, i))
+	}
+	return store
+}
+
+// scan returns the sorted addresses accepted by [filter], like the former store scanning the whole map.
+func scan(filter func(address string) bool) []string {
+	result := []string{}
+	for _, address := range indexedAddresses {
+		if filter(address) {
+			result = append(result, address)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func addressesOf(records []usecaseifs.IMessageStoreRecord) []string {
+	result := []string{}
+	for _, record := range records {
+		result = append(result, record.GetMessage().GetAddress())
+	}
+	return result
+}
+
+func TestGetRecord(t *testing.T) {
+	store := newIndexedStore()
+
+	for _, address := range indexedAddresses {
+		if record, ok := store.GetRecord(address, false); !ok || record.GetMessage().GetAddress() != address {
+			t.Errorf("GetRecord(%q) = %v, %t", address, record, ok)
+		}
+	}
+	for _, address := range []string{"", "/ch", "/ch/01/mix", "/ch/03/mix/on", "/ch/01/mix/on//"} {
+		if record, ok := store.GetRecord(address, false); ok {
+			t.Errorf("GetRecord(%q) = %v, expected no record", address, record)
+		}
+	}
+	if size := store.GetSize(); size != len(indexedAddresses) {
+		t.Errorf("GetSize = %d, want %d", size, len(indexedAddresses))
+	}
+}
+
+func TestGetRecordsByPrefix(t *testing.T) {
+	store := newIndexedStore()
+
+	for _, prefix := range []string{"", "/", "/c", "/ch", "/ch/", "/ch/0", "/ch/01", "/ch/01/", "/ch/01/mix/on", "/ch/1", "/ch//", "/x"} {
+		want := scan(func(address string) bool { return strings.HasPrefix(address, prefix) })
+		if got := addressesOf(store.GetRecordsByPrefix(prefix, false)); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetRecordsByPrefix(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+}
+
+func TestGetRecordsByPattern(t *testing.T) {
+	store := newIndexedStore()
+
+	patterns := []string{
+		"/ch/01/mix/on",
+		"/ch/*/mix/on",
+		"/ch/*",
+		"/*",
+		"/*/*/mix/on",
+		"/ch/{01,10}/mix/*",
+		"/ch/[0-1]?/*/*",
+		"/ch/[!0]*/mix/on",
+		"/ch?/01/mix/on",
+		"/ch/01/mix/on/*",
+		"/ch/03/mix/on",
+	}
+	for _, pattern := range patterns {
+		want := scan(func(address string) bool { return oscpattern.Match(pattern, address) })
+		got, err := store.GetRecordsByPattern(pattern, false)
+		if err != nil {
+			t.Errorf("GetRecordsByPattern(%q) failed: %s", pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(addressesOf(got), want) {
+			t.Errorf("GetRecordsByPattern(%q) = %v, want %v", pattern, addressesOf(got), want)
+		}
+	}
+
+	if _, err := store.GetRecordsByPattern("/ch/[01/mix/on", false); err == nil {
+		t.Errorf("GetRecordsByPattern accepted an invalid pattern")
+	}
+}
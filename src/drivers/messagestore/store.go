@@ -3,11 +3,14 @@
 package messagestore
 
 import (
+	"fmt"
 	"regexp"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+
 	"net.kopias.oscbridge/app/pkg/slicetools"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
@@ -15,9 +18,16 @@ import (
 
 var _ usecaseifs.IMessageStore = &MessageStore{}
 
+// MessageStore holds the latest message for every address.
+// The records are kept in a persistent trie (see addressIndexNode), so a snapshot of the store is just a pointer to the
+// current root, which makes Clone O(1) regardless of the number of records.
 type MessageStore struct {
-	m                     *sync.RWMutex
-	store                 map[string]usecaseifs.IMessageStoreRecord
+	m    *sync.RWMutex
+	root *addressIndexNode
+	size int
+
+	// watchM guards the watched record and its accesses, as a snapshot may be read concurrently (e.g. by parallel tasks).
+	watchM                *sync.Mutex
 	watchedRecord         *usecaseifs.IOSCMessage
 	watchedRecordAccesses int64
 }
@@ -25,6 +35,9 @@ type MessageStore struct {
 // WatchRecordAccess registers a message, and from the point of the call, the store will cound how many times that address has been accessed.
 // See [MessageStore.GetWatchedRecordAccesses].
 func (e *MessageStore) WatchRecordAccess(msg *usecaseifs.IOSCMessage) {
+	e.watchM.Lock()
+	defer e.watchM.Unlock()
+
 	e.watchedRecord = msg
 	e.watchedRecordAccesses = 0
 }
@@ -32,6 +45,9 @@ func (e *MessageStore) WatchRecordAccess(msg *usecaseifs.IOSCMessage) {
 // GetWatchedRecordAccesses returns the number of accesses that the watched record received.
 // See [MessageStore.GetWatchedRecordAccesses]
 func (e *MessageStore) GetWatchedRecordAccesses() int64 {
+	e.watchM.Lock()
+	defer e.watchM.Unlock()
+
 	return e.watchedRecordAccesses
 }
 
@@ -42,6 +58,9 @@ func (e *MessageStore) checkWatchedRecordAccess(msgs []usecaseifs.IOSCMessage, t
 		return
 	}
 
+	e.watchM.Lock()
+	defer e.watchM.Unlock()
+
 	if e.watchedRecord == nil {
 		return
 	}
@@ -53,43 +72,58 @@ func (e *MessageStore) checkWatchedRecordAccess(msgs []usecaseifs.IOSCMessage, t
 	}
 }
 
-// Clone clones this message store
+// Clone returns an independent copy of this message store. As the trie is immutable, only the root is copied.
 func (e *MessageStore) Clone() usecaseifs.IMessageStore {
 	newStore := NewMessageStore()
-	newStore.store = e.GetAll()
+
+	e.m.RLock()
+	newStore.root = e.root
+	newStore.size = e.size
+	e.m.RUnlock()
 
 	return newStore
 }
 
+// getRoot returns the current root of the trie, which is safe to read without locking afterwards.
+func (e *MessageStore) getRoot() *addressIndexNode {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	return e.root
+}
+
 // GetAll returns every record. It does not do record watching.
 func (e *MessageStore) GetAll() map[string]usecaseifs.IMessageStoreRecord {
-	newData := map[string]usecaseifs.IMessageStoreRecord{}
+	records := []usecaseifs.IMessageStoreRecord{}
+	e.getRoot().collect(&records)
 
-	e.m.RLock()
-	for key, value := range e.store {
-		newData[key] = value
+	newData := make(map[string]usecaseifs.IMessageStoreRecord, len(records))
+	for _, record := range records {
+		newData[record.GetMessage().GetAddress()] = record
 	}
-	// we clone ourselves, hopefully will always work
-	// newData := deepcopy.Anything(e.store).(map[string]usecaseifs.IMessageStoreRecord)
-	e.m.RUnlock()
 
 	return newData
 }
 
+// GetSize returns the number of records in the store.
+func (e *MessageStore) GetSize() int {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	return e.size
+}
+
 // GetRecord returns a message with the exact [address].
 // [trackAccess] determines if this access is tracked or not. See WatchRecordAccess.
 func (e *MessageStore) GetRecord(address string, trackAccess bool) (usecaseifs.IMessageStoreRecord, bool) {
-	e.m.RLock()
-	record, ok := e.store[address]
+	record, ok := e.getRoot().get(address)
 	if ok {
 		e.checkWatchedRecordAccess([]usecaseifs.IOSCMessage{record.GetMessage()}, trackAccess)
 	}
-	e.m.RUnlock()
 
 	return record, ok
 }
 
 // GetOneRecordByRegexp returns a single record whose address is matching the [re] expression.
+// If there are multiple matches, the one with the lowest address (in lexical order) is returned.
 // [trackAccess] determines if this access is tracked or not. See WatchRecordAccess.
 func (e *MessageStore) GetOneRecordByRegexp(re string, trackAccess bool) (usecaseifs.IMessageStoreRecord, error) {
 	result, err := e.getRecordsByRegexpFinder(re, true, trackAccess)
@@ -102,7 +136,7 @@ func (e *MessageStore) GetOneRecordByRegexp(re string, trackAccess bool) (usecas
 	return result[0], err
 }
 
-// GetRecordsByRegexp returns a list of records whose address is matching the [re] expression.
+// GetRecordsByRegexp returns a list of records whose address is matching the [re] expression, ordered by address.
 // [trackAccess] determines if this access is tracked or not. See WatchRecordAccess.
 func (e *MessageStore) GetRecordsByRegexp(re string, trackAccess bool) ([]usecaseifs.IMessageStoreRecord, error) {
 	return e.getRecordsByRegexpFinder(re, false, trackAccess)
@@ -115,62 +149,86 @@ func (e *MessageStore) getRecordsByRegexpFinder(re string, firstOnly bool, track
 		return nil, err
 	}
 
-	result := []usecaseifs.IMessageStoreRecord{}
+	all := []usecaseifs.IMessageStoreRecord{}
+	e.getRoot().collect(&all)
 
-	e.m.RLock()
-	for address, record := range e.store {
-		if compiledRegex.MatchString(address) {
+	result := []usecaseifs.IMessageStoreRecord{}
+	for _, record := range all {
+		if compiledRegex.MatchString(record.GetMessage().GetAddress()) {
 			result = append(result, record)
-			if firstOnly {
-				break
-			}
 		}
 	}
+
+	// Map iteration order is random, sorting makes the result deterministic.
+	sortRecordsByAddress(result)
+	if firstOnly && len(result) > 1 {
+		result = result[:1]
+	}
+
 	e.checkWatchedRecordAccess(slicetools.Map(result, func(t usecaseifs.IMessageStoreRecord) usecaseifs.IOSCMessage {
 		return t.GetMessage()
 	}), trackAccess)
 
-	e.m.RUnlock()
 	return result, nil
 }
 
-// GetRecordsByPrefix returns a list of records whose address starts with [prefix].
+// GetRecordsByPrefix returns a list of records whose address starts with [prefix], ordered by address.
 // [trackAccess] determines if this access is tracked or not. See WatchRecordAccess.
 func (e *MessageStore) GetRecordsByPrefix(prefix string, trackAccess bool) []usecaseifs.IMessageStoreRecord {
-	result := []usecaseifs.IMessageStoreRecord{}
+	result := e.getRoot().findByPrefix(prefix)
+	sortRecordsByAddress(result)
+	e.checkWatchedRecordAccess(slicetools.Map(result, func(t usecaseifs.IMessageStoreRecord) usecaseifs.IOSCMessage {
+		return t.GetMessage()
+	}), trackAccess)
+	return result
+}
 
-	e.m.RLock()
-	for address, record := range e.store {
-		if strings.HasPrefix(address, prefix) {
-			result = append(result, record)
-		}
+// GetRecordsByPattern returns a list of records whose address is matching the OSC 1.0 address [pattern]
+// (e.g. /ch/*/mix/on or /ch/{01,02}/mix/on), ordered by address.
+// [trackAccess] determines if this access is tracked or not. See WatchRecordAccess.
+func (e *MessageStore) GetRecordsByPattern(pattern string, trackAccess bool) ([]usecaseifs.IMessageStoreRecord, error) {
+	if err := oscpattern.Validate(pattern); err != nil {
+		return nil, fmt.Errorf("invalid osc address pattern: %w", err)
 	}
+
+	result := e.getRoot().findByPattern(pattern)
+	sortRecordsByAddress(result)
 	e.checkWatchedRecordAccess(slicetools.Map(result, func(t usecaseifs.IMessageStoreRecord) usecaseifs.IOSCMessage {
 		return t.GetMessage()
 	}), trackAccess)
-	e.m.RUnlock()
-	return result
+	return result, nil
 }
 
 // SetRecord updates the store. Returns the fact if the store changed or not.
+// Existing clones are not affected, as the changed path of the trie is copied.
 func (e *MessageStore) SetRecord(record usecaseifs.IOSCMessage) bool {
-	changed := false
 	e.m.Lock()
+	defer e.m.Unlock()
 
-	oldRecord, ok := e.store[record.GetAddress()]
+	oldRecord, ok := e.root.get(record.GetAddress())
+	if ok && oldRecord.GetMessage().Equal(record) {
+		return false
+	}
 
-	if ok && !oldRecord.GetMessage().Equal(record) || !ok {
-		e.store[record.GetAddress()] = NewMessageStoreRecord(record, time.Now())
-		changed = true
+	newRoot, added := e.root.with(record.GetAddress(), NewMessageStoreRecord(record, time.Now()))
+	e.root = newRoot
+	if added {
+		e.size++
 	}
+	return true
+}
 
-	e.m.Unlock()
-	return changed
+// sortRecordsByAddress orders the records in place by their address.
+func sortRecordsByAddress(records []usecaseifs.IMessageStoreRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].GetMessage().GetAddress() < records[j].GetMessage().GetAddress()
+	})
 }
 
 func NewMessageStore() *MessageStore {
 	return &MessageStore{
-		m:     &sync.RWMutex{},
-		store: make(map[string]usecaseifs.IMessageStoreRecord),
+		m:      &sync.RWMutex{},
+		watchM: &sync.Mutex{},
+		root:   emptyAddressIndexNode,
 	}
 }
@@ -0,0 +1,170 @@
+package messagestore
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// The benchmarks compare the message store snapshotting against the former full-map-copy approach, with 10k records.
+// Run them with: go test -bench . ./drivers/messagestore
+
+const benchmarkRecordCount = 10000
+
+// snapshotter is the common part of the two stores under benchmark.
+type snapshotter interface {
+	SetRecord(msg usecaseifs.IOSCMessage) bool
+	snapshot() snapshotter
+}
+
+// legacyStore reproduces the former MessageStore behaviour: a map that is copied on every Clone.
+type legacyStore struct {
+	m     *sync.RWMutex
+	store map[string]usecaseifs.IMessageStoreRecord
+}
+
+func newLegacyStore() *legacyStore {
+	return &legacyStore{m: &sync.RWMutex{}, store: map[string]usecaseifs.IMessageStoreRecord{}}
+}
+
+func (l *legacyStore) SetRecord(msg usecaseifs.IOSCMessage) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	old, ok := l.store[msg.GetAddress()]
+	if ok && old.GetMessage().Equal(msg) {
+		return false
+	}
+	l.store[msg.GetAddress()] = NewMessageStoreRecord(msg, time.Now())
+	return true
+}
+
+func (l *legacyStore) snapshot() snapshotter {
+	newStore := newLegacyStore()
+	l.m.RLock()
+	for k, v := range l.store {
+		newStore.store[k] = v
+	}
+	l.m.RUnlock()
+	return newStore
+}
+
+// trieStore adapts MessageStore to the snapshotter interface.
+type trieStore struct {
+	*MessageStore
+}
+
+func (c trieStore) snapshot() snapshotter {
+	// nolint:forcetypeassert
+	return trieStore{c.MessageStore.Clone().(*MessageStore)}
+}
+
+var benchmarkStores = []struct {
+	name  string
+	store func() snapshotter
+}{
+	{"legacy map copy", func() snapshotter { return newLegacyStore() }},
+	{"persistent trie", func() snapshotter { return trieStore{NewMessageStore()} }},
+}
+
+func BenchmarkClone(b *testing.B) {
+	addresses := generateAddresses()
+
+	for _, s := range benchmarkStores {
+		store := fill(s.store(), addresses)
+
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				store.snapshot()
+			}
+		})
+	}
+}
+
+// BenchmarkUpdateAndClone measures what happens for every changed message.
+func BenchmarkUpdateAndClone(b *testing.B) {
+	addresses := generateAddresses()
+
+	for _, s := range benchmarkStores {
+		store := fill(s.store(), addresses)
+
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				store.SetRecord(newMessage(addresses[i%len(addresses)], i+1))
+				store.snapshot()
+			}
+		})
+	}
+}
+
+// generateAddresses returns benchmarkRecordCount unique, X32-like addresses.
+func generateAddresses() []string {
+	addresses := make([]string, 0, benchmarkRecordCount)
+	for i := 0; len(addresses) < benchmarkRecordCount; i++ {
+		addresses = append(addresses, fmt.Sprintf("/ch/%03d/param/%02d", i/100, i%100))
+	}
+	return addresses
+}
+
+func fill(store snapshotter, addresses []string) snapshotter {
+	for i, address := range addresses {
+		store.SetRecord(newMessage(address, i))
+	}
+	return store
+}
+
+func newMessage(address string, value int) usecaseifs.IOSCMessage {
+	return osc_message.NewMessage(address, []usecaseifs.IOSCMessageArgument{
+		osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", float32(value%1000)/1000)),
+	})
+}
+
+// TestCloneIsolation checks that a snapshot is not affected by the later updates of the store, and vice versa.
+func TestCloneIsolation(t *testing.T) {
+	store := NewMessageStore()
+	store.SetRecord(newMessage("/ch/01/mix/on", 1))
+	store.SetRecord(newMessage("/ch/02/mix/on", 2))
+
+	snapshot := store.Clone()
+
+	store.SetRecord(newMessage("/ch/01/mix/on", 3))
+	store.SetRecord(newMessage("/ch/03/mix/on", 4))
+	snapshot.SetRecord(newMessage("/ch/02/mix/on", 5))
+
+	checkValue := func(name string, s usecaseifs.IMessageStore, address string, want usecaseifs.IOSCMessage) {
+		t.Helper()
+		record, ok := s.GetRecord(address, false)
+		if want == nil {
+			if ok {
+				t.Errorf("%s has %s, expected no record", name, address)
+			}
+			return
+		}
+		if !ok || !record.GetMessage().Equal(want) {
+			t.Errorf("%s has %v at %s, want %v", name, record, address, want)
+		}
+	}
+
+	checkValue("the snapshot", snapshot, "/ch/01/mix/on", newMessage("/ch/01/mix/on", 1))
+	checkValue("the snapshot", snapshot, "/ch/02/mix/on", newMessage("/ch/02/mix/on", 5))
+	checkValue("the snapshot", snapshot, "/ch/03/mix/on", nil)
+	checkValue("the store", store, "/ch/01/mix/on", newMessage("/ch/01/mix/on", 3))
+	checkValue("the store", store, "/ch/02/mix/on", newMessage("/ch/02/mix/on", 2))
+	checkValue("the store", store, "/ch/03/mix/on", newMessage("/ch/03/mix/on", 4))
+
+	if size := snapshot.GetSize(); size != 2 {
+		t.Errorf("the snapshot has %d records, want 2", size)
+	}
+	if size := store.GetSize(); size != 3 {
+		t.Errorf("the store has %d records, want 3", size)
+	}
+	if records := snapshot.GetRecordsByPrefix("/ch/", false); len(records) != 2 {
+		t.Errorf("the snapshot lists %v, want 2 records", addressesOf(records))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/andreykaipov/goobs"
 	"github.com/andreykaipov/goobs/api/requests/general"
 
 	"github.com/andreykaipov/goobs/api/requests/ui"
@@ -18,37 +19,34 @@ import (
 )
 
 func (or *OBSRemote) ListScenes(ctx context.Context) ([]string, error) {
-	if or.client == nil {
-		return nil, fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
-	list, err := or.client.Scenes.GetSceneList()
+	var sceneNames []string
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		list, err := client.Scenes.GetSceneList()
+		if err != nil {
+			return err
+		}
+		sceneNames = slicetools.Map(list.Scenes, func(t *typedefs.Scene) string {
+			return t.SceneName
+		})
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list scenes: %w", err)
 	}
-	sceneNames := slicetools.Map(list.Scenes, func(t *typedefs.Scene) string {
-		return t.SceneName
-	})
 
 	return sceneNames, nil
 }
 
 func (or *OBSRemote) SwitchPreviewScene(ctx context.Context, sceneName string) error {
-	if or.client == nil {
-		return fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
 	params := &scenes.SetCurrentPreviewSceneParams{
 		SceneName: sceneName,
 	}
 
-	_, err := or.client.Scenes.SetCurrentPreviewScene(params)
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		_, err := client.Scenes.SetCurrentPreviewScene(params)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to switch scene: %w", err)
 	}
@@ -56,17 +54,14 @@ func (or *OBSRemote) SwitchPreviewScene(ctx context.Context, sceneName string) e
 }
 
 func (or *OBSRemote) SwitchProgramScene(ctx context.Context, sceneName string) error {
-	if or.client == nil {
-		return fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
 	params := &scenes.SetCurrentProgramSceneParams{
 		SceneName: sceneName,
 	}
-	_, err := or.client.Scenes.SetCurrentProgramScene(params)
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		_, err := client.Scenes.SetCurrentProgramScene(params)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to switch scene: %w", err)
 	}
@@ -74,94 +69,101 @@ func (or *OBSRemote) SwitchProgramScene(ctx context.Context, sceneName string) e
 }
 
 func (or *OBSRemote) GetCurrentProgramScene(ctx context.Context) (string, error) {
-	if or.client == nil {
-		return "", fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
-	sme, err := or.client.Ui.GetStudioModeEnabled(&ui.GetStudioModeEnabledParams{})
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve studio mode state: %w", err)
-	}
-	if !sme.StudioModeEnabled {
-		return "", nil
-	}
-
-	params := &scenes.GetCurrentProgramSceneParams{}
-	r, err := or.client.Scenes.GetCurrentProgramScene(params)
+	var sceneName string
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		sme, err := client.Ui.GetStudioModeEnabled(&ui.GetStudioModeEnabledParams{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve studio mode state: %w", err)
+		}
+		if !sme.StudioModeEnabled {
+			return nil
+		}
+
+		r, err := client.Scenes.GetCurrentProgramScene(&scenes.GetCurrentProgramSceneParams{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve current program scene: %w", err)
+		}
+		sceneName = r.CurrentProgramSceneName
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve current program scene: %w", err)
+		return "", err
 	}
-	return r.CurrentProgramSceneName, nil
+	return sceneName, nil
 }
 
 func (or *OBSRemote) GetCurrentPreviewScene(ctx context.Context) (string, error) {
-	if or.client == nil {
-		return "", fmt.Errorf("not connected")
-	}
-	or.m.Lock()
-	defer or.m.Unlock()
-
-	params := &scenes.GetCurrentPreviewSceneParams{}
-	r, err := or.client.Scenes.GetCurrentPreviewScene(params)
+	var sceneName string
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Scenes.GetCurrentPreviewScene(&scenes.GetCurrentPreviewSceneParams{})
+		if err != nil {
+			return err
+		}
+		sceneName = r.CurrentPreviewSceneName
+		return nil
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to retrieve current preview scene: %w", err)
 	}
-	return r.CurrentPreviewSceneName, nil
+	return sceneName, nil
 }
 
 func (or *OBSRemote) IsStreaming(ctx context.Context) (bool, error) {
-	if or.client == nil {
-		return false, fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
-	params := &stream.GetStreamStatusParams{}
-	r, err := or.client.Stream.GetStreamStatus(params)
+	var active bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Stream.GetStreamStatus(&stream.GetStreamStatusParams{})
+		if err != nil {
+			return err
+		}
+		active = r.OutputActive
+		return nil
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to retrieve stream status: %w", err)
 	}
-	return r.OutputActive, nil
+	return active, nil
 }
 
 func (or *OBSRemote) IsRecording(ctx context.Context) (bool, error) {
-	if or.client == nil {
-		return false, fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
-	params := &record.GetRecordStatusParams{}
-	r, err := or.client.Record.GetRecordStatus(params)
+	var active bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Record.GetRecordStatus(&record.GetRecordStatusParams{})
+		if err != nil {
+			return err
+		}
+		active = r.OutputActive
+		return nil
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to retrieve recording status: %w", err)
 	}
 
-	return r.OutputActive, nil
+	return active, nil
 }
 
 func (or *OBSRemote) VendorRequest(ctx context.Context, vendorName string, requestType string, requestData interface{}) (responseData interface{}, err error) {
-	if or.client == nil {
-		return nil, fmt.Errorf("not connected")
-	}
-
-	or.m.Lock()
-	defer or.m.Unlock()
-
 	params := &general.CallVendorRequestParams{
 		RequestData: requestData,
 		RequestType: requestType,
 		VendorName:  vendorName,
 	}
-	r, err := or.client.General.CallVendorRequest(params)
+
+	var data interface{}
+	err = or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.General.CallVendorRequest(params)
+		if err != nil {
+			return err
+		}
+		data = r.ResponseData
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send vendor request: %w", err)
 	}
 
-	return r.ResponseData, nil
+	return data, nil
 }
@@ -0,0 +1,260 @@
+package obsremote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/filters"
+	"github.com/andreykaipov/goobs/api/requests/general"
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"github.com/andreykaipov/goobs/api/requests/outputs"
+	"github.com/andreykaipov/goobs/api/requests/record"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"github.com/andreykaipov/goobs/api/requests/sources"
+	"github.com/andreykaipov/goobs/api/requests/stream"
+	"github.com/andreykaipov/goobs/api/requests/transitions"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+func (or *OBSRemote) StartRecording(ctx context.Context) error {
+	return or.request(ctx, "failed to start recording", func(client *goobs.Client) error {
+		_, err := client.Record.StartRecord(&record.StartRecordParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) StopRecording(ctx context.Context) error {
+	return or.request(ctx, "failed to stop recording", func(client *goobs.Client) error {
+		_, err := client.Record.StopRecord(&record.StopRecordParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) ToggleRecording(ctx context.Context) error {
+	return or.request(ctx, "failed to toggle recording", func(client *goobs.Client) error {
+		_, err := client.Record.ToggleRecord(&record.ToggleRecordParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) StartStreaming(ctx context.Context) error {
+	return or.request(ctx, "failed to start streaming", func(client *goobs.Client) error {
+		_, err := client.Stream.StartStream(&stream.StartStreamParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) StopStreaming(ctx context.Context) error {
+	return or.request(ctx, "failed to stop streaming", func(client *goobs.Client) error {
+		_, err := client.Stream.StopStream(&stream.StopStreamParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) ToggleStreaming(ctx context.Context) error {
+	return or.request(ctx, "failed to toggle streaming", func(client *goobs.Client) error {
+		_, err := client.Stream.ToggleStream(&stream.ToggleStreamParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) SaveReplayBuffer(ctx context.Context) error {
+	return or.request(ctx, "failed to save replay buffer", func(client *goobs.Client) error {
+		_, err := client.Outputs.SaveReplayBuffer(&outputs.SaveReplayBufferParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) SetInputMute(ctx context.Context, inputName string, muted bool) error {
+	return or.request(ctx, fmt.Sprintf("failed to set mute state of %s", inputName), func(client *goobs.Client) error {
+		_, err := client.Inputs.SetInputMute(&inputs.SetInputMuteParams{InputName: inputName, InputMuted: &muted})
+		return err
+	})
+}
+
+// ToggleInputMute returns the new mute state.
+func (or *OBSRemote) ToggleInputMute(ctx context.Context, inputName string) (bool, error) {
+	var muted bool
+
+	err := or.request(ctx, fmt.Sprintf("failed to toggle mute state of %s", inputName), func(client *goobs.Client) error {
+		r, err := client.Inputs.ToggleInputMute(&inputs.ToggleInputMuteParams{InputName: inputName})
+		if err != nil {
+			return err
+		}
+		muted = r.InputMuted
+		return nil
+	})
+	return muted, err
+}
+
+// setInputVolumeParams replaces inputs.SetInputVolumeParams, that omits the zero values (0 dB, or a multiplier of 0).
+type setInputVolumeParams struct {
+	InputName      string   `json:"inputName"`
+	InputVolumeDb  *float64 `json:"inputVolumeDb,omitempty"`
+	InputVolumeMul *float64 `json:"inputVolumeMul,omitempty"`
+}
+
+func (o *setInputVolumeParams) GetRequestName() string {
+	return "SetInputVolume"
+}
+
+func (or *OBSRemote) SetInputVolumeDb(ctx context.Context, inputName string, db float64) error {
+	return or.request(ctx, fmt.Sprintf("failed to set volume of %s", inputName), func(client *goobs.Client) error {
+		return client.Inputs.SendRequest(&setInputVolumeParams{InputName: inputName, InputVolumeDb: &db}, &inputs.SetInputVolumeResponse{})
+	})
+}
+
+func (or *OBSRemote) SetInputVolumeMul(ctx context.Context, inputName string, mul float64) error {
+	return or.request(ctx, fmt.Sprintf("failed to set volume of %s", inputName), func(client *goobs.Client) error {
+		return client.Inputs.SendRequest(&setInputVolumeParams{InputName: inputName, InputVolumeMul: &mul}, &inputs.SetInputVolumeResponse{})
+	})
+}
+
+// SetInputText sets the text of a text source (GDI+ or FreeType 2), leaving its other settings unchanged.
+func (or *OBSRemote) SetInputText(ctx context.Context, inputName string, text string) error {
+	overlay := true
+	return or.request(ctx, fmt.Sprintf("failed to set text of %s", inputName), func(client *goobs.Client) error {
+		_, err := client.Inputs.SetInputSettings(&inputs.SetInputSettingsParams{
+			InputName:     inputName,
+			InputSettings: map[string]interface{}{"text": text},
+			Overlay:       &overlay,
+		})
+		return err
+	})
+}
+
+func (or *OBSRemote) IsSceneItemEnabled(ctx context.Context, sceneName string, sourceName string) (bool, error) {
+	var enabled bool
+
+	err := or.request(ctx, fmt.Sprintf("failed to retrieve the state of %s on %s", sourceName, sceneName), func(client *goobs.Client) error {
+		id, err := getSceneItemID(client, sceneName, sourceName)
+		if err != nil {
+			return err
+		}
+
+		r, err := client.SceneItems.GetSceneItemEnabled(&sceneitems.GetSceneItemEnabledParams{SceneName: sceneName, SceneItemId: id})
+		if err != nil {
+			return err
+		}
+		enabled = r.SceneItemEnabled
+		return nil
+	})
+	return enabled, err
+}
+
+func (or *OBSRemote) SetSceneItemEnabled(ctx context.Context, sceneName string, sourceName string, enabled bool) error {
+	return or.request(ctx, fmt.Sprintf("failed to set the state of %s on %s", sourceName, sceneName), func(client *goobs.Client) error {
+		id, err := getSceneItemID(client, sceneName, sourceName)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.SceneItems.SetSceneItemEnabled(&sceneitems.SetSceneItemEnabledParams{SceneName: sceneName, SceneItemId: id, SceneItemEnabled: &enabled})
+		return err
+	})
+}
+
+func getSceneItemID(client *goobs.Client, sceneName string, sourceName string) (float64, error) {
+	r, err := client.SceneItems.GetSceneItemId(&sceneitems.GetSceneItemIdParams{SceneName: sceneName, SourceName: sourceName})
+	if err != nil {
+		return 0, err
+	}
+	return r.SceneItemId, nil
+}
+
+// TriggerStudioModeTransition transitions the preview scene to program.
+// If [transitionName] is set, it becomes the current transition first, same for [durationMillis] if it is positive.
+func (or *OBSRemote) TriggerStudioModeTransition(ctx context.Context, transitionName string, durationMillis int) error {
+	return or.request(ctx, "failed to trigger studio mode transition", func(client *goobs.Client) error {
+		if transitionName != "" {
+			if _, err := client.Transitions.SetCurrentSceneTransition(&transitions.SetCurrentSceneTransitionParams{TransitionName: transitionName}); err != nil {
+				return fmt.Errorf("failed to set transition to %s: %w", transitionName, err)
+			}
+		}
+
+		if durationMillis > 0 {
+			if _, err := client.Transitions.SetCurrentSceneTransitionDuration(&transitions.SetCurrentSceneTransitionDurationParams{TransitionDuration: float64(durationMillis)}); err != nil {
+				return fmt.Errorf("failed to set transition duration to %d: %w", durationMillis, err)
+			}
+		}
+
+		_, err := client.Transitions.TriggerStudioModeTransition(&transitions.TriggerStudioModeTransitionParams{})
+		return err
+	})
+}
+
+func (or *OBSRemote) TriggerHotkeyByName(ctx context.Context, hotkeyName string) error {
+	return or.request(ctx, fmt.Sprintf("failed to trigger hotkey %s", hotkeyName), func(client *goobs.Client) error {
+		_, err := client.General.TriggerHotkeyByName(&general.TriggerHotkeyByNameParams{HotkeyName: hotkeyName})
+		return err
+	})
+}
+
+// triggerHotkeyByKeySequenceParams replaces general.TriggerHotkeyByKeySequenceParams, as its modifiers are mis-generated.
+type triggerHotkeyByKeySequenceParams struct {
+	KeyID        string       `json:"keyId"`
+	KeyModifiers keyModifiers `json:"keyModifiers"`
+}
+
+type keyModifiers struct {
+	Shift   bool `json:"shift"`
+	Control bool `json:"control"`
+	Alt     bool `json:"alt"`
+	Command bool `json:"command"`
+}
+
+func (o *triggerHotkeyByKeySequenceParams) GetRequestName() string {
+	return "TriggerHotkeyByKeySequence"
+}
+
+func (or *OBSRemote) TriggerHotkeyByKeySequence(ctx context.Context, keyID string, modifiers usecaseifs.OBSKeyModifiers) error {
+	return or.request(ctx, fmt.Sprintf("failed to trigger hotkey %s", keyID), func(client *goobs.Client) error {
+		return client.General.SendRequest(&triggerHotkeyByKeySequenceParams{KeyID: keyID, KeyModifiers: keyModifiers(modifiers)}, &general.TriggerHotkeyByKeySequenceResponse{})
+	})
+}
+
+func (or *OBSRemote) IsSourceFilterEnabled(ctx context.Context, sourceName string, filterName string) (bool, error) {
+	var enabled bool
+
+	err := or.request(ctx, fmt.Sprintf("failed to retrieve the state of filter %s of %s", filterName, sourceName), func(client *goobs.Client) error {
+		r, err := client.Filters.GetSourceFilter(&filters.GetSourceFilterParams{SourceName: sourceName, FilterName: filterName})
+		if err != nil {
+			return err
+		}
+		enabled = r.FilterEnabled
+		return nil
+	})
+	return enabled, err
+}
+
+func (or *OBSRemote) SetSourceFilterEnabled(ctx context.Context, sourceName string, filterName string, enabled bool) error {
+	return or.request(ctx, fmt.Sprintf("failed to set the state of filter %s of %s", filterName, sourceName), func(client *goobs.Client) error {
+		_, err := client.Filters.SetSourceFilterEnabled(&filters.SetSourceFilterEnabledParams{SourceName: sourceName, FilterName: filterName, FilterEnabled: &enabled})
+		return err
+	})
+}
+
+// SaveSourceScreenshot saves a screenshot of a source (or scene) to a file on the machine running OBS.
+// The width, height and quality are optional (0), the format is e.g. png or jpg.
+func (or *OBSRemote) SaveSourceScreenshot(ctx context.Context, sourceName string, filePath string, format string, width int, height int, quality int) error {
+	return or.request(ctx, fmt.Sprintf("failed to save screenshot of %s", sourceName), func(client *goobs.Client) error {
+		_, err := client.Sources.SaveSourceScreenshot(&sources.SaveSourceScreenshotParams{
+			SourceName:              sourceName,
+			ImageFilePath:           filePath,
+			ImageFormat:             format,
+			ImageWidth:              float64(width),
+			ImageHeight:             float64(height),
+			ImageCompressionQuality: float64(quality),
+		})
+		return err
+	})
+}
+
+// request calls [fn] with the client, and wraps its error with [failure].
+func (or *OBSRemote) request(ctx context.Context, failure string, fn func(client *goobs.Client) error) error {
+	if err := or.withClient(ctx, fn); err != nil {
+		return fmt.Errorf("%s: %w", failure, err)
+	}
+	return nil
+}
@@ -66,7 +66,8 @@ func (or *OBSRemote) Start(ctx context.Context) error {
 	if err := or.checkConnection(ctx); err != nil {
 		return err
 	}
-	go or.watchdog(ctx)
+	// Stop clears the quit channel, so the watchdog gets its own reference.
+	go or.watchdog(ctx, or.quit)
 	return nil
 }
 
@@ -99,7 +100,7 @@ func (or *OBSRemote) checkConnection(ctx context.Context) error {
 	return nil
 }
 
-func (or *OBSRemote) watchdog(ctx context.Context) {
+func (or *OBSRemote) watchdog(ctx context.Context, quit <-chan interface{}) {
 	for {
 		if or.cfg.Debug {
 			or.logger.Infof(ctx, "OBS remote checking connection...")
@@ -111,13 +112,40 @@ func (or *OBSRemote) watchdog(ctx context.Context) {
 		}
 
 		select {
-		case <-or.quit:
+		case <-quit:
 			return
 		case <-time.After(5 * time.Second):
 		}
 	}
 }
 
+// withClient calls [fn] with the client while holding the lock.
+// If [ctx] is done before [fn] returns, the context's error is returned right away, and [fn] finishes in the background.
+func (or *OBSRemote) withClient(ctx context.Context, fn func(client *goobs.Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		or.m.Lock()
+		defer or.m.Unlock()
+
+		if or.client == nil {
+			result <- fmt.Errorf("not connected")
+			return
+		}
+		result <- fn(or.client)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (or *OBSRemote) Notify() chan error {
 	return or.notify
 }
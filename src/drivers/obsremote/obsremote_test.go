@@ -0,0 +1,97 @@
+package obsremote_test
+
+import (
+	"context"
+	"testing"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/obsremote/standin"
+	"net.kopias.oscbridge/app/pkg/logger"
+)
+
+const password = "secret"
+
+// startServer starts a stand-in OBS on a random port, and closes it at the end of the test.
+func startServer(t *testing.T) *standin.Server {
+	t.Helper()
+
+	server, err := standin.NewServer("127.0.0.1:0", password, standin.DefaultState())
+	if err != nil {
+		t.Fatalf("failed to start the stand-in OBS: %s", err)
+	}
+	go func() {
+		// nolint:errcheck
+		server.Serve()
+	}()
+	t.Cleanup(func() {
+		// nolint:errcheck
+		server.Close()
+	})
+	return server
+}
+
+func newRemote(server *standin.Server, password string) *obsremote.OBSRemote {
+	return obsremote.NewOBSRemote(logger.New(), obsremote.Config{
+		Host:     "127.0.0.1",
+		Port:     int64(server.Addr().Port),
+		Password: password,
+	})
+}
+
+func TestRequests(t *testing.T) {
+	server := startServer(t)
+	ctx := context.Background()
+
+	remote := newRemote(server, password)
+	if err := remote.Start(ctx); err != nil {
+		t.Fatalf("failed to connect: %s", err)
+	}
+	defer remote.Stop(ctx)
+
+	scenes, err := remote.ListScenes(ctx)
+	if err != nil {
+		t.Fatalf("ListScenes failed: %s", err)
+	}
+	if len(scenes) != len(standin.DefaultState().Scenes) {
+		t.Errorf("ListScenes = %v, expected %d scenes", scenes, len(standin.DefaultState().Scenes))
+	}
+
+	if err := remote.SwitchProgramScene(ctx, "VIDEO"); err != nil {
+		t.Fatalf("SwitchProgramScene failed: %s", err)
+	}
+	if scene := server.GetState().ProgramScene; scene != "VIDEO" {
+		t.Errorf("the program scene is %s, expected VIDEO", scene)
+	}
+	if scene, err := remote.GetCurrentProgramScene(ctx); err != nil || scene != "VIDEO" {
+		t.Errorf("GetCurrentProgramScene = %s, %v, expected VIDEO", scene, err)
+	}
+
+	if err := remote.SetInputMute(ctx, "Mic/Aux", true); err != nil {
+		t.Fatalf("SetInputMute failed: %s", err)
+	}
+	if muted, err := remote.IsInputMuted(ctx, "Mic/Aux"); err != nil || !muted {
+		t.Errorf("IsInputMuted = %t, %v, expected true", muted, err)
+	}
+
+	if err := remote.StartRecording(ctx); err != nil {
+		t.Fatalf("StartRecording failed: %s", err)
+	}
+	if recording, err := remote.IsRecording(ctx); err != nil || !recording {
+		t.Errorf("IsRecording = %t, %v, expected true", recording, err)
+	}
+
+	// The failures of OBS are returned.
+	if err := remote.SwitchProgramScene(ctx, "MISSING"); err == nil {
+		t.Errorf("SwitchProgramScene succeeded with a missing scene")
+	}
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	server := startServer(t)
+
+	remote := newRemote(server, "wrong")
+	if err := remote.Start(context.Background()); err == nil {
+		remote.Stop(context.Background())
+		t.Fatalf("connected with a wrong password")
+	}
+}
@@ -0,0 +1,318 @@
+package standin
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	outputStarting = "OBS_WEBSOCKET_OUTPUT_STARTING"
+	outputStarted  = "OBS_WEBSOCKET_OUTPUT_STARTED"
+	outputStopping = "OBS_WEBSOCKET_OUTPUT_STOPPING"
+	outputStopped  = "OBS_WEBSOCKET_OUTPUT_STOPPED"
+
+	// recordingDirectory is only reported in the paths, the stand-in does not write recordings or replays.
+	recordingDirectory = "/tmp/obs-standin"
+)
+
+// output is the state of an output, and the event reporting its changes.
+type output struct {
+	active    func(s *State) *bool
+	eventType string
+	// path returns the file of the output, if it has one.
+	path func() string
+}
+
+var (
+	recordOutput = output{
+		active:    func(s *State) *bool { return &s.Recording },
+		eventType: "RecordStateChanged",
+		path:      func() string { return outputPath("Recording", "mkv") },
+	}
+	streamOutput = output{
+		active:    func(s *State) *bool { return &s.Streaming },
+		eventType: "StreamStateChanged",
+	}
+	replayBufferOutput = output{
+		active:    func(s *State) *bool { return &s.ReplayBuffer },
+		eventType: "ReplayBufferStateChanged",
+	}
+	virtualCamOutput = output{
+		active:    func(s *State) *bool { return &s.VirtualCam },
+		eventType: "VirtualcamStateChanged",
+	}
+)
+
+func outputPath(prefix string, extension string) string {
+	return fmt.Sprintf("%s/%s %s.%s", recordingDirectory, prefix, time.Now().Format("2006-01-02 15-04-05"), extension)
+}
+
+// start starts the output, emitting the starting and started states like OBS does.
+func (o output) start(s *Server) (map[string]interface{}, error) {
+	active := o.active(&s.state)
+	if *active {
+		return nil, requestError(StatusOutputRunning, "The output is already running.")
+	}
+
+	*active = true
+	s.emit(o.eventType, o.event(false, outputStarting, ""))
+	path := ""
+	if o.path != nil {
+		path = o.path()
+		s.outputPaths[o.eventType] = path
+	}
+	s.emit(o.eventType, o.event(true, outputStarted, path))
+	return nil, nil
+}
+
+// stop stops the output, emitting the stopping and stopped states like OBS does.
+func (o output) stop(s *Server) (map[string]interface{}, error) {
+	active := o.active(&s.state)
+	if !*active {
+		return nil, requestError(StatusOutputNotRunning, "The output is not running.")
+	}
+
+	*active = false
+	s.emit(o.eventType, o.event(true, outputStopping, ""))
+	if o.path == nil {
+		s.emit(o.eventType, o.event(false, outputStopped, ""))
+		return nil, nil
+	}
+
+	path := s.outputPaths[o.eventType]
+	s.emit(o.eventType, o.event(false, outputStopped, path))
+	return map[string]interface{}{"outputPath": path}, nil
+}
+
+func (o output) toggle(s *Server) (map[string]interface{}, error) {
+	var err error
+	if *o.active(&s.state) {
+		_, err = o.stop(s)
+	} else {
+		_, err = o.start(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"outputActive": *o.active(&s.state)}, nil
+}
+
+func (o output) event(active bool, state string, path string) map[string]interface{} {
+	data := map[string]interface{}{"outputActive": active, "outputState": state}
+	if o.path != nil {
+		var p interface{}
+		if path != "" {
+			p = path
+		}
+		data["outputPath"] = p
+	}
+	return data
+}
+
+func getRecordStatus(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"outputActive":   s.state.Recording,
+		"outputPaused":   false,
+		"outputTimecode": "00:00:00.000",
+		"outputDuration": 0,
+		"outputBytes":    0,
+	}, nil
+}
+
+func startRecord(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return recordOutput.start(s)
+}
+
+func stopRecord(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return recordOutput.stop(s)
+}
+
+func toggleRecord(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return recordOutput.toggle(s)
+}
+
+func getStreamStatus(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"outputActive":        s.state.Streaming,
+		"outputReconnecting":  false,
+		"outputTimecode":      "00:00:00.000",
+		"outputDuration":      0,
+		"outputCongestion":    0,
+		"outputBytes":         0,
+		"outputSkippedFrames": 0,
+		"outputTotalFrames":   0,
+	}, nil
+}
+
+func startStream(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return streamOutput.start(s)
+}
+
+func stopStream(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return streamOutput.stop(s)
+}
+
+func toggleStream(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return streamOutput.toggle(s)
+}
+
+func requireReplayBuffer(s *Server) error {
+	if !s.state.ReplayBufferEnabled {
+		return requestError(StatusInvalidResourceState, "Replay buffer is not available.")
+	}
+	return nil
+}
+
+func getReplayBufferStatus(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireReplayBuffer(s); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"outputActive": s.state.ReplayBuffer}, nil
+}
+
+func startReplayBuffer(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireReplayBuffer(s); err != nil {
+		return nil, err
+	}
+	return replayBufferOutput.start(s)
+}
+
+func stopReplayBuffer(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireReplayBuffer(s); err != nil {
+		return nil, err
+	}
+	return replayBufferOutput.stop(s)
+}
+
+func toggleReplayBuffer(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireReplayBuffer(s); err != nil {
+		return nil, err
+	}
+	return replayBufferOutput.toggle(s)
+}
+
+func saveReplayBuffer(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireReplayBuffer(s); err != nil {
+		return nil, err
+	}
+	if !s.state.ReplayBuffer {
+		return nil, requestError(StatusOutputNotRunning, "Replay buffer is not active.")
+	}
+
+	s.emit("ReplayBufferSaved", map[string]interface{}{"savedReplayPath": outputPath("Replay", "mkv")})
+	return nil, nil
+}
+
+func getVirtualCamStatus(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{"outputActive": s.state.VirtualCam}, nil
+}
+
+func startVirtualCam(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return virtualCamOutput.start(s)
+}
+
+func stopVirtualCam(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return virtualCamOutput.stop(s)
+}
+
+func toggleVirtualCam(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return virtualCamOutput.toggle(s)
+}
+
+// getMediaInput returns the media input named by the inputName of the request.
+func getMediaInput(s *Server, d RequestData) (*Input, error) {
+	input, err := getInput(s, d, false)
+	if err != nil {
+		return nil, err
+	}
+	if input.Media == nil {
+		return nil, requestError(StatusInvalidResourceType, "The specified input is not a media input.")
+	}
+
+	s.updateMediaCursor(input)
+	return input, nil
+}
+
+func getMediaInputStatus(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getMediaInput(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var duration, cursor interface{}
+	if input.Media.State != MediaStateNone {
+		duration, cursor = input.Media.DurationMillis, input.Media.CursorMillis
+	}
+
+	return map[string]interface{}{"mediaState": input.Media.State, "mediaDuration": duration, "mediaCursor": cursor}, nil
+}
+
+func setMediaInputCursor(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getMediaInput(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := d.Number("mediaCursor", 0, float64(input.Media.DurationMillis))
+	if err != nil {
+		return nil, err
+	}
+
+	input.Media.CursorMillis = int(cursor)
+	return nil, nil
+}
+
+func triggerMediaInputAction(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getMediaInput(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := d.String("mediaAction")
+	if err != nil {
+		return nil, err
+	}
+
+	media := input.Media
+	switch action {
+	case "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_PLAY":
+		if media.State == MediaStateEnded || media.State == MediaStateStopped {
+			media.CursorMillis = 0
+		}
+		s.playMedia(input)
+	case "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_RESTART":
+		media.CursorMillis = 0
+		if media.State == MediaStatePlaying {
+			s.playing[input.Name] = time.Now()
+		}
+		s.playMedia(input)
+	case "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_PAUSE":
+		if media.State == MediaStatePlaying {
+			media.State = MediaStatePaused
+			delete(s.playing, input.Name)
+		}
+	case "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_STOP":
+		if media.State == MediaStatePlaying || media.State == MediaStatePaused {
+			media.State, media.CursorMillis = MediaStateStopped, 0
+			delete(s.playing, input.Name)
+			s.emit("MediaInputPlaybackEnded", map[string]interface{}{"inputName": input.Name})
+		}
+	case "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_NONE", "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_NEXT", "OBS_WEBSOCKET_MEDIA_INPUT_ACTION_PREVIOUS":
+	default:
+		return nil, requestError(StatusInvalidRequestField, "You have specified an invalid media input action.")
+	}
+
+	s.emit("MediaInputActionTriggered", map[string]interface{}{"inputName": input.Name, "mediaAction": action})
+	return nil, nil
+}
+
+// playMedia starts (or resumes) playing a media input, the lock must be held.
+func (s *Server) playMedia(input *Input) {
+	if input.Media.State == MediaStatePlaying {
+		return
+	}
+
+	input.Media.State = MediaStatePlaying
+	s.playing[input.Name] = time.Now()
+	s.emit("MediaInputPlaybackStarted", map[string]interface{}{"inputName": input.Name})
+}
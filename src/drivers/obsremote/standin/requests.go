@@ -0,0 +1,306 @@
+package standin
+
+import (
+	"fmt"
+	"regexp"
+
+	"net.kopias.oscbridge/app/pkg/slicetools"
+)
+
+// The request status codes of the protocol.
+const (
+	StatusSuccess                 = 100
+	StatusUnknownRequestType      = 204
+	StatusMissingRequestField     = 300
+	StatusInvalidRequestField     = 400
+	StatusInvalidRequestFieldType = 401
+	StatusRequestFieldOutOfRange  = 402
+	StatusRequestFieldEmpty       = 403
+	StatusTooManyRequestFields    = 404
+	StatusOutputRunning           = 500
+	StatusOutputNotRunning        = 501
+	StatusStudioModeNotActive     = 506
+	StatusResourceNotFound        = 600
+	StatusResourceAlreadyExists   = 601
+	StatusInvalidResourceType     = 602
+	StatusInvalidResourceState    = 604
+	StatusResourceActionFailed    = 701
+	StatusRequestProcessingFailed = 702
+)
+
+// RequestError is a failed request, with the status code and comment of the response.
+type RequestError struct {
+	Code    int
+	Comment string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request failed (%d): %s", e.Code, e.Comment)
+}
+
+func requestError(code int, comment string, args ...interface{}) error {
+	return &RequestError{Code: code, Comment: fmt.Sprintf(comment, args...)}
+}
+
+// RequestData is the requestData of a request, with getters failing like OBS does for missing or invalid fields.
+type RequestData map[string]interface{}
+
+// String returns a required, non-empty string field.
+func (d RequestData) String(name string) (string, error) {
+	value, ok, err := d.OptionalString(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", requestError(StatusMissingRequestField, "Your request is missing the `%s` field.", name)
+	}
+	if value == "" {
+		return "", requestError(StatusRequestFieldEmpty, "The field value of `%s` cannot be empty.", name)
+	}
+	return value, nil
+}
+
+func (d RequestData) OptionalString(name string) (value string, ok bool, err error) {
+	raw, ok := d[name]
+	if !ok || raw == nil {
+		return "", false, nil
+	}
+	value, ok = raw.(string)
+	if !ok {
+		return "", false, requestError(StatusInvalidRequestFieldType, "The field value of `%s` must be a string.", name)
+	}
+	return value, true, nil
+}
+
+// Bool returns a required boolean field.
+func (d RequestData) Bool(name string) (bool, error) {
+	value, ok, err := d.OptionalBool(name)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, requestError(StatusMissingRequestField, "Your request is missing the `%s` field.", name)
+	}
+	return value, nil
+}
+
+func (d RequestData) OptionalBool(name string) (value bool, ok bool, err error) {
+	raw, ok := d[name]
+	if !ok || raw == nil {
+		return false, false, nil
+	}
+	value, ok = raw.(bool)
+	if !ok {
+		return false, false, requestError(StatusInvalidRequestFieldType, "The field value of `%s` must be boolean.", name)
+	}
+	return value, true, nil
+}
+
+// Number returns a required number field within [min] and [max].
+func (d RequestData) Number(name string, min float64, max float64) (float64, error) {
+	value, ok, err := d.OptionalNumber(name, min, max)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, requestError(StatusMissingRequestField, "Your request is missing the `%s` field.", name)
+	}
+	return value, nil
+}
+
+func (d RequestData) OptionalNumber(name string, min float64, max float64) (value float64, ok bool, err error) {
+	raw, ok := d[name]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		value = v
+	case int:
+		value = float64(v)
+	default:
+		return 0, false, requestError(StatusInvalidRequestFieldType, "The field value of `%s` must be a number.", name)
+	}
+
+	if value < min || value > max {
+		return 0, false, requestError(StatusRequestFieldOutOfRange, "The field value of `%s` is outside of the accepted range [%g, %g].", name, min, max)
+	}
+	return value, true, nil
+}
+
+// Object returns a required object field.
+func (d RequestData) Object(name string) (map[string]interface{}, error) {
+	raw, ok := d[name]
+	if !ok || raw == nil {
+		return nil, requestError(StatusMissingRequestField, "Your request is missing the `%s` field.", name)
+	}
+	value, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, requestError(StatusInvalidRequestFieldType, "The field value of `%s` must be an object.", name)
+	}
+	return value, nil
+}
+
+// builtinHandlers are the requests supported by the server.
+var builtinHandlers = map[string]handler{
+	"GetVersion":                 getVersion,
+	"BroadcastCustomEvent":       broadcastCustomEvent,
+	"CallVendorRequest":          callVendorRequest,
+	"GetHotkeyList":              getHotkeyList,
+	"TriggerHotkeyByName":        triggerHotkeyByName,
+	"TriggerHotkeyByKeySequence": triggerHotkeyByKeySequence,
+
+	"GetSceneList":           getSceneList,
+	"GetCurrentProgramScene": getCurrentProgramScene,
+	"SetCurrentProgramScene": setCurrentProgramScene,
+	"GetCurrentPreviewScene": getCurrentPreviewScene,
+	"SetCurrentPreviewScene": setCurrentPreviewScene,
+	"CreateScene":            createScene,
+	"RemoveScene":            removeScene,
+	"GetStudioModeEnabled":   getStudioModeEnabled,
+	"SetStudioModeEnabled":   setStudioModeEnabled,
+
+	"GetSceneTransitionList":            getSceneTransitionList,
+	"GetCurrentSceneTransition":         getCurrentSceneTransition,
+	"SetCurrentSceneTransition":         setCurrentSceneTransition,
+	"SetCurrentSceneTransitionDuration": setCurrentSceneTransitionDuration,
+	"TriggerStudioModeTransition":       triggerStudioModeTransition,
+
+	"GetInputList":           getInputList,
+	"GetInputMute":           getInputMute,
+	"SetInputMute":           setInputMute,
+	"ToggleInputMute":        toggleInputMute,
+	"GetInputVolume":         getInputVolume,
+	"SetInputVolume":         setInputVolume,
+	"GetInputSettings":       getInputSettings,
+	"SetInputSettings":       setInputSettings,
+	"GetSceneItemList":       getSceneItemList,
+	"GetSceneItemId":         getSceneItemID,
+	"GetSceneItemEnabled":    getSceneItemEnabled,
+	"SetSceneItemEnabled":    setSceneItemEnabled,
+	"GetSourceFilterList":    getSourceFilterList,
+	"GetSourceFilter":        getSourceFilter,
+	"SetSourceFilterEnabled": setSourceFilterEnabled,
+	"SaveSourceScreenshot":   saveSourceScreenshot,
+
+	"GetRecordStatus":       getRecordStatus,
+	"StartRecord":           startRecord,
+	"StopRecord":            stopRecord,
+	"ToggleRecord":          toggleRecord,
+	"GetStreamStatus":       getStreamStatus,
+	"StartStream":           startStream,
+	"StopStream":            stopStream,
+	"ToggleStream":          toggleStream,
+	"GetReplayBufferStatus": getReplayBufferStatus,
+	"StartReplayBuffer":     startReplayBuffer,
+	"StopReplayBuffer":      stopReplayBuffer,
+	"ToggleReplayBuffer":    toggleReplayBuffer,
+	"SaveReplayBuffer":      saveReplayBuffer,
+	"GetVirtualCamStatus":   getVirtualCamStatus,
+	"StartVirtualCam":       startVirtualCam,
+	"StopVirtualCam":        stopVirtualCam,
+	"ToggleVirtualCam":      toggleVirtualCam,
+
+	"GetMediaInputStatus":     getMediaInputStatus,
+	"SetMediaInputCursor":     setMediaInputCursor,
+	"TriggerMediaInputAction": triggerMediaInputAction,
+}
+
+// hotkeyActions are the hotkeys doing something in the stand-in, the rest are only accepted.
+var hotkeyActions = map[string]handler{
+	"OBSBasic.StartStreaming":    startStream,
+	"OBSBasic.StopStreaming":     stopStream,
+	"OBSBasic.StartRecording":    startRecord,
+	"OBSBasic.StopRecording":     stopRecord,
+	"OBSBasic.StartReplayBuffer": startReplayBuffer,
+	"OBSBasic.StopReplayBuffer":  stopReplayBuffer,
+	"OBSBasic.SaveReplayBuffer":  saveReplayBuffer,
+}
+
+func getVersion(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"obsVersion":            "30.0.0",
+		"obsWebSocketVersion":   Version,
+		"rpcVersion":            rpcVersion,
+		"availableRequests":     s.availableRequests(),
+		"supportedImageFormats": []string{"png", "jpg", "jpeg", "gif"},
+		"platform":              "linux",
+		"platformDescription":   "OSCBridge stand-in OBS",
+	}, nil
+}
+
+func broadcastCustomEvent(s *Server, d RequestData) (map[string]interface{}, error) {
+	eventData, err := d.Object("eventData")
+	if err != nil {
+		return nil, err
+	}
+
+	s.emit("CustomEvent", map[string]interface{}{"eventData": eventData})
+	return nil, nil
+}
+
+// callVendorRequest answers every vendor request by echoing its data.
+func callVendorRequest(_ *Server, d RequestData) (map[string]interface{}, error) {
+	vendorName, err := d.String("vendorName")
+	if err != nil {
+		return nil, err
+	}
+	requestType, err := d.String("requestType")
+	if err != nil {
+		return nil, err
+	}
+
+	responseData := d["requestData"]
+	if responseData == nil {
+		responseData = map[string]interface{}{}
+	}
+
+	return map[string]interface{}{"vendorName": vendorName, "requestType": requestType, "responseData": responseData}, nil
+}
+
+func getHotkeyList(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{"hotkeys": append([]string{}, s.state.Hotkeys...)}, nil
+}
+
+func triggerHotkeyByName(s *Server, d RequestData) (map[string]interface{}, error) {
+	hotkeyName, err := d.String("hotkeyName")
+	if err != nil {
+		return nil, err
+	}
+
+	if !slicetools.Contains(s.state.Hotkeys, hotkeyName) {
+		return nil, requestError(StatusResourceNotFound, "No hotkeys were found by that name.")
+	}
+
+	// Like in OBS, a hotkey not doing anything (e.g. starting an active output) is not an error.
+	if action, ok := hotkeyActions[hotkeyName]; ok {
+		_, _ = action(s, RequestData{})
+	}
+	return nil, nil
+}
+
+var keyIDRe = regexp.MustCompile(`^OBS_KEY_[A-Z0-9_]+$`)
+
+func triggerHotkeyByKeySequence(_ *Server, d RequestData) (map[string]interface{}, error) {
+	keyID, ok, err := d.OptionalString("keyId")
+	if err != nil {
+		return nil, err
+	}
+	if ok && !keyIDRe.MatchString(keyID) {
+		return nil, requestError(StatusInvalidRequestField, "The field value of `keyId` is not a valid key.")
+	}
+
+	if modifiers, ok := d["keyModifiers"]; ok {
+		m, ok := modifiers.(map[string]interface{})
+		if !ok {
+			return nil, requestError(StatusInvalidRequestFieldType, "The field value of `keyModifiers` must be an object.")
+		}
+		for _, name := range []string{"shift", "control", "alt", "command"} {
+			if _, _, err := RequestData(m).OptionalBool(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,235 @@
+package standin
+
+import (
+	"net.kopias.oscbridge/app/pkg/slicetools"
+)
+
+// getScene returns the scene named by the [field] of the request.
+func getScene(s *Server, d RequestData, field string) (*Scene, error) {
+	name, err := d.String(field)
+	if err != nil {
+		return nil, err
+	}
+
+	scene := s.state.scene(name)
+	if scene == nil {
+		return nil, requestError(StatusResourceNotFound, "No source was found by the name of `%s`.", name)
+	}
+	return scene, nil
+}
+
+func requireStudioMode(s *Server) error {
+	if !s.state.StudioMode {
+		return requestError(StatusStudioModeNotActive, "Studio mode is not enabled.")
+	}
+	return nil
+}
+
+func (s *Server) sceneList() []map[string]interface{} {
+	scenes := []map[string]interface{}{}
+	for i, scene := range s.state.Scenes {
+		scenes = append(scenes, map[string]interface{}{"sceneName": scene.Name, "sceneIndex": i})
+	}
+	return scenes
+}
+
+func getSceneList(s *Server, _ RequestData) (map[string]interface{}, error) {
+	var preview interface{}
+	if s.state.StudioMode {
+		preview = s.state.PreviewScene
+	}
+
+	return map[string]interface{}{
+		"currentProgramSceneName": s.state.ProgramScene,
+		"currentPreviewSceneName": preview,
+		"scenes":                  s.sceneList(),
+	}, nil
+}
+
+func getCurrentProgramScene(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{"currentProgramSceneName": s.state.ProgramScene, "sceneName": s.state.ProgramScene}, nil
+}
+
+func setCurrentProgramScene(s *Server, d RequestData) (map[string]interface{}, error) {
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	s.state.ProgramScene = scene.Name
+	s.emit("CurrentProgramSceneChanged", map[string]interface{}{"sceneName": scene.Name})
+	return nil, nil
+}
+
+func getCurrentPreviewScene(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireStudioMode(s); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"currentPreviewSceneName": s.state.PreviewScene, "sceneName": s.state.PreviewScene}, nil
+}
+
+func setCurrentPreviewScene(s *Server, d RequestData) (map[string]interface{}, error) {
+	if err := requireStudioMode(s); err != nil {
+		return nil, err
+	}
+
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	s.state.PreviewScene = scene.Name
+	s.emit("CurrentPreviewSceneChanged", map[string]interface{}{"sceneName": scene.Name})
+	return nil, nil
+}
+
+func createScene(s *Server, d RequestData) (map[string]interface{}, error) {
+	name, err := d.String("sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	if s.state.scene(name) != nil || s.state.input(name) != nil {
+		return nil, requestError(StatusResourceAlreadyExists, "A source already exists by that scene name.")
+	}
+
+	s.state.Scenes = append(s.state.Scenes, Scene{Name: name, Items: []SceneItem{}, Filters: []Filter{}})
+	s.emit("SceneCreated", map[string]interface{}{"sceneName": name, "isGroup": false})
+	s.emit("SceneListChanged", map[string]interface{}{"scenes": s.sceneList()})
+	return nil, nil
+}
+
+func removeScene(s *Server, d RequestData) (map[string]interface{}, error) {
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.state.Scenes) == 1 {
+		return nil, requestError(StatusResourceActionFailed, "The last scene can not be removed.")
+	}
+
+	name := scene.Name
+	s.state.Scenes = slicetools.Filter(s.state.Scenes, func(scene Scene) bool { return scene.Name != name })
+	s.emit("SceneRemoved", map[string]interface{}{"sceneName": name, "isGroup": false})
+	s.emit("SceneListChanged", map[string]interface{}{"scenes": s.sceneList()})
+
+	if s.state.ProgramScene == name {
+		s.state.ProgramScene = s.state.Scenes[0].Name
+		s.emit("CurrentProgramSceneChanged", map[string]interface{}{"sceneName": s.state.ProgramScene})
+	}
+	if s.state.PreviewScene == name {
+		s.state.PreviewScene = s.state.Scenes[0].Name
+		if s.state.StudioMode {
+			s.emit("CurrentPreviewSceneChanged", map[string]interface{}{"sceneName": s.state.PreviewScene})
+		}
+	}
+	return nil, nil
+}
+
+func getStudioModeEnabled(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{"studioModeEnabled": s.state.StudioMode}, nil
+}
+
+func setStudioModeEnabled(s *Server, d RequestData) (map[string]interface{}, error) {
+	enabled, err := d.Bool("studioModeEnabled")
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled == s.state.StudioMode {
+		return nil, nil
+	}
+
+	s.state.StudioMode = enabled
+	s.emit("StudioModeStateChanged", map[string]interface{}{"studioModeEnabled": enabled})
+
+	if enabled {
+		s.state.PreviewScene = s.state.ProgramScene
+		s.emit("CurrentPreviewSceneChanged", map[string]interface{}{"sceneName": s.state.PreviewScene})
+	}
+	return nil, nil
+}
+
+func transitionKind(name string) string {
+	if name == "Cut" {
+		return "cut_transition"
+	}
+	return "fade_transition"
+}
+
+func getSceneTransitionList(s *Server, _ RequestData) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"currentSceneTransitionName": s.state.CurrentTransition,
+		"currentSceneTransitionKind": transitionKind(s.state.CurrentTransition),
+		"transitions": slicetools.Map(s.state.Transitions, func(name string) map[string]interface{} {
+			return map[string]interface{}{
+				"transitionName":         name,
+				"transitionKind":         transitionKind(name),
+				"transitionFixed":        name == "Cut",
+				"transitionConfigurable": false,
+			}
+		}),
+	}, nil
+}
+
+func getCurrentSceneTransition(s *Server, _ RequestData) (map[string]interface{}, error) {
+	name := s.state.CurrentTransition
+
+	var duration interface{}
+	if name != "Cut" {
+		duration = s.state.TransitionDurationMillis
+	}
+
+	return map[string]interface{}{
+		"transitionName":         name,
+		"transitionKind":         transitionKind(name),
+		"transitionFixed":        name == "Cut",
+		"transitionDuration":     duration,
+		"transitionConfigurable": false,
+		"transitionSettings":     map[string]interface{}{},
+	}, nil
+}
+
+func setCurrentSceneTransition(s *Server, d RequestData) (map[string]interface{}, error) {
+	name, err := d.String("transitionName")
+	if err != nil {
+		return nil, err
+	}
+
+	if !slicetools.Contains(s.state.Transitions, name) {
+		return nil, requestError(StatusResourceNotFound, "No transition was found by that name.")
+	}
+
+	s.state.CurrentTransition = name
+	s.emit("CurrentSceneTransitionChanged", map[string]interface{}{"transitionName": name})
+	return nil, nil
+}
+
+func setCurrentSceneTransitionDuration(s *Server, d RequestData) (map[string]interface{}, error) {
+	duration, err := d.Number("transitionDuration", 50, 20000)
+	if err != nil {
+		return nil, err
+	}
+
+	s.state.TransitionDurationMillis = int(duration)
+	s.emit("CurrentSceneTransitionDurationChanged", map[string]interface{}{"transitionDuration": int(duration)})
+	return nil, nil
+}
+
+// triggerStudioModeTransition swaps the program and the preview scenes instantly.
+func triggerStudioModeTransition(s *Server, _ RequestData) (map[string]interface{}, error) {
+	if err := requireStudioMode(s); err != nil {
+		return nil, err
+	}
+
+	transition := map[string]interface{}{"transitionName": s.state.CurrentTransition}
+	s.emit("SceneTransitionStarted", transition)
+
+	s.state.ProgramScene, s.state.PreviewScene = s.state.PreviewScene, s.state.ProgramScene
+	s.emit("CurrentProgramSceneChanged", map[string]interface{}{"sceneName": s.state.ProgramScene})
+	s.emit("CurrentPreviewSceneChanged", map[string]interface{}{"sceneName": s.state.PreviewScene})
+
+	s.emit("SceneTransitionEnded", transition)
+	return nil, nil
+}
@@ -0,0 +1,569 @@
+// Package standin is a stand-in OBS speaking the OBS WebSocket v5 protocol, to test the obs connections, bridges and
+// tasks without a running OBS.
+// It supports the authentication, requests, request batches and events. It keeps the scenes, inputs, outputs, etc.
+// in memory, executes the requests used by obsremote (and a few more) instantly, and emits the corresponding events.
+//
+// In tests:
+//
+//	server, _ := standin.NewServer("127.0.0.1:0", "password", standin.DefaultState())
+//	go server.Serve()
+//	defer server.Close()
+//	remote := obsremote.NewOBSRemote(log, obsremote.Config{Host: "127.0.0.1", Port: int64(server.Addr().Port), Password: "password"})
+package standin
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Version is reported as the obs-websocket version.
+	Version    = "5.3.0"
+	rpcVersion = 1
+
+	opHello                = 0
+	opIdentify             = 1
+	opIdentified           = 2
+	opReidentify           = 3
+	opEvent                = 5
+	opRequest              = 6
+	opRequestResponse      = 7
+	opRequestBatch         = 8
+	opRequestBatchResponse = 9
+
+	closeMissingDataField      = 4003
+	closeUnknownOpCode         = 4006
+	closeNotIdentified         = 4007
+	closeAlreadyIdentified     = 4008
+	closeAuthenticationFailed  = 4009
+	closeUnsupportedRPCVersion = 4010
+
+	// subscriptionAll is the default event subscription, all the non high-volume events.
+	subscriptionAll            = 0x7FF
+	mediaAdvanceIntervalMillis = 100
+)
+
+// eventSubscriptions maps the emitted events to their subscription bits.
+var eventSubscriptions = map[string]int{
+	"ExitStarted":                           1 << 0,
+	"CustomEvent":                           1 << 0,
+	"VendorEvent":                           1 << 9,
+	"SceneCreated":                          1 << 2,
+	"SceneRemoved":                          1 << 2,
+	"SceneListChanged":                      1 << 2,
+	"CurrentProgramSceneChanged":            1 << 2,
+	"CurrentPreviewSceneChanged":            1 << 2,
+	"InputMuteStateChanged":                 1 << 3,
+	"InputVolumeChanged":                    1 << 3,
+	"CurrentSceneTransitionChanged":         1 << 4,
+	"CurrentSceneTransitionDurationChanged": 1 << 4,
+	"SceneTransitionStarted":                1 << 4,
+	"SceneTransitionEnded":                  1 << 4,
+	"SourceFilterEnableStateChanged":        1 << 5,
+	"RecordStateChanged":                    1 << 6,
+	"StreamStateChanged":                    1 << 6,
+	"ReplayBufferStateChanged":              1 << 6,
+	"ReplayBufferSaved":                     1 << 6,
+	"VirtualcamStateChanged":                1 << 6,
+	"SceneItemEnableStateChanged":           1 << 7,
+	"MediaInputPlaybackStarted":             1 << 8,
+	"MediaInputPlaybackEnded":               1 << 8,
+	"MediaInputActionTriggered":             1 << 8,
+	"StudioModeStateChanged":                1 << 10,
+}
+
+// Event is an event emitted to the identified clients subscribed to it.
+type Event struct {
+	Type string
+	Data map[string]interface{}
+}
+
+// RequestHandler answers a request of a given type, it may change the state, and return events to be emitted.
+// Returning a *RequestError sets the status code of the response.
+type RequestHandler func(state *State, data RequestData) (response map[string]interface{}, events []Event, err error)
+
+// handler is the internal form of the request handlers, it is called while holding the lock.
+type handler func(s *Server, data RequestData) (map[string]interface{}, error)
+
+// Server is a stand-in OBS listening on TCP.
+type Server struct {
+	listener   net.Listener
+	httpServer *http.Server
+	upgrader   websocket.Upgrader
+	password   string
+
+	m        *sync.Mutex
+	state    State
+	handlers map[string]handler
+	pending  []Event
+	clients  map[*client]struct{}
+	playing  map[string]time.Time
+	// outputPaths are the files of the active outputs, by their event type.
+	outputPaths map[string]string
+
+	quit      chan interface{}
+	closeOnce *sync.Once
+
+	// OnRequest is called after each executed request with the status code of its result, if set.
+	OnRequest func(requestType string, data RequestData, code int)
+}
+
+type client struct {
+	conn          *websocket.Conn
+	m             *sync.Mutex
+	identified    bool
+	subscriptions int
+}
+
+// NewServer starts listening on [address], e.g. "127.0.0.1:4455", or "127.0.0.1:0" for a random port.
+// If [password] is not empty, the clients have to authenticate with it.
+func NewServer(address string, password string, state State) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener:    listener,
+		password:    password,
+		m:           &sync.Mutex{},
+		state:       state.clone(),
+		handlers:    map[string]handler{},
+		clients:     map[*client]struct{}{},
+		playing:     map[string]time.Time{},
+		outputPaths: map[string]string{},
+		quit:        make(chan interface{}),
+		closeOnce:   &sync.Once{},
+	}
+	s.upgrader = websocket.Upgrader{
+		Subprotocols: []string{"obswebsocket.json"},
+		CheckOrigin:  func(r *http.Request) bool { return true },
+	}
+	s.httpServer = &http.Server{Handler: http.HandlerFunc(s.serveWebsocket), ReadHeaderTimeout: 5 * time.Second}
+
+	for requestType, h := range builtinHandlers {
+		s.handlers[requestType] = h
+	}
+
+	for _, input := range s.state.Inputs {
+		if input.Media != nil && input.Media.State == MediaStatePlaying {
+			s.playing[input.Name] = time.Now()
+		}
+	}
+
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() *net.TCPAddr {
+	// nolint:forcetypeassert
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Serve answers the incoming connections until the server is closed.
+func (s *Server) Serve() error {
+	go s.advanceMedia()
+
+	err := s.httpServer.Serve(s.listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Close emits ExitStarted, then closes the connections and the listener.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.EmitEvent("ExitStarted", nil)
+		close(s.quit)
+
+		s.m.Lock()
+		for c := range s.clients {
+			c.close(websocket.CloseGoingAway, "server stopping")
+		}
+		s.m.Unlock()
+
+		err = s.httpServer.Close()
+	})
+	return err
+}
+
+// GetState returns a copy of the current state.
+func (s *Server) GetState() State {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.state.clone()
+}
+
+// UpdateState changes the state with [fn] without emitting events, e.g. to prepare a test case.
+func (s *Server) UpdateState(fn func(state *State)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	fn(&s.state)
+}
+
+// Handle replaces (or adds) the handler of a request type.
+func (s *Server) Handle(requestType string, requestHandler RequestHandler) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.handlers[requestType] = func(s *Server, data RequestData) (map[string]interface{}, error) {
+		response, events, err := requestHandler(&s.state, data)
+		s.pending = append(s.pending, events...)
+		return response, err
+	}
+}
+
+// Execute executes a request as if it was done in OBS itself, e.g. switching the scene by hand, and emits the events.
+func (s *Server) Execute(requestType string, data map[string]interface{}) (map[string]interface{}, error) {
+	response, code, comment := s.execute(requestType, data)
+	if code != StatusSuccess {
+		return nil, &RequestError{Code: code, Comment: comment}
+	}
+	return response, nil
+}
+
+// EmitEvent emits an event to the clients subscribed to it, events unknown to the server are sent to every client.
+func (s *Server) EmitEvent(eventType string, data map[string]interface{}) {
+	s.broadcast([]Event{{Type: eventType, Data: data}})
+}
+
+// emit queues an event to be sent after the current request, the lock must be held.
+func (s *Server) emit(eventType string, data map[string]interface{}) {
+	s.pending = append(s.pending, Event{Type: eventType, Data: data})
+}
+
+func (s *Server) execute(requestType string, data map[string]interface{}) (map[string]interface{}, int, string) {
+	s.m.Lock()
+	h, ok := s.handlers[requestType]
+
+	var response map[string]interface{}
+	code, comment := StatusSuccess, ""
+
+	if !ok {
+		code, comment = StatusUnknownRequestType, fmt.Sprintf("Your request type `%s` is not valid.", requestType)
+	} else {
+		var err error
+		if response, err = h(s, data); err != nil {
+			var requestErr *RequestError
+			if !errors.As(err, &requestErr) {
+				requestErr = &RequestError{Code: StatusRequestProcessingFailed, Comment: err.Error()}
+			}
+			code, comment = requestErr.Code, requestErr.Comment
+			response = nil
+		}
+	}
+
+	events := s.pending
+	s.pending = nil
+	s.m.Unlock()
+
+	if s.OnRequest != nil {
+		s.OnRequest(requestType, data, code)
+	}
+
+	s.broadcast(events)
+	return response, code, comment
+}
+
+func (s *Server) broadcast(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	s.m.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.m.Unlock()
+
+	for _, event := range events {
+		subscription, known := eventSubscriptions[event.Type]
+		for _, c := range clients {
+			c.m.Lock()
+			send := c.identified && (!known || c.subscriptions&subscription != 0)
+			c.m.Unlock()
+
+			if send {
+				c.send(opEvent, map[string]interface{}{
+					"eventType":   event.Type,
+					"eventIntent": subscription,
+					"eventData":   event.Data,
+				})
+			}
+		}
+	}
+}
+
+// advanceMedia ends the playing media inputs when their cursor reaches their duration.
+func (s *Server) advanceMedia() {
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(mediaAdvanceIntervalMillis * time.Millisecond):
+		}
+
+		s.m.Lock()
+		for name := range s.playing {
+			if input := s.state.input(name); input != nil && input.Media != nil {
+				s.updateMediaCursor(input)
+			}
+		}
+		events := s.pending
+		s.pending = nil
+		s.m.Unlock()
+
+		s.broadcast(events)
+	}
+}
+
+// updateMediaCursor moves the cursor of a playing media input, and ends it at its duration. The lock must be held.
+func (s *Server) updateMediaCursor(input *Input) {
+	since, ok := s.playing[input.Name]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	input.Media.CursorMillis += int(now.Sub(since).Milliseconds())
+	s.playing[input.Name] = now
+
+	if input.Media.CursorMillis >= input.Media.DurationMillis {
+		input.Media.CursorMillis = input.Media.DurationMillis
+		input.Media.State = MediaStateEnded
+		delete(s.playing, input.Name)
+		s.emit("MediaInputPlaybackEnded", map[string]interface{}{"inputName": input.Name})
+	}
+}
+
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &client{conn: conn, m: &sync.Mutex{}}
+
+	s.m.Lock()
+	s.clients[c] = struct{}{}
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		delete(s.clients, c)
+		s.m.Unlock()
+		_ = conn.Close()
+	}()
+
+	challenge, salt := randomString(), randomString()
+
+	hello := map[string]interface{}{
+		"obsWebSocketVersion": Version,
+		"rpcVersion":          rpcVersion,
+	}
+	if s.password != "" {
+		hello["authentication"] = map[string]interface{}{"challenge": challenge, "salt": salt}
+	}
+	c.send(opHello, hello)
+
+	for {
+		var message struct {
+			Op *int            `json:"op"`
+			D  json.RawMessage `json:"d"`
+		}
+		if err := conn.ReadJSON(&message); err != nil {
+			return
+		}
+
+		if message.Op == nil || message.D == nil {
+			c.close(closeMissingDataField, "Your payload is missing an `op` or `d`.")
+			return
+		}
+
+		if !s.handleMessage(c, *message.Op, message.D, challenge, salt) {
+			return
+		}
+	}
+}
+
+// handleMessage handles a message of a client, and returns false if the connection has been closed.
+func (s *Server) handleMessage(c *client, op int, d json.RawMessage, challenge string, salt string) bool {
+	c.m.Lock()
+	identified := c.identified
+	c.m.Unlock()
+
+	if !identified && op != opIdentify {
+		c.close(closeNotIdentified, "You attempted to send a non-Identify message while not identified.")
+		return false
+	}
+
+	switch op {
+	case opIdentify:
+		var identify struct {
+			RPCVersion         int    `json:"rpcVersion"`
+			Authentication     string `json:"authentication"`
+			EventSubscriptions *int   `json:"eventSubscriptions"`
+		}
+		if err := json.Unmarshal(d, &identify); err != nil {
+			c.close(closeMissingDataField, err.Error())
+			return false
+		}
+
+		switch {
+		case identified:
+			c.close(closeAlreadyIdentified, "You are already Identified with the obs-websocket server.")
+			return false
+		case identify.RPCVersion != rpcVersion:
+			c.close(closeUnsupportedRPCVersion, fmt.Sprintf("Your requested RPC version (%d) is not supported by this server.", identify.RPCVersion))
+			return false
+		case s.password != "" && identify.Authentication != authenticationString(s.password, salt, challenge):
+			c.close(closeAuthenticationFailed, "Authentication failed.")
+			return false
+		}
+
+		c.m.Lock()
+		c.identified = true
+		c.subscriptions = subscriptionAll
+		if identify.EventSubscriptions != nil {
+			c.subscriptions = *identify.EventSubscriptions
+		}
+		c.m.Unlock()
+
+		c.send(opIdentified, map[string]interface{}{"negotiatedRpcVersion": rpcVersion})
+
+	case opReidentify:
+		var reidentify struct {
+			EventSubscriptions *int `json:"eventSubscriptions"`
+		}
+		_ = json.Unmarshal(d, &reidentify)
+
+		if reidentify.EventSubscriptions != nil {
+			c.m.Lock()
+			c.subscriptions = *reidentify.EventSubscriptions
+			c.m.Unlock()
+		}
+
+		c.send(opIdentified, map[string]interface{}{"negotiatedRpcVersion": rpcVersion})
+
+	case opRequest:
+		var request struct {
+			RequestType string                 `json:"requestType"`
+			RequestID   string                 `json:"requestId"`
+			RequestData map[string]interface{} `json:"requestData"`
+		}
+		if err := json.Unmarshal(d, &request); err != nil {
+			c.close(closeMissingDataField, err.Error())
+			return false
+		}
+
+		c.send(opRequestResponse, s.response(request.RequestType, request.RequestID, request.RequestData))
+
+	case opRequestBatch:
+		var batch struct {
+			RequestID     string `json:"requestId"`
+			HaltOnFailure bool   `json:"haltOnFailure"`
+			Requests      []struct {
+				RequestType string                 `json:"requestType"`
+				RequestID   string                 `json:"requestId"`
+				RequestData map[string]interface{} `json:"requestData"`
+			} `json:"requests"`
+		}
+		if err := json.Unmarshal(d, &batch); err != nil {
+			c.close(closeMissingDataField, err.Error())
+			return false
+		}
+
+		results := []map[string]interface{}{}
+		for _, request := range batch.Requests {
+			result := s.response(request.RequestType, request.RequestID, request.RequestData)
+			results = append(results, result)
+
+			// nolint:forcetypeassert
+			if batch.HaltOnFailure && !result["requestStatus"].(map[string]interface{})["result"].(bool) {
+				break
+			}
+		}
+
+		c.send(opRequestBatchResponse, map[string]interface{}{"requestId": batch.RequestID, "results": results})
+
+	default:
+		c.close(closeUnknownOpCode, fmt.Sprintf("Unknown OpCode: %d", op))
+		return false
+	}
+
+	return true
+}
+
+// response executes a request, and returns the data of its response message.
+func (s *Server) response(requestType string, requestID string, data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	response, code, comment := s.execute(requestType, data)
+
+	status := map[string]interface{}{"result": code == StatusSuccess, "code": code}
+	if comment != "" {
+		status["comment"] = comment
+	}
+
+	message := map[string]interface{}{
+		"requestType":   requestType,
+		"requestId":     requestID,
+		"requestStatus": status,
+	}
+	if response != nil {
+		message["responseData"] = response
+	}
+	return message
+}
+
+// availableRequests returns the sorted list of the handled request types.
+func (s *Server) availableRequests() []string {
+	var types []string
+	for requestType := range s.handlers {
+		types = append(types, requestType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (c *client) send(op int, d interface{}) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	_ = c.conn.WriteJSON(map[string]interface{}{"op": op, "d": d})
+}
+
+func (c *client) close(code int, reason string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	_ = c.conn.Close()
+}
+
+// authenticationString returns the expected authentication string of the Identify message.
+func authenticationString(password string, salt string, challenge string) string {
+	secret := sha256.Sum256([]byte(password + salt))
+	auth := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(secret[:]) + challenge))
+	return base64.StdEncoding.EncodeToString(auth[:])
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
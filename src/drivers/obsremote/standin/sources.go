@@ -0,0 +1,461 @@
+package standin
+
+import (
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	screenshotDefaultWidth  = 1920
+	screenshotDefaultHeight = 1080
+)
+
+var versionedKindRe = regexp.MustCompile(`_v\d+$`)
+
+// getInput returns the input named by the inputName of the request, [audio] requires it to have an audio track.
+func getInput(s *Server, d RequestData, audio bool) (*Input, error) {
+	name, err := d.String("inputName")
+	if err != nil {
+		return nil, err
+	}
+
+	input := s.state.input(name)
+	if input == nil {
+		return nil, requestError(StatusResourceNotFound, "No source was found by the name of `%s`.", name)
+	}
+
+	if audio && !input.Audio {
+		return nil, requestError(StatusInvalidResourceType, "The specified input does not support audio.")
+	}
+	return input, nil
+}
+
+func getInputList(s *Server, d RequestData) (map[string]interface{}, error) {
+	kind, _, err := d.OptionalString("inputKind")
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := []map[string]interface{}{}
+	for _, input := range s.state.Inputs {
+		if kind != "" && input.Kind != kind {
+			continue
+		}
+		inputs = append(inputs, map[string]interface{}{
+			"inputName":            input.Name,
+			"inputKind":            input.Kind,
+			"unversionedInputKind": versionedKindRe.ReplaceAllString(input.Kind, ""),
+		})
+	}
+
+	return map[string]interface{}{"inputs": inputs}, nil
+}
+
+func getInputMute(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, true)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"inputMuted": input.Muted}, nil
+}
+
+func setInputMute(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, true)
+	if err != nil {
+		return nil, err
+	}
+
+	muted, err := d.Bool("inputMuted")
+	if err != nil {
+		return nil, err
+	}
+
+	s.setMuted(input, muted)
+	return nil, nil
+}
+
+func toggleInputMute(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setMuted(input, !input.Muted)
+	return map[string]interface{}{"inputMuted": input.Muted}, nil
+}
+
+func (s *Server) setMuted(input *Input, muted bool) {
+	if input.Muted == muted {
+		return
+	}
+	input.Muted = muted
+	s.emit("InputMuteStateChanged", map[string]interface{}{"inputName": input.Name, "inputMuted": muted})
+}
+
+// mulToDb converts a volume multiplier to dB, like OBS, with -100 dB for the silence.
+func mulToDb(mul float64) float64 {
+	if mul <= 0 {
+		return -100
+	}
+	return 20 * math.Log10(mul)
+}
+
+func dbToMul(db float64) float64 {
+	if db <= -100 {
+		return 0
+	}
+	return math.Pow(10, db/20)
+}
+
+func getInputVolume(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, true)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"inputVolumeMul": input.VolumeMul, "inputVolumeDb": mulToDb(input.VolumeMul)}, nil
+}
+
+func setInputVolume(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, true)
+	if err != nil {
+		return nil, err
+	}
+
+	mul, hasMul, err := d.OptionalNumber("inputVolumeMul", 0, 20)
+	if err != nil {
+		return nil, err
+	}
+	db, hasDb, err := d.OptionalNumber("inputVolumeDb", -100, 26)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case hasMul && hasDb:
+		return nil, requestError(StatusTooManyRequestFields, "You may only specify one volume field.")
+	case hasDb:
+		mul = dbToMul(db)
+	case !hasMul:
+		return nil, requestError(StatusMissingRequestField, "You must specify one volume field.")
+	}
+
+	input.VolumeMul = mul
+	s.emit("InputVolumeChanged", map[string]interface{}{"inputName": input.Name, "inputVolumeMul": mul, "inputVolumeDb": mulToDb(mul)})
+	return nil, nil
+}
+
+func getInputSettings(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, false)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]interface{}{}
+	for key, value := range input.Settings {
+		settings[key] = value
+	}
+	return map[string]interface{}{"inputSettings": settings, "inputKind": input.Kind}, nil
+}
+
+func setInputSettings(s *Server, d RequestData) (map[string]interface{}, error) {
+	input, err := getInput(s, d, false)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := d.Object("inputSettings")
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, ok, err := d.OptionalBool("overlay")
+	if err != nil {
+		return nil, err
+	}
+
+	if (ok && !overlay) || input.Settings == nil {
+		input.Settings = map[string]interface{}{}
+	}
+	for key, value := range settings {
+		input.Settings[key] = value
+	}
+	return nil, nil
+}
+
+// getSceneItem returns the scene and the item identified by the sceneName and sceneItemId of the request.
+func getSceneItem(s *Server, d RequestData) (*Scene, *SceneItem, error) {
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id, err := d.Number("sceneItemId", 0, math.MaxInt32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range scene.Items {
+		if scene.Items[i].ID == int(id) {
+			return scene, &scene.Items[i], nil
+		}
+	}
+	return nil, nil, requestError(StatusResourceNotFound, "No scene items were found in the specified scene by that ID.")
+}
+
+func getSceneItemList(s *Server, d RequestData) (map[string]interface{}, error) {
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	items := []map[string]interface{}{}
+	for i, item := range scene.Items {
+		sourceType, kind := "OBS_SOURCE_TYPE_SCENE", interface{}(nil)
+		if input := s.state.input(item.SourceName); input != nil {
+			sourceType, kind = "OBS_SOURCE_TYPE_INPUT", input.Kind
+		}
+
+		items = append(items, map[string]interface{}{
+			"sceneItemId":        item.ID,
+			"sceneItemIndex":     i,
+			"sceneItemEnabled":   item.Enabled,
+			"sceneItemLocked":    false,
+			"sceneItemBlendMode": "OBS_BLEND_NORMAL",
+			"sourceName":         item.SourceName,
+			"sourceType":         sourceType,
+			"inputKind":          kind,
+			"isGroup":            nil,
+		})
+	}
+
+	return map[string]interface{}{"sceneItems": items}, nil
+}
+
+func getSceneItemID(s *Server, d RequestData) (map[string]interface{}, error) {
+	scene, err := getScene(s, d, "sceneName")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceName, err := d.String("sourceName")
+	if err != nil {
+		return nil, err
+	}
+
+	offset, _, err := d.OptionalNumber("searchOffset", -1, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []int
+	for _, item := range scene.Items {
+		if item.SourceName == sourceName {
+			matches = append(matches, item.ID)
+		}
+	}
+
+	index := int(offset)
+	if index == -1 && len(matches) > 0 {
+		index = len(matches) - 1
+	}
+	if index < 0 || index >= len(matches) {
+		return nil, requestError(StatusResourceNotFound, "No scene items were found in the specified scene by that name or offset.")
+	}
+
+	return map[string]interface{}{"sceneItemId": matches[index]}, nil
+}
+
+func getSceneItemEnabled(s *Server, d RequestData) (map[string]interface{}, error) {
+	_, item, err := getSceneItem(s, d)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"sceneItemEnabled": item.Enabled}, nil
+}
+
+func setSceneItemEnabled(s *Server, d RequestData) (map[string]interface{}, error) {
+	scene, item, err := getSceneItem(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := d.Bool("sceneItemEnabled")
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Enabled != enabled {
+		item.Enabled = enabled
+		s.emit("SceneItemEnableStateChanged", map[string]interface{}{"sceneName": scene.Name, "sceneItemId": item.ID, "sceneItemEnabled": enabled})
+	}
+	return nil, nil
+}
+
+// getFilter returns the filter identified by the sourceName and filterName of the request.
+func getFilter(s *Server, d RequestData) (string, int, *Filter, error) {
+	sourceName, err := d.String("sourceName")
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	filterName, err := d.String("filterName")
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	filters, ok := s.state.filters(sourceName)
+	if !ok {
+		return "", 0, nil, requestError(StatusResourceNotFound, "No source was found by the name of `%s`.", sourceName)
+	}
+
+	for i := range *filters {
+		if (*filters)[i].Name == filterName {
+			return sourceName, i, &(*filters)[i], nil
+		}
+	}
+	return "", 0, nil, requestError(StatusResourceNotFound, "No filter was found in the source `%s` with the name `%s`.", sourceName, filterName)
+}
+
+func getSourceFilterList(s *Server, d RequestData) (map[string]interface{}, error) {
+	sourceName, err := d.String("sourceName")
+	if err != nil {
+		return nil, err
+	}
+
+	filters, ok := s.state.filters(sourceName)
+	if !ok {
+		return nil, requestError(StatusResourceNotFound, "No source was found by the name of `%s`.", sourceName)
+	}
+
+	list := []map[string]interface{}{}
+	for i, filter := range *filters {
+		list = append(list, map[string]interface{}{
+			"filterName":     filter.Name,
+			"filterKind":     filter.Kind,
+			"filterIndex":    i,
+			"filterEnabled":  filter.Enabled,
+			"filterSettings": map[string]interface{}{},
+		})
+	}
+
+	return map[string]interface{}{"filters": list}, nil
+}
+
+func getSourceFilter(s *Server, d RequestData) (map[string]interface{}, error) {
+	_, index, filter, err := getFilter(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"filterEnabled":  filter.Enabled,
+		"filterIndex":    index,
+		"filterKind":     filter.Kind,
+		"filterSettings": map[string]interface{}{},
+	}, nil
+}
+
+func setSourceFilterEnabled(s *Server, d RequestData) (map[string]interface{}, error) {
+	sourceName, _, filter, err := getFilter(s, d)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := d.Bool("filterEnabled")
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Enabled != enabled {
+		filter.Enabled = enabled
+		s.emit("SourceFilterEnableStateChanged", map[string]interface{}{"sourceName": sourceName, "filterName": filter.Name, "filterEnabled": enabled})
+	}
+	return nil, nil
+}
+
+// saveSourceScreenshot writes an image filled with a color specific to the source.
+func saveSourceScreenshot(s *Server, d RequestData) (map[string]interface{}, error) {
+	sourceName, err := d.String("sourceName")
+	if err != nil {
+		return nil, err
+	}
+	if s.state.scene(sourceName) == nil && s.state.input(sourceName) == nil {
+		return nil, requestError(StatusResourceNotFound, "No source was found by the name of `%s`.", sourceName)
+	}
+
+	format, err := d.String("imageFormat")
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := d.String("imageFilePath")
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		return nil, requestError(StatusInvalidRequestField, "The file path must be absolute.")
+	}
+
+	width, hasWidth, err := d.OptionalNumber("imageWidth", 8, 4096)
+	if err != nil {
+		return nil, err
+	}
+	height, hasHeight, err := d.OptionalNumber("imageHeight", 8, 4096)
+	if err != nil {
+		return nil, err
+	}
+	quality, hasQuality, err := d.OptionalNumber("imageCompressionQuality", -1, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasWidth {
+		width = screenshotDefaultWidth
+	}
+	if !hasHeight {
+		height = screenshotDefaultHeight
+	}
+	if !hasQuality || quality == -1 {
+		quality = jpeg.DefaultQuality
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(sourceName))
+	sum := hash.Sum32()
+
+	img := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: uint8(sum), G: uint8(sum >> 8), B: uint8(sum >> 16), A: 255}}, image.Point{}, draw.Src)
+
+	var encode func(file *os.File) error
+	switch strings.ToLower(format) {
+	case "png":
+		encode = func(file *os.File) error { return png.Encode(file, img) }
+	case "jpg", "jpeg":
+		encode = func(file *os.File) error { return jpeg.Encode(file, img, &jpeg.Options{Quality: int(quality)}) }
+	case "gif":
+		encode = func(file *os.File) error { return gif.Encode(file, img, nil) }
+	default:
+		return nil, requestError(StatusInvalidRequestField, "Your specified image format is invalid or not supported by this system.")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, requestError(StatusRequestProcessingFailed, "Failed to save the screenshot: %s", err)
+	}
+	defer file.Close()
+
+	if err := encode(file); err != nil {
+		return nil, requestError(StatusRequestProcessingFailed, "Failed to save the screenshot: %s", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,188 @@
+package standin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// State is the state of the stand-in OBS, it can be loaded from a JSON file with LoadState.
+type State struct {
+	Scenes       []Scene `json:"scenes"`
+	ProgramScene string  `json:"program_scene"`
+	PreviewScene string  `json:"preview_scene"`
+	StudioMode   bool    `json:"studio_mode"`
+
+	Inputs []Input `json:"inputs"`
+
+	Transitions              []string `json:"transitions"`
+	CurrentTransition        string   `json:"current_transition"`
+	TransitionDurationMillis int      `json:"transition_duration_millis"`
+
+	Recording  bool `json:"recording"`
+	Streaming  bool `json:"streaming"`
+	VirtualCam bool `json:"virtual_cam"`
+	// ReplayBufferEnabled is false if the replay buffer is not enabled in the output settings, so it can not be used.
+	ReplayBufferEnabled bool `json:"replay_buffer_enabled"`
+	ReplayBuffer        bool `json:"replay_buffer"`
+
+	Hotkeys []string `json:"hotkeys"`
+}
+
+// Scene is a scene with its items and filters.
+type Scene struct {
+	Name    string      `json:"name"`
+	Items   []SceneItem `json:"items"`
+	Filters []Filter    `json:"filters"`
+}
+
+type SceneItem struct {
+	ID         int    `json:"id"`
+	SourceName string `json:"source_name"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type Filter struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Input is an input (source), Audio marks the inputs having an audio track, the mute and volume requests fail for the rest.
+type Input struct {
+	Name      string                 `json:"name"`
+	Kind      string                 `json:"kind"`
+	Audio     bool                   `json:"audio"`
+	Muted     bool                   `json:"muted"`
+	VolumeMul float64                `json:"volume_mul"`
+	Settings  map[string]interface{} `json:"settings"`
+	Filters   []Filter               `json:"filters"`
+	// Media is set for media inputs (e.g. ffmpeg_source), the cursor advances while it is playing.
+	Media *Media `json:"media,omitempty"`
+}
+
+type Media struct {
+	State          string `json:"state"`
+	CursorMillis   int    `json:"cursor_millis"`
+	DurationMillis int    `json:"duration_millis"`
+}
+
+const (
+	MediaStateNone    = "OBS_MEDIA_STATE_NONE"
+	MediaStatePlaying = "OBS_MEDIA_STATE_PLAYING"
+	MediaStatePaused  = "OBS_MEDIA_STATE_PAUSED"
+	MediaStateStopped = "OBS_MEDIA_STATE_STOPPED"
+	MediaStateEnded   = "OBS_MEDIA_STATE_ENDED"
+)
+
+// DefaultState returns a small studio setup with a few scenes, audio, text, camera and media inputs, in studio mode.
+func DefaultState() State {
+	return State{
+		Scenes: []Scene{
+			{
+				Name: "STAGE",
+				Items: []SceneItem{
+					{ID: 1, SourceName: "Camera", Enabled: true},
+					{ID: 2, SourceName: "Lower third", Enabled: false},
+				},
+				Filters: []Filter{},
+			},
+			{
+				Name: "PULPIT",
+				Items: []SceneItem{
+					{ID: 1, SourceName: "Camera", Enabled: true},
+					{ID: 2, SourceName: "Lower third", Enabled: true},
+				},
+				Filters: []Filter{},
+			},
+			{
+				Name:    "VIDEO",
+				Items:   []SceneItem{{ID: 1, SourceName: "Intro video", Enabled: true}},
+				Filters: []Filter{{Name: "Fade", Kind: "color_filter_v2", Enabled: false}},
+			},
+		},
+		ProgramScene: "STAGE",
+		PreviewScene: "PULPIT",
+		StudioMode:   true,
+		Inputs: []Input{
+			{Name: "Mic/Aux", Kind: "pulse_input_capture", Audio: true, VolumeMul: 1, Settings: map[string]interface{}{}, Filters: []Filter{{Name: "Noise Suppression", Kind: "noise_suppress_filter_v2", Enabled: true}}},
+			{Name: "Desktop Audio", Kind: "pulse_output_capture", Audio: true, VolumeMul: 1, Settings: map[string]interface{}{}, Filters: []Filter{}},
+			{Name: "Camera", Kind: "v4l2_input", VolumeMul: 1, Settings: map[string]interface{}{}, Filters: []Filter{{Name: "Color Correction", Kind: "color_filter_v2", Enabled: true}}},
+			{Name: "Lower third", Kind: "text_ft2_source_v2", VolumeMul: 1, Settings: map[string]interface{}{"text": ""}, Filters: []Filter{}},
+			{Name: "Intro video", Kind: "ffmpeg_source", Audio: true, VolumeMul: 1, Settings: map[string]interface{}{}, Filters: []Filter{}, Media: &Media{State: MediaStateStopped, DurationMillis: 30000}},
+		},
+		Transitions:              []string{"Cut", "Fade"},
+		CurrentTransition:        "Fade",
+		TransitionDurationMillis: 300,
+		ReplayBufferEnabled:      true,
+		Hotkeys: []string{
+			"OBSBasic.StartStreaming",
+			"OBSBasic.StopStreaming",
+			"OBSBasic.StartRecording",
+			"OBSBasic.StopRecording",
+			"OBSBasic.StartReplayBuffer",
+			"OBSBasic.StopReplayBuffer",
+			"OBSBasic.SaveReplayBuffer",
+			"OBSBasic.Screenshot",
+		},
+	}
+}
+
+// LoadState reads a state from a JSON file, see State for the structure.
+func LoadState(path string) (State, error) {
+	var state State
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, fmt.Errorf("failed to read the state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse the state: %w", err)
+	}
+
+	return state, nil
+}
+
+// clone returns a deep copy of the state.
+func (s State) clone() State {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	var c State
+	if err := json.Unmarshal(data, &c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (s *State) scene(name string) *Scene {
+	for i := range s.Scenes {
+		if s.Scenes[i].Name == name {
+			return &s.Scenes[i]
+		}
+	}
+	return nil
+}
+
+func (s *State) input(name string) *Input {
+	for i := range s.Inputs {
+		if s.Inputs[i].Name == name {
+			return &s.Inputs[i]
+		}
+	}
+	return nil
+}
+
+// filters returns the filters of a scene or an input.
+func (s *State) filters(sourceName string) (*[]Filter, bool) {
+	if scene := s.scene(sourceName); scene != nil {
+		return &scene.Filters, true
+	}
+	if input := s.input(sourceName); input != nil {
+		return &input.Filters, true
+	}
+	return nil, false
+}
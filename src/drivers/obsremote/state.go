@@ -0,0 +1,193 @@
+package obsremote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andreykaipov/goobs"
+	"github.com/andreykaipov/goobs/api/requests/filters"
+	"github.com/andreykaipov/goobs/api/requests/inputs"
+	"github.com/andreykaipov/goobs/api/requests/mediainputs"
+	"github.com/andreykaipov/goobs/api/requests/outputs"
+	"github.com/andreykaipov/goobs/api/requests/sceneitems"
+	"github.com/andreykaipov/goobs/api/requests/transitions"
+	"github.com/andreykaipov/goobs/api/requests/ui"
+	"github.com/andreykaipov/goobs/api/typedefs"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// ListInputs returns the names of the inputs of the given kind (e.g. "ffmpeg_source"), or of all inputs if [kind] is empty.
+func (or *OBSRemote) ListInputs(ctx context.Context, kind string) ([]string, error) {
+	var inputNames []string
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Inputs.GetInputList(&inputs.GetInputListParams{InputKind: kind})
+		if err != nil {
+			return err
+		}
+		inputNames = slicetools.Map(r.Inputs, func(t *typedefs.Input) string {
+			return t.InputName
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inputs: %w", err)
+	}
+	return inputNames, nil
+}
+
+func (or *OBSRemote) IsInputMuted(ctx context.Context, inputName string) (bool, error) {
+	var muted bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Inputs.GetInputMute(&inputs.GetInputMuteParams{InputName: inputName})
+		if err != nil {
+			return err
+		}
+		muted = r.InputMuted
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve mute state of %s: %w", inputName, err)
+	}
+	return muted, nil
+}
+
+func (or *OBSRemote) GetInputVolume(ctx context.Context, inputName string) (db float64, mul float64, err error) {
+	err = or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Inputs.GetInputVolume(&inputs.GetInputVolumeParams{InputName: inputName})
+		if err != nil {
+			return err
+		}
+		db, mul = r.InputVolumeDb, r.InputVolumeMul
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to retrieve volume of %s: %w", inputName, err)
+	}
+	return db, mul, nil
+}
+
+// GetCurrentTransition returns the name and the duration (in milliseconds) of the current scene transition.
+func (or *OBSRemote) GetCurrentTransition(ctx context.Context) (name string, durationMillis int, err error) {
+	err = or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Transitions.GetCurrentSceneTransition(&transitions.GetCurrentSceneTransitionParams{})
+		if err != nil {
+			return err
+		}
+		name, durationMillis = r.TransitionName, int(r.TransitionDuration)
+		return nil
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to retrieve current transition: %w", err)
+	}
+	return name, durationMillis, nil
+}
+
+func (or *OBSRemote) IsStudioModeEnabled(ctx context.Context) (bool, error) {
+	var enabled bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Ui.GetStudioModeEnabled(&ui.GetStudioModeEnabledParams{})
+		if err != nil {
+			return err
+		}
+		enabled = r.StudioModeEnabled
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve studio mode state: %w", err)
+	}
+	return enabled, nil
+}
+
+func (or *OBSRemote) ListSceneItems(ctx context.Context, sceneName string) ([]usecaseifs.OBSSceneItem, error) {
+	var items []usecaseifs.OBSSceneItem
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.SceneItems.GetSceneItemList(&sceneitems.GetSceneItemListParams{SceneName: sceneName})
+		if err != nil {
+			return err
+		}
+		items = slicetools.Map(r.SceneItems, func(t *typedefs.SceneItem) usecaseifs.OBSSceneItem {
+			return usecaseifs.OBSSceneItem{ID: t.SceneItemID, SourceName: t.SourceName, Enabled: t.SceneItemEnabled}
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the items of scene %s: %w", sceneName, err)
+	}
+	return items, nil
+}
+
+func (or *OBSRemote) ListSourceFilters(ctx context.Context, sourceName string) ([]usecaseifs.OBSFilter, error) {
+	var filterList []usecaseifs.OBSFilter
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Filters.GetSourceFilterList(&filters.GetSourceFilterListParams{SourceName: sourceName})
+		if err != nil {
+			return err
+		}
+		filterList = slicetools.Map(r.Filters, func(t *typedefs.Filter) usecaseifs.OBSFilter {
+			return usecaseifs.OBSFilter{Name: t.FilterName, Enabled: t.FilterEnabled}
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the filters of %s: %w", sourceName, err)
+	}
+	return filterList, nil
+}
+
+// IsReplayBufferActive fails if the replay buffer is not enabled in the output settings of OBS.
+func (or *OBSRemote) IsReplayBufferActive(ctx context.Context) (bool, error) {
+	var active bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Outputs.GetReplayBufferStatus(&outputs.GetReplayBufferStatusParams{})
+		if err != nil {
+			return err
+		}
+		active = r.OutputActive
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve replay buffer status: %w", err)
+	}
+	return active, nil
+}
+
+func (or *OBSRemote) IsVirtualCamActive(ctx context.Context) (bool, error) {
+	var active bool
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.Outputs.GetVirtualCamStatus(&outputs.GetVirtualCamStatusParams{})
+		if err != nil {
+			return err
+		}
+		active = r.OutputActive
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve virtual camera status: %w", err)
+	}
+	return active, nil
+}
+
+func (or *OBSRemote) GetMediaInputStatus(ctx context.Context, inputName string) (usecaseifs.OBSMediaStatus, error) {
+	var status usecaseifs.OBSMediaStatus
+
+	err := or.withClient(ctx, func(client *goobs.Client) error {
+		r, err := client.MediaInputs.GetMediaInputStatus(&mediainputs.GetMediaInputStatusParams{InputName: inputName})
+		if err != nil {
+			return err
+		}
+		status = usecaseifs.OBSMediaStatus{State: r.MediaState, CursorMillis: int(r.MediaCursor), DurationMillis: int(r.MediaDuration)}
+		return nil
+	})
+	if err != nil {
+		return status, fmt.Errorf("failed to retrieve media status of %s: %w", inputName, err)
+	}
+	return status, nil
+}
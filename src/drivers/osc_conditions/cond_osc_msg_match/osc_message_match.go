@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"net.kopias.oscbridge/app/drivers/osc_conditions"
 
 	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+	"net.kopias.oscbridge/app/pkg/x32"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -20,14 +23,20 @@ const (
 	AddressMatchTypeKey = "address_match_type"
 	ArgumentsKey        = "arguments"
 	TriggerOnChangeKey  = "trigger_on_change"
+	QuantifierKey       = "quantifier"
 
 	AddressMatchTypeEq     = "eq"
 	AddressMatchTypeRegexp = "regexp"
+	AddressMatchTypeOSC    = "osc"
 
 	ArgIndexKey          = "index"
 	ArgTypeKey           = "type"
 	ArgValueKey          = "value"
 	ArgValueMatchTypeKey = "value_match_type"
+	ArgUnitKey           = "unit"
+
+	// UnitX32DB compares the X32 fader floats in dB, the value is a dB level (e.g. -10, -inf).
+	UnitX32DB = "x32_db"
 
 	ValueMatchTypeRegexp = "regexp"
 	ValueMatchTypeEq     = "="
@@ -37,6 +46,12 @@ const (
 	ValueMatchTypeGT     = ">"
 	ValueMatchTypeNOT    = "!="
 	// @TODO ADD MOD
+
+	QuantifierAny     = "any"
+	QuantifierAll     = "all"
+	QuantifierNone    = "none"
+	QuantifierAtLeast = "at_least"
+	QuantifierExactly = "exactly"
 )
 
 type argumentCondition struct {
@@ -45,12 +60,47 @@ type argumentCondition struct {
 	variableValue          string
 	variableValueRegexp    *regexp.Regexp
 	variableValueMatchType string
+	unit                   string
+	// db is the value in dB, if the unit is x32_db.
+	db float64
 }
 
 func (ac argumentCondition) String() string {
+	if ac.unit != "" {
+		return fmt.Sprintf("ArgumentCondition(type: %s, value: %s %s, matchType: %s)", ac.variableType, ac.variableValue, ac.unit, ac.variableValueMatchType)
+	}
 	return fmt.Sprintf("ArgumentCondition(type: %s, value: %s, matchType: %s)", ac.variableType, ac.variableValue, ac.variableValueMatchType)
 }
 
+// quantifier determines how many of the address-matched records must match the argument patterns.
+type quantifier struct {
+	kind  string
+	count int
+}
+
+func (q quantifier) String() string {
+	if q.kind == QuantifierAtLeast || q.kind == QuantifierExactly {
+		return fmt.Sprintf("%s %d", q.kind, q.count)
+	}
+	return q.kind
+}
+
+// satisfied tells if [matched] records out of [total] fulfill the quantifier.
+func (q quantifier) satisfied(matched int, total int) bool {
+	switch q.kind {
+	case QuantifierAll:
+		return total > 0 && matched == total
+	case QuantifierNone:
+		return matched == 0
+	case QuantifierAtLeast:
+		return matched >= q.count
+	case QuantifierExactly:
+		return matched == q.count
+	default:
+		return matched > 0
+	}
+}
+
 // OSCCondition matches an entire OSC Message by address and arguments if applicable.
 type OSCCondition struct {
 	path     string
@@ -63,6 +113,7 @@ type OSCCondition struct {
 	addressMatchType string
 	argumentPatterns []argumentCondition
 	triggerOnChange  bool
+	quantifier       quantifier
 	conditionTracker *osc_conditions.ConditionTracker
 }
 
@@ -83,7 +134,7 @@ func (a *OSCCondition) SetParameters(m map[string]interface{}) {
 			Name:         AddressMatchTypeKey,
 			Optional:     true,
 			DefaultValue: AddressMatchTypeEq,
-			ValuePattern: fmt.Sprintf("^%s|%s$", AddressMatchTypeEq, AddressMatchTypeRegexp),
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s)$", AddressMatchTypeEq, AddressMatchTypeRegexp, AddressMatchTypeOSC),
 			Type:         []string{"string"},
 		}, {
 			Name:         TriggerOnChangeKey,
@@ -94,6 +145,11 @@ func (a *OSCCondition) SetParameters(m map[string]interface{}) {
 			Name:     ArgumentsKey,
 			Optional: true,
 			Type:     []string{"[]interface {}"},
+		}, {
+			Name:         QuantifierKey,
+			Optional:     true,
+			DefaultValue: QuantifierAny,
+			Type:         []string{"string", "map[string]interface {}"},
 		},
 	})
 	if err != nil {
@@ -101,6 +157,12 @@ func (a *OSCCondition) SetParameters(m map[string]interface{}) {
 		return
 	}
 
+	a.quantifier, err = parseQuantifier(sanitized[QuantifierKey])
+	if err != nil {
+		a.configError = fmt.Errorf("%s failed to verify parameters: %w", a.path, err)
+		return
+	}
+
 	// nolint:forcetypeassert
 	a.addressPattern = sanitized[AddressKey].(string)
 
@@ -118,6 +180,13 @@ func (a *OSCCondition) SetParameters(m map[string]interface{}) {
 		}
 	}
 
+	if sanitized[AddressMatchTypeKey] == AddressMatchTypeOSC {
+		if err = oscpattern.Validate(a.addressPattern); err != nil {
+			a.configError = fmt.Errorf("%s is not a valid osc address pattern: %w", AddressKey, err)
+			return
+		}
+	}
+
 	args, ok := sanitized[ArgumentsKey]
 	if !ok {
 		a.configError = fmt.Errorf("key %s was not found", ArgumentsKey)
@@ -135,6 +204,35 @@ func (a *OSCCondition) SetParameters(m map[string]interface{}) {
 	}
 }
 
+// parseQuantifier accepts either a plain string (any, all, none) or a single keyed map (at_least: N, exactly: N).
+func parseQuantifier(v interface{}) (quantifier, error) {
+	switch t := v.(type) {
+	case string:
+		if t != QuantifierAny && t != QuantifierAll && t != QuantifierNone {
+			return quantifier{}, fmt.Errorf("invalid %s: '%s', valid values: %s, %s, %s, %s: N, %s: N",
+				QuantifierKey, t, QuantifierAny, QuantifierAll, QuantifierNone, QuantifierAtLeast, QuantifierExactly)
+		}
+		return quantifier{kind: t}, nil
+
+	case map[string]interface{}:
+		if len(t) != 1 {
+			return quantifier{}, fmt.Errorf("%s must have exactly one key (%s or %s)", QuantifierKey, QuantifierAtLeast, QuantifierExactly)
+		}
+		for kind, countValue := range t {
+			if kind != QuantifierAtLeast && kind != QuantifierExactly {
+				return quantifier{}, fmt.Errorf("invalid %s: '%s', valid keys: %s, %s", QuantifierKey, kind, QuantifierAtLeast, QuantifierExactly)
+			}
+			count, ok := countValue.(int)
+			if !ok || count < 0 {
+				return quantifier{}, fmt.Errorf("%s.%s must be a non-negative integer", QuantifierKey, kind)
+			}
+			return quantifier{kind: kind, count: count}, nil
+		}
+	}
+
+	return quantifier{}, fmt.Errorf("%s is of a wrong type (%T)", QuantifierKey, v)
+}
+
 func (a *OSCCondition) setArgumentParameters(m interface{}) error {
 	mCasted, ok := m.(map[string]interface{})
 	if !ok {
@@ -149,7 +247,7 @@ func (a *OSCCondition) setArgumentParameters(m interface{}) error {
 		{
 			Name:         ArgTypeKey,
 			Optional:     false,
-			ValuePattern: "^string|int|float$", // @TODO this doesn't work... int32 is accepted.
+			ValuePattern: "^(string|int32|float32)$",
 			Type:         []string{"string"},
 		},
 		{
@@ -172,6 +270,13 @@ func (a *OSCCondition) setArgumentParameters(m interface{}) error {
 			}, "|")),
 			Type: []string{"string"},
 		},
+		{
+			Name:         ArgUnitKey,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: fmt.Sprintf("^(%s|)$", UnitX32DB),
+			Type:         []string{"string"},
+		},
 	})
 	if err != nil {
 		return err
@@ -187,6 +292,18 @@ func (a *OSCCondition) setArgumentParameters(m interface{}) error {
 	newArgCondition.variableValue = sanitized[ArgValueKey].(string)
 	// nolint:forcetypeassert
 	newArgCondition.variableValueMatchType = sanitized[ArgValueMatchTypeKey].(string)
+	// nolint:forcetypeassert
+	newArgCondition.unit = sanitized[ArgUnitKey].(string)
+
+	if newArgCondition.unit == UnitX32DB {
+		if newArgCondition.variableValueMatchType == ValueMatchTypeRegexp {
+			return fmt.Errorf("%s can not be used with %s %s", ValueMatchTypeRegexp, ArgUnitKey, UnitX32DB)
+		}
+		newArgCondition.db, err = x32.ParseDB(newArgCondition.variableValue)
+		if err != nil {
+			return err
+		}
+	}
 
 	if newArgCondition.variableValueMatchType == ValueMatchTypeRegexp {
 		newArgCondition.variableValueRegexp, err = regexp.Compile(newArgCondition.variableValue)
@@ -204,38 +321,68 @@ func (a *OSCCondition) GetType() string {
 }
 
 func (a *OSCCondition) Evaluate(ctx context.Context, store usecaseifs.IMessageStore) (bool, error) {
-	var err error
-	var record usecaseifs.IMessageStoreRecord
-	var found bool
+	records, err := a.getRecords(store)
+	if err != nil {
+		return a.conditionTracker.R(ctx, false, a.path, err.Error()), err
+	}
 
-	if a.addressMatchType == AddressMatchTypeRegexp {
-		record, err = store.GetOneRecordByRegexp(a.addressPattern, a.triggerOnChange)
+	matchedCount := 0
+	for _, record := range records {
+		matched, err := a.matchRecord(record)
 		if err != nil {
-			err = fmt.Errorf("failed to get record by regexp: %w", err)
 			return a.conditionTracker.R(ctx, false, a.path, err.Error()), err
 		}
-		if record == nil {
-			return a.conditionTracker.R(ctx, false, a.path, "record not found by regexp on address: %s", a.addressPattern), nil
+		if matched {
+			matchedCount++
+		}
+	}
+
+	if !a.quantifier.satisfied(matchedCount, len(records)) {
+		return a.conditionTracker.R(ctx, false, a.path, "%d of %d record(s) on address '%s' matched the arguments, which does not satisfy '%s'",
+			matchedCount, len(records), a.addressPattern, a.quantifier.String()), nil
+	}
+
+	return a.conditionTracker.R(ctx, true, a.path, "%d of %d record(s) on address '%s' matched the arguments, which satisfies '%s'",
+		matchedCount, len(records), a.addressPattern, a.quantifier.String()), nil
+}
+
+// getRecords returns every record that matches the address pattern.
+func (a *OSCCondition) getRecords(store usecaseifs.IMessageStore) ([]usecaseifs.IMessageStoreRecord, error) {
+	switch a.addressMatchType {
+	case AddressMatchTypeRegexp:
+		records, err := store.GetRecordsByRegexp(a.addressPattern, a.triggerOnChange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get records by regexp: %w", err)
 		}
-	} else {
-		record, found = store.GetRecord(a.addressPattern, a.triggerOnChange)
-		if !found {
-			return a.conditionTracker.R(ctx, false, a.path, "record not found by exact match: %s", a.addressPattern), nil
+		return records, nil
+
+	case AddressMatchTypeOSC:
+		records, err := store.GetRecordsByPattern(a.addressPattern, a.triggerOnChange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get records by osc pattern: %w", err)
 		}
+		return records, nil
+	}
+
+	record, found := store.GetRecord(a.addressPattern, a.triggerOnChange)
+	if !found {
+		return []usecaseifs.IMessageStoreRecord{}, nil
 	}
+	return []usecaseifs.IMessageStoreRecord{record}, nil
+}
 
-	var matched bool
+// matchRecord checks a single record against all the argument patterns.
+func (a *OSCCondition) matchRecord(record usecaseifs.IMessageStoreRecord) (bool, error) {
 	for i, ap := range a.argumentPatterns {
-		matched, err = a.matchArguments(record, ap)
+		matched, err := a.matchArguments(record, ap)
 		if err != nil {
-			err = fmt.Errorf("failed to match argument[%d]: %w", i, err)
-			return a.conditionTracker.R(ctx, false, a.path, err.Error()), err
+			return false, fmt.Errorf("failed to match argument[%d]: %w", i, err)
 		}
 		if !matched {
-			return a.conditionTracker.R(ctx, false, a.path, "argument %d did not match '%s'", i, ap.String()), nil
+			return false, nil
 		}
 	}
-	return a.conditionTracker.R(ctx, true, a.path, "all checks passed"), nil
+	return true, nil
 }
 
 // nolint: unparam,cyclop
@@ -250,6 +397,10 @@ func (a *OSCCondition) matchArguments(record usecaseifs.IMessageStoreRecord, ac
 		return false, nil
 	}
 
+	if ac.unit == UnitX32DB {
+		return matchDB(arg.GetValue(), ac)
+	}
+
 	switch ac.variableValueMatchType {
 	case ValueMatchTypeEq:
 		if arg.GetValue() != ac.variableValue {
@@ -284,6 +435,30 @@ func (a *OSCCondition) matchArguments(record usecaseifs.IMessageStoreRecord, ac
 	return true, nil
 }
 
+// matchDB compares an X32 fader float to the dB value of the condition, with 0.1 dB precision.
+func matchDB(value string, ac argumentCondition) (bool, error) {
+	fader, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse '%s' as a fader float: %w", value, err)
+	}
+
+	db, expected := x32.RoundDB(x32.FaderToDB(fader)), x32.RoundDB(ac.db)
+	switch ac.variableValueMatchType {
+	case ValueMatchTypeLTE:
+		return db <= expected, nil
+	case ValueMatchTypeGTE:
+		return db >= expected, nil
+	case ValueMatchTypeLT:
+		return db < expected, nil
+	case ValueMatchTypeGT:
+		return db > expected, nil
+	case ValueMatchTypeNOT:
+		return db != expected, nil
+	default:
+		return db == expected, nil
+	}
+}
+
 func (a *OSCCondition) AddChild(condition usecaseifs.IActionCondition) {
 	a.children = append(a.children, condition)
 }
@@ -0,0 +1,134 @@
+package cond_osc_msg_match
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"net.kopias.oscbridge/app/drivers/messagestore"
+	"net.kopias.oscbridge/app/drivers/osc_conditions"
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/logger"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+func TestQuantifierSatisfied(t *testing.T) {
+	tests := []struct {
+		quantifier quantifier
+		matched    int
+		total      int
+		want       bool
+	}{
+		{quantifier: quantifier{kind: QuantifierAny}, matched: 0, total: 0, want: false},
+		{quantifier: quantifier{kind: QuantifierAny}, matched: 0, total: 3, want: false},
+		{quantifier: quantifier{kind: QuantifierAny}, matched: 1, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierAll}, matched: 0, total: 0, want: false},
+		{quantifier: quantifier{kind: QuantifierAll}, matched: 2, total: 3, want: false},
+		{quantifier: quantifier{kind: QuantifierAll}, matched: 3, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierNone}, matched: 0, total: 0, want: true},
+		{quantifier: quantifier{kind: QuantifierNone}, matched: 0, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierNone}, matched: 1, total: 3, want: false},
+		{quantifier: quantifier{kind: QuantifierAtLeast, count: 0}, matched: 0, total: 0, want: true},
+		{quantifier: quantifier{kind: QuantifierAtLeast, count: 2}, matched: 1, total: 3, want: false},
+		{quantifier: quantifier{kind: QuantifierAtLeast, count: 2}, matched: 2, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierAtLeast, count: 2}, matched: 3, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierExactly, count: 0}, matched: 0, total: 0, want: true},
+		{quantifier: quantifier{kind: QuantifierExactly, count: 0}, matched: 1, total: 3, want: false},
+		{quantifier: quantifier{kind: QuantifierExactly, count: 2}, matched: 2, total: 3, want: true},
+		{quantifier: quantifier{kind: QuantifierExactly, count: 2}, matched: 3, total: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.quantifier.satisfied(tt.matched, tt.total); got != tt.want {
+			t.Errorf("%s satisfied(%d, %d) = %t, want %t", tt.quantifier, tt.matched, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuantifier(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    quantifier
+		wantErr bool
+	}{
+		{in: "any", want: quantifier{kind: QuantifierAny}},
+		{in: "all", want: quantifier{kind: QuantifierAll}},
+		{in: "none", want: quantifier{kind: QuantifierNone}},
+		{in: map[string]interface{}{"at_least": 3}, want: quantifier{kind: QuantifierAtLeast, count: 3}},
+		{in: map[string]interface{}{"exactly": 0}, want: quantifier{kind: QuantifierExactly, count: 0}},
+		{in: "some", wantErr: true},
+		{in: map[string]interface{}{"at_most": 3}, wantErr: true},
+		{in: map[string]interface{}{"at_least": -1}, wantErr: true},
+		{in: map[string]interface{}{"at_least": "3"}, wantErr: true},
+		{in: map[string]interface{}{"at_least": 1, "exactly": 1}, wantErr: true},
+		{in: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseQuantifier(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseQuantifier(%v) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseQuantifier(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestEvaluateQuantifiers runs the quantifiers on the channels 01..04 of a store, with the channels 01 and 02 open.
+func TestEvaluateQuantifiers(t *testing.T) {
+	store := messagestore.NewMessageStore()
+	for i, on := range []string{"1", "1", "0", "0"} {
+		store.SetRecord(osc_message.NewMessage(fmt.Sprintf("/ch/%02d/mix/on", i+1), []usecaseifs.IOSCMessageArgument{
+			osc_message.NewMessageArgument("int32", on),
+		}))
+	}
+
+	tests := []struct {
+		address    string
+		quantifier interface{}
+		want       bool
+	}{
+		{address: "/ch/*/mix/on", quantifier: "any", want: true},
+		{address: "/ch/*/mix/on", quantifier: "all", want: false},
+		{address: "/ch/0[12]/mix/on", quantifier: "all", want: true},
+		{address: "/ch/*/mix/on", quantifier: "none", want: false},
+		{address: "/ch/0[34]/mix/on", quantifier: "none", want: true},
+		{address: "/ch/*/mix/on", quantifier: map[string]interface{}{"at_least": 2}, want: true},
+		{address: "/ch/*/mix/on", quantifier: map[string]interface{}{"at_least": 3}, want: false},
+		{address: "/ch/*/mix/on", quantifier: map[string]interface{}{"exactly": 2}, want: true},
+		{address: "/ch/*/mix/on", quantifier: map[string]interface{}{"exactly": 1}, want: false},
+		// No record matches the address at all.
+		{address: "/bus/*/mix/on", quantifier: "any", want: false},
+		{address: "/bus/*/mix/on", quantifier: "all", want: false},
+		{address: "/bus/*/mix/on", quantifier: "none", want: true},
+		{address: "/bus/*/mix/on", quantifier: map[string]interface{}{"exactly": 0}, want: true},
+	}
+
+	factory := NewFactory(osc_conditions.NewConditionTracker(logger.New(), false))
+	for _, tt := range tests {
+		condition := factory("test")
+		condition.SetParameters(map[string]interface{}{
+			AddressKey:          tt.address,
+			AddressMatchTypeKey: AddressMatchTypeOSC,
+			QuantifierKey:       tt.quantifier,
+			ArgumentsKey: []interface{}{
+				map[string]interface{}{ArgIndexKey: 0, ArgTypeKey: "int32", ArgValueKey: "1"},
+			},
+		})
+		if err := condition.Validate(); err != nil {
+			t.Fatalf("invalid condition: %s", err)
+		}
+
+		got, err := condition.Evaluate(context.Background(), store)
+		if err != nil {
+			t.Errorf("%s with %v failed: %s", tt.address, tt.quantifier, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s with %v = %t, want %t", tt.address, tt.quantifier, got, tt.want)
+		}
+	}
+}
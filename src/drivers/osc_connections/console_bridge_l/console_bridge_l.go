@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"net.kopias.oscbridge/app/drivers/osc_message"
@@ -18,7 +20,23 @@ import (
 
 // This implementation uses loffa/gosc, which is lacking disconnect options.
 
-var _ usecaseifs.IOSCConnection = &Connection{}
+var (
+	_ usecaseifs.IOSCConnection    = &Connection{}
+	_ usecaseifs.IConnectionHealth = &Connection{}
+)
+
+const (
+	// checkInterval is the time between two connection checks.
+	checkInterval = 5 * time.Second
+	// checkTimeout is the time the check response must arrive in.
+	checkTimeout = 10 * time.Second
+)
+
+// Client sends and receives the OSC messages of a Connection.
+type Client interface {
+	SendMessage(msg *gosc.Message) error
+	ReceiveMessageFunc(addressPattern string, receiverFunc gosc.MessageReceiverFunc) error
+}
 
 type Config struct {
 	Debug         bool
@@ -27,11 +45,20 @@ type Config struct {
 	Host          string
 	CheckAddress  string
 	CheckPattern  string
+
+	// KeepRunningOnFailure marks the connection down upon a failed check, instead of notifying an error.
+	KeepRunningOnFailure bool
+
+	// Sync lists the addresses to query at start and after a reconnect.
+	Sync config.ConsoleSync
+
+	// Dial creates the client for the address (host:port), gosc's UDP client is used if nil.
+	Dial func(address string) (Client, error)
 }
 
 type Connection struct {
 	cfg      Config
-	client   *gosc.Client
+	client   Client
 	messages chan usecaseifs.IOSCMessage
 	log      usecaseifs.ILogger
 
@@ -40,21 +67,44 @@ type Connection struct {
 
 	// A channel that shows when the client exited with an error.
 	notify chan error
+
+	// checkResponses receives the messages arriving on the check address.
+	checkResponses chan *gosc.Message
+
+	// up is false after a failed connection check, until the next successful one.
+	up *atomic.Bool
+
+	// syncM guards the state of the running sync: the addresses yet to answer, and the channel closed once all did.
+	syncM       *sync.Mutex
+	syncPending map[string]bool
+	syncDone    chan any
+	cancelSync  context.CancelFunc
 }
 
 func NewConnection(log usecaseifs.ILogger, cfg Config) usecaseifs.IOSCConnection {
+	up := &atomic.Bool{}
+	up.Store(true)
+
 	return &Connection{
-		log:      log,
-		cfg:      cfg,
-		quit:     make(chan any),
-		messages: make(chan usecaseifs.IOSCMessage),
-		notify:   make(chan error, 1),
+		log:            log,
+		cfg:            cfg,
+		quit:           make(chan any),
+		messages:       make(chan usecaseifs.IOSCMessage),
+		notify:         make(chan error, 1),
+		checkResponses: make(chan *gosc.Message, 1),
+		up:             up,
+		syncM:          &sync.Mutex{},
 	}
 }
 
 func (c *Connection) Start(ctx context.Context) error {
 	// Set up the client.
-	client, err := gosc.NewClient(fmt.Sprintf("%s:%d", c.cfg.Host, c.cfg.Port))
+	dial := c.cfg.Dial
+	if dial == nil {
+		dial = func(address string) (Client, error) { return gosc.NewClient(address) }
+	}
+
+	client, err := dial(fmt.Sprintf("%s:%d", c.cfg.Host, c.cfg.Port))
 	if err != nil {
 		return fmt.Errorf("failed to resolve udp addr: %w", err)
 	}
@@ -64,11 +114,22 @@ func (c *Connection) Start(ctx context.Context) error {
 	err = c.client.ReceiveMessageFunc(".*", func(oscMessage *gosc.Message) {
 		msg, err2 := MessageFromOSCMessage(*oscMessage)
 		if err2 != nil {
-			c.log.Err(ctx, err2)
+			c.log.Err(ctx, fmt.Errorf("failed to convert message on %s: %w", oscMessage.Address, err2))
+			return
 		}
 		if c.cfg.Debug {
 			c.log.Infof(ctx, "Received message: %v", msg)
 		}
+
+		c.markSynced(oscMessage.Address)
+
+		if oscMessage.Address == c.cfg.CheckAddress {
+			select {
+			case c.checkResponses <- oscMessage:
+			default:
+			}
+		}
+
 		c.messages <- msg
 	})
 
@@ -78,6 +139,7 @@ func (c *Connection) Start(ctx context.Context) error {
 
 	go c.watchdog(ctx)
 	go c.manageSubscriptions(ctx)
+	c.startSync(ctx)
 
 	return nil
 }
@@ -144,52 +206,72 @@ func (c *Connection) executeSub(ctx context.Context, i int) {
 	}
 }
 
+// watchdog regularly checks the connection, if a check address is configured.
+// A failed check either marks the connection down, or emits an error signalling that this connection is dead.
 func (c *Connection) watchdog(ctx context.Context) {
+	if c.cfg.CheckAddress == "" {
+		return
+	}
+
 	for {
 		if c.cfg.Debug {
 			c.log.Infof(ctx, "OSC conn checking connection...")
+		}
 
-			// The checkConnection should finish in 10seconds, or we emit an error signalling that this connection is dead.
-			stop := make(chan any, 1)
-
-			go func() {
-				select {
-				case <-time.After(10 * time.Second):
-					c.notify <- fmt.Errorf("timeout without check connection response")
-				case <-stop:
-					return
-				}
-			}()
-
-			err := c.checkConnection(ctx)
-			stop <- true
-			if err != nil {
-				c.notify <- fmt.Errorf("osc connection is broken: %w", err)
-				c.Stop(ctx)
+		err := c.checkConnection(ctx)
+		switch {
+		case err == nil:
+			if !c.up.Swap(true) {
+				c.log.Infof(ctx, "OSC connection is up again.")
+				c.startSync(ctx)
 			}
-
-			// Wait on stop to finish, or retry...
-			select {
-			case <-c.quit:
-				return
-			case <-time.After(5 * time.Second):
+		case c.cfg.KeepRunningOnFailure:
+			if c.up.Swap(false) {
+				c.log.Warnf(ctx, "OSC connection is down: %s", err)
 			}
+		default:
+			c.up.Store(false)
+			c.notify <- fmt.Errorf("osc connection is broken: %w", err)
+			c.Stop(ctx)
+			return
+		}
+
+		// Wait on stop to finish, or retry...
+		select {
+		case <-c.quit:
+			return
+		case <-time.After(checkInterval):
 		}
 	}
 }
 
-// CheckConnection sends a check OSC Message for which some response is expected.
+// CheckConnection sends a check OSC Message for which some response is expected in checkTimeout.
 // The resulting response's first argument will be matched against a pattern.
 func (c *Connection) checkConnection(ctx context.Context) error {
 	msg, err := OSCMessageFromMessage(osc_message.NewMessage(c.cfg.CheckAddress, nil))
 	if err != nil {
 		return fmt.Errorf("failed to convert check message: %w", err)
 	}
-	resp, err := c.client.SendAndReceiveMessage(msg)
-	if err != nil {
+
+	// Drop a late response of a previous check.
+	select {
+	case <-c.checkResponses:
+	default:
+	}
+
+	if err := c.client.SendMessage(msg); err != nil {
 		return err
 	}
 
+	var resp *gosc.Message
+	select {
+	case resp = <-c.checkResponses:
+	case <-time.After(checkTimeout):
+		return fmt.Errorf("timeout without check connection response")
+	case <-c.quit:
+		return nil
+	}
+
 	if len(resp.Arguments) == 0 {
 		return fmt.Errorf("the received message has no arguments to check")
 	}
@@ -206,6 +288,11 @@ func (c *Connection) checkConnection(ctx context.Context) error {
 	return nil
 }
 
+// IsUp tells if the last connection check succeeded.
+func (c *Connection) IsUp() bool {
+	return c.up.Load()
+}
+
 // Notify returns the notification channel that can be used to listen for the client's exit
 func (c *Connection) Notify() <-chan error {
 	return c.notify
@@ -1,6 +1,7 @@
 package console_bridge_l
 
 import (
+	"encoding/base64"
 	"fmt"
 	"strconv"
 
@@ -55,6 +56,10 @@ func MessageArgumentFromOSCArgument(arg any) (usecaseifs.IOSCMessageArgument, er
 		msgValue = fmt.Sprintf("%f", t)
 	case string:
 		msgValue = t
+	case []byte:
+		// Blobs are stored base64 encoded, e.g. the meters of the X32.
+		msgType = "blob"
+		msgValue = base64.StdEncoding.EncodeToString(t)
 	default:
 		return nil, fmt.Errorf("response type %T is not supported", arg)
 	}
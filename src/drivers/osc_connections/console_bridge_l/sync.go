@@ -0,0 +1,141 @@
+package console_bridge_l
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const (
+	// SyncedAddress is published with 0 when a sync starts, and with 1 when it is complete.
+	SyncedAddress = "/bridge/synced"
+
+	// defaultQueriesPerSecond is the rate of the sync queries, if it is not configured.
+	defaultQueriesPerSecond = 100
+	// syncTimeout is the time the responses of a sync must arrive in, after its last query.
+	syncTimeout = 5 * time.Second
+)
+
+// startSync starts querying the sync addresses, cancelling the previous sync if it is still running.
+func (c *Connection) startSync(ctx context.Context) {
+	if len(c.cfg.Sync.Addresses) == 0 {
+		return
+	}
+
+	c.syncM.Lock()
+	defer c.syncM.Unlock()
+
+	if c.cancelSync != nil {
+		c.cancelSync()
+	}
+	ctx, c.cancelSync = context.WithCancel(ctx)
+
+	go c.sync(ctx)
+}
+
+// sync queries every sync address (an OSC message without arguments), and publishes the completion once all of
+// them answered, or the time is up.
+func (c *Connection) sync(ctx context.Context) {
+	addresses := []string{}
+	for _, template := range c.cfg.Sync.Addresses {
+		expanded, err := oscpattern.Expand(template)
+		if err != nil {
+			c.log.Err(ctx, err)
+			continue
+		}
+		addresses = append(addresses, expanded...)
+	}
+
+	done := make(chan any)
+	c.syncM.Lock()
+	c.syncPending = map[string]bool{}
+	for _, address := range addresses {
+		c.syncPending[address] = true
+	}
+	c.syncDone = done
+	c.syncM.Unlock()
+
+	if !c.publish(newSyncedMessage(false)) {
+		return
+	}
+	c.log.Infof(ctx, "Syncing %d addresses...", len(addresses))
+
+	rate := c.cfg.Sync.MaxQueriesPerSecond
+	if rate == 0 {
+		rate = defaultQueriesPerSecond
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	for i, address := range addresses {
+		if i > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			case <-c.quit:
+				return
+			}
+		}
+
+		if err := c.SendMessage(ctx, osc_message.NewMessage(address, nil)); err != nil {
+			c.log.Err(ctx, fmt.Errorf("failed to query %s: %w", address, err))
+		}
+	}
+
+	select {
+	case <-done:
+		c.log.Infof(ctx, "Synced %d addresses.", len(addresses))
+	case <-time.After(syncTimeout):
+		c.syncM.Lock()
+		if c.syncDone == done {
+			c.log.Warnf(ctx, "Synced %d addresses, %d of them did not answer.", len(addresses), len(c.syncPending))
+			c.syncPending = nil
+		}
+		c.syncM.Unlock()
+	case <-ctx.Done():
+		return
+	case <-c.quit:
+		return
+	}
+
+	c.publish(newSyncedMessage(true))
+}
+
+// markSynced registers the arrival of an address, that may complete the running sync.
+func (c *Connection) markSynced(address string) {
+	c.syncM.Lock()
+	defer c.syncM.Unlock()
+
+	if !c.syncPending[address] {
+		return
+	}
+
+	delete(c.syncPending, address)
+	if len(c.syncPending) == 0 {
+		close(c.syncDone)
+		c.syncPending = nil
+	}
+}
+
+// publish emits a message of the connection itself, returns false if the connection is stopped.
+func (c *Connection) publish(msg usecaseifs.IOSCMessage) bool {
+	select {
+	case c.messages <- msg:
+		return true
+	case <-c.quit:
+		return false
+	}
+}
+
+func newSyncedMessage(synced bool) usecaseifs.IOSCMessage {
+	value := "0"
+	if synced {
+		value = "1"
+	}
+	return osc_message.NewMessage(SyncedAddress, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("int32", value)})
+}
@@ -0,0 +1,224 @@
+package console_bridge_x32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sync"
+
+	"github.com/loffa/gosc"
+	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_l"
+)
+
+var _ console_bridge_l.Client = &client{}
+
+// client is a UDP OSC client for the consoles, used instead of gosc's, as that fails on the node responses (they are
+// sent to "node" without the leading slash), on the packets over 512 bytes and on blob arguments.
+type client struct {
+	conn *net.UDPConn
+
+	m         *sync.Mutex
+	receivers []receiver
+}
+
+type receiver struct {
+	pattern *regexp.Regexp
+	fn      gosc.MessageReceiverFunc
+}
+
+// dial connects to the console at [address] (host:port), and starts receiving its messages.
+func dial(address string) (console_bridge_l.Client, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client{conn: conn, m: &sync.Mutex{}}
+	go c.listen()
+	return c, nil
+}
+
+func (c *client) SendMessage(msg *gosc.Message) error {
+	packet, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.Write(packet)
+	return err
+}
+
+// ReceiveMessageFunc registers a receiver for the addresses matching the regexp, the first matching receiver gets the message.
+func (c *client) ReceiveMessageFunc(addressPattern string, receiverFunc gosc.MessageReceiverFunc) error {
+	pattern, err := regexp.Compile(addressPattern)
+	if err != nil {
+		return err
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.receivers = append(c.receivers, receiver{pattern: pattern, fn: receiverFunc})
+	return nil
+}
+
+// listen dispatches the incoming messages, the undecodable packets and the errors (e.g. the console being offline)
+// are skipped, as the connection check reports the broken connections.
+func (c *client) listen() {
+	buf := make([]byte, 65536)
+	for {
+		n, err := c.conn.Read(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+
+		msg, err := DecodeMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		c.m.Lock()
+		receivers := c.receivers
+		c.m.Unlock()
+
+		for _, r := range receivers {
+			if r.pattern.MatchString(msg.Address) {
+				r.fn(msg)
+				break
+			}
+		}
+	}
+}
+
+// EncodeMessage encodes an OSC message, the arguments may be int32, float32, string or []byte values.
+func EncodeMessage(msg *gosc.Message) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writeString(buf, msg.Address)
+
+	tags := ","
+	args := &bytes.Buffer{}
+	for _, a := range msg.Arguments {
+		switch v := a.(type) {
+		case int32:
+			tags += "i"
+			_ = binary.Write(args, binary.BigEndian, v)
+		case float32:
+			tags += "f"
+			_ = binary.Write(args, binary.BigEndian, math.Float32bits(v))
+		case string:
+			tags += "s"
+			writeString(args, v)
+		case []byte:
+			tags += "b"
+			_ = binary.Write(args, binary.BigEndian, int32(len(v)))
+			args.Write(v)
+			args.Write(make([]byte, pad(len(v))))
+		default:
+			return nil, fmt.Errorf("argument type %T is not supported", a)
+		}
+	}
+
+	writeString(buf, tags)
+	buf.Write(args.Bytes())
+	return buf.Bytes(), nil
+}
+
+// DecodeMessage decodes an OSC message (bundles are not supported), the address may lack the leading slash.
+func DecodeMessage(packet []byte) (*gosc.Message, error) {
+	r := bytes.NewReader(packet)
+
+	address, err := readString(r)
+	if err != nil || address == "" || address[0] == '#' {
+		return nil, fmt.Errorf("invalid address")
+	}
+
+	msg := &gosc.Message{Address: address, Arguments: []any{}}
+	if r.Len() == 0 {
+		return msg, nil
+	}
+
+	tags, err := readString(r)
+	if err != nil || tags == "" || tags[0] != ',' {
+		return nil, fmt.Errorf("invalid type tags on %s", address)
+	}
+
+	for _, tag := range tags[1:] {
+		var arg any
+		switch tag {
+		case 'i':
+			var v int32
+			err = binary.Read(r, binary.BigEndian, &v)
+			arg = v
+		case 'f':
+			var v uint32
+			err = binary.Read(r, binary.BigEndian, &v)
+			arg = math.Float32frombits(v)
+		case 's':
+			arg, err = readString(r)
+		case 'b':
+			arg, err = readBlob(r)
+		default:
+			return nil, fmt.Errorf("type tag %c is not supported on %s", tag, address)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument on %s: %w", address, err)
+		}
+		msg.Arguments = append(msg.Arguments, arg)
+	}
+	return msg, nil
+}
+
+// writeString writes a null terminated string, padded to 4 bytes.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.Write(make([]byte, 4-len(s)%4))
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	s := []byte{}
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b == 0 {
+			break
+		}
+		s = append(s, b)
+	}
+
+	_, err := r.Seek(int64(pad(len(s)+1)), 1)
+	return string(s), err
+}
+
+func readBlob(r *bytes.Reader) ([]byte, error) {
+	var n int32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if n < 0 || int(n) > r.Len() {
+		return nil, fmt.Errorf("invalid blob size: %d", n)
+	}
+
+	blob := make([]byte, n)
+	if _, err := r.Read(blob); err != nil {
+		return nil, err
+	}
+	_, err := r.Seek(int64(pad(int(n))), 1)
+	return blob, err
+}
+
+// pad returns the number of bytes needed to align n to 4 bytes.
+func pad(n int) int {
+	return (4 - n%4) % 4
+}
@@ -0,0 +1,206 @@
+// Package console_bridge_x32 is the console bridge of the Behringer X32/M32 and X-Air (XR) consoles.
+// It runs a console_bridge_l connection with its own UDP client, keeps the /xremote and the meter subscriptions alive, decodes the meter
+// blobs and the /node responses to separate messages, and queries the channel configs at start.
+package console_bridge_x32
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"net.kopias.oscbridge/app/adapters/config"
+	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_l"
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/chantools"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var (
+	_ usecaseifs.IOSCConnection    = &Connection{}
+	_ usecaseifs.IConnectionHealth = &Connection{}
+)
+
+const (
+	// renewMillis is the interval of renewing /xremote and the meters, the console drops them after 10 seconds.
+	renewMillis = 9000
+	// queryInterval is the time between two discovery queries, not to flood the console.
+	queryInterval = 10 * time.Millisecond
+)
+
+type Config struct {
+	Debug bool
+	// Connection is the configuration of the underlying connection, the X32 subscriptions are added to it.
+	Connection console_bridge_l.Config
+	X32        config.ConsoleX32
+}
+
+type Connection struct {
+	cfg      Config
+	conn     usecaseifs.IOSCConnection
+	messages chan usecaseifs.IOSCMessage
+	log      usecaseifs.ILogger
+
+	// Signals that the connection is stopped.
+	quit chan any
+}
+
+func NewConnection(log usecaseifs.ILogger, cfg Config) usecaseifs.IOSCConnection {
+	connCfg := cfg.Connection
+	connCfg.Subscriptions = append(subscriptions(cfg.X32), connCfg.Subscriptions...)
+	connCfg.Dial = dial
+
+	return &Connection{
+		cfg:      cfg,
+		conn:     console_bridge_l.NewConnection(log, connCfg),
+		messages: make(chan usecaseifs.IOSCMessage),
+		log:      log,
+		quit:     make(chan any),
+	}
+}
+
+// subscriptions returns the regularly renewed subscriptions: /xremote for the changes, and the configured meters.
+func subscriptions(cfg config.ConsoleX32) []config.ConsoleSubscription {
+	subs := []config.ConsoleSubscription{{
+		OSCCommand:   config.OSCCommand{Address: "/xremote", Comment: "x32 remote updates"},
+		RepeatMillis: renewMillis,
+	}}
+
+	for _, m := range cfg.Meters {
+		args := []config.OSCArgument{{Type: "string", Value: fmt.Sprintf("/meters/%d", m.ID)}}
+		for _, a := range m.Arguments {
+			args = append(args, config.OSCArgument{Type: "int32", Value: strconv.Itoa(a)})
+		}
+		if m.TimeFactor > 0 {
+			args = append(args, config.OSCArgument{Type: "int32", Value: strconv.Itoa(m.TimeFactor)})
+		}
+
+		subs = append(subs, config.ConsoleSubscription{
+			OSCCommand:   config.OSCCommand{Address: "/meters", Arguments: args, Comment: fmt.Sprintf("x32 meters %d", m.ID)},
+			RepeatMillis: renewMillis,
+		})
+	}
+	return subs
+}
+
+func (c *Connection) Start(ctx context.Context) error {
+	if err := c.conn.Start(ctx); err != nil {
+		return err
+	}
+
+	go c.decodeMessages(ctx)
+
+	if !c.cfg.X32.DisableDiscovery {
+		go c.discover(ctx)
+	}
+	return nil
+}
+
+// decodeMessages forwards the messages of the underlying connection, replacing the meters and nodes with their values.
+func (c *Connection) decodeMessages(ctx context.Context) {
+	in := c.conn.GetEventChan(ctx)
+	for {
+		var msg usecaseifs.IOSCMessage
+		select {
+		case msg = <-in:
+		case <-c.quit:
+			return
+		}
+
+		msgs, err := decode(msg)
+		if err != nil {
+			c.log.Err(ctx, err)
+			continue
+		}
+
+		for _, m := range msgs {
+			select {
+			case c.messages <- m:
+			case <-c.quit:
+				return
+			}
+		}
+	}
+}
+
+// decode converts the meter blobs and node responses, the rest of the messages are returned as they are.
+func decode(msg usecaseifs.IOSCMessage) ([]usecaseifs.IOSCMessage, error) {
+	args := msg.GetArguments()
+
+	if isNodeResponse(msg.GetAddress()) && len(args) == 1 && args[0].GetType() == "string" {
+		msgs, err := ParseNode(args[0].GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse node response: %w", err)
+		}
+		return msgs, nil
+	}
+
+	if metersAddressRe.MatchString(msg.GetAddress()) && len(args) == 1 && args[0].GetType() == "blob" {
+		msgs, err := DecodeMeters(msg.GetAddress(), args[0].GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", msg.GetAddress(), err)
+		}
+		return msgs, nil
+	}
+
+	return []usecaseifs.IOSCMessage{msg}, nil
+}
+
+// discover queries the config (name, icon, color, source) of the input channels.
+func (c *Connection) discover(ctx context.Context) {
+	channels := c.cfg.X32.Channels
+	if channels == 0 {
+		channels = 32
+	}
+
+	if c.cfg.Debug {
+		c.log.Infof(ctx, "Discovering %d x32 channels...", channels)
+	}
+
+	for ch := 1; ch <= channels; ch++ {
+		node := fmt.Sprintf("ch/%02d/config", ch)
+		if err := c.QueryNode(ctx, node); err != nil {
+			c.log.Err(ctx, fmt.Errorf("failed to query %s: %w", node, err))
+		}
+
+		select {
+		case <-c.quit:
+			return
+		case <-time.After(queryInterval):
+		}
+	}
+}
+
+// QueryNode asks the console for the values of a node (e.g. "ch/01/mix"), the response arrives as separate messages.
+func (c *Connection) QueryNode(ctx context.Context, node string) error {
+	msg := osc_message.NewMessage("/node", []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("string", node)})
+	return c.conn.SendMessage(ctx, msg)
+}
+
+// IsUp tells if the last connection check of the underlying connection succeeded.
+func (c *Connection) IsUp() bool {
+	if health, ok := c.conn.(usecaseifs.IConnectionHealth); ok {
+		return health.IsUp()
+	}
+	return true
+}
+
+// Notify returns the notification channel that can be used to listen for the client's exit
+func (c *Connection) Notify() <-chan error {
+	return c.conn.Notify()
+}
+
+func (c *Connection) Stop(ctx context.Context) {
+	if chantools.ChanIsOpenReader(c.quit) {
+		close(c.quit)
+	}
+	c.conn.Stop(ctx)
+}
+
+func (c *Connection) GetEventChan(ctx context.Context) <-chan usecaseifs.IOSCMessage {
+	return c.messages
+}
+
+func (c *Connection) SendMessage(ctx context.Context, msg usecaseifs.IOSCMessage) error {
+	return c.conn.SendMessage(ctx, msg)
+}
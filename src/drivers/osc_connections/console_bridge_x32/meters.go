@@ -0,0 +1,56 @@
+package console_bridge_x32
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"regexp"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var metersAddressRe = regexp.MustCompile(`^/meters/\d+$`)
+
+// DecodeMeters converts a base64 encoded meter blob to a message per meter, numbered from 1, e.g. /meters/1/1.
+// The values are linear levels, 1.0 is 0 dBFS.
+//
+// The blob starts with the number of the values as a little endian int32. The X32 sends little endian float32 levels,
+// while the X-Air consoles send little endian int16 dB values in 1/256 dB units.
+func DecodeMeters(address string, encoded string) ([]usecaseifs.IOSCMessage, error) {
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob: %w", err)
+	}
+	if len(blob) < 4 {
+		return nil, fmt.Errorf("the blob is too short (%d bytes)", len(blob))
+	}
+
+	count := int(int32(binary.LittleEndian.Uint32(blob)))
+	data := blob[4:]
+
+	var levels []float64
+	switch {
+	case count < 0:
+		return nil, fmt.Errorf("invalid number of values: %d", count)
+	case len(data) >= 4*count:
+		for i := 0; i < count; i++ {
+			levels = append(levels, float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))))
+		}
+	case len(data) >= 2*count:
+		for i := 0; i < count; i++ {
+			db := float64(int16(binary.LittleEndian.Uint16(data[2*i:]))) / 256
+			levels = append(levels, math.Pow(10, db/20))
+		}
+	default:
+		return nil, fmt.Errorf("the blob of %d bytes is too short for %d values", len(blob), count)
+	}
+
+	msgs := make([]usecaseifs.IOSCMessage, 0, len(levels))
+	for i, level := range levels {
+		msgs = append(msgs, osc_message.NewMessage(fmt.Sprintf("%s/%d", address, i+1),
+			[]usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", level))}))
+	}
+	return msgs, nil
+}
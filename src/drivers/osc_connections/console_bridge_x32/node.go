@@ -0,0 +1,149 @@
+package console_bridge_x32
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/x32"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// fieldKind determines how a value of a node response is converted, to match the type of its OSC message.
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	// kindOnOff is ON/OFF, converted to 1/0.
+	kindOnOff
+	// kindColor is a color name (e.g. YE), converted to its number.
+	kindColor
+	// kindLevel is a dB level (e.g. -10.5, -oo), converted to a fader float.
+	kindLevel
+	// kindPan is -100..+100, converted to 0..1.
+	kindPan
+)
+
+type nodeField struct {
+	name string
+	kind fieldKind
+}
+
+// nodeFields names the values of the known nodes, the values of the others are numbered from 1.
+var nodeFields = []struct {
+	pattern *regexp.Regexp
+	fields  []nodeField
+}{
+	{
+		pattern: regexp.MustCompile(`^/((ch|auxin|fxrtn|bus|mtx|dca)/\d+|main/(st|m))/config$`),
+		fields:  []nodeField{{"name", kindString}, {"icon", kindInt}, {"color", kindColor}, {"source", kindInt}},
+	},
+	{
+		pattern: regexp.MustCompile(`^/(ch|auxin|fxrtn|bus)/\d+/mix$`),
+		fields:  []nodeField{{"on", kindOnOff}, {"fader", kindLevel}, {"st", kindOnOff}, {"pan", kindPan}, {"mono", kindOnOff}, {"mlevel", kindLevel}},
+	},
+	{
+		pattern: regexp.MustCompile(`^/main/(st|m)/mix$`),
+		fields:  []nodeField{{"on", kindOnOff}, {"fader", kindLevel}, {"pan", kindPan}},
+	},
+	{
+		pattern: regexp.MustCompile(`^/(mtx/\d+/mix|dca/\d+)$`),
+		fields:  []nodeField{{"on", kindOnOff}, {"fader", kindLevel}},
+	},
+}
+
+// isNodeResponse tells if the address is of a /node response, the consoles answer on "node" without the slash.
+func isNodeResponse(address string) bool {
+	return address == "node" || address == "/node"
+}
+
+// ParseNode converts the text of a /node response, e.g. `/ch/01/config "Kick" 1 YE 1`, to a message per value, e.g.
+// /ch/01/config/name "Kick", /ch/01/config/icon 1, /ch/01/config/color 3, /ch/01/config/source 1.
+func ParseNode(text string) ([]usecaseifs.IOSCMessage, error) {
+	tokens, err := splitNode(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("the response is empty")
+	}
+
+	node := tokens[0]
+	if !strings.HasPrefix(node, "/") {
+		node = "/" + node
+	}
+
+	var fields []nodeField
+	for _, nf := range nodeFields {
+		if nf.pattern.MatchString(node) {
+			fields = nf.fields
+			break
+		}
+	}
+
+	msgs := []usecaseifs.IOSCMessage{}
+	for i, value := range tokens[1:] {
+		field := nodeField{name: strconv.Itoa(i + 1), kind: kindString}
+		if i < len(fields) {
+			field = fields[i]
+		}
+
+		msgs = append(msgs, osc_message.NewMessage(node+"/"+field.name, []usecaseifs.IOSCMessageArgument{convertNodeValue(value, field.kind)}))
+	}
+	return msgs, nil
+}
+
+// convertNodeValue converts a single value, it is kept as a string if it can not be converted.
+func convertNodeValue(value string, kind fieldKind) usecaseifs.IOSCMessageArgument {
+	switch kind {
+	case kindInt:
+		if i, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return osc_message.NewMessageArgument("int32", strconv.FormatInt(i, 10))
+		}
+	case kindOnOff:
+		switch value {
+		case "ON":
+			return osc_message.NewMessageArgument("int32", "1")
+		case "OFF":
+			return osc_message.NewMessageArgument("int32", "0")
+		}
+	case kindColor:
+		if color, err := x32.ColorToInt(value); err == nil {
+			return osc_message.NewMessageArgument("int32", strconv.Itoa(color))
+		}
+	case kindLevel:
+		if db, err := x32.ParseDB(value); err == nil {
+			return osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", x32.DBToFader(db)))
+		}
+	case kindPan:
+		if pan, err := strconv.ParseFloat(strings.TrimPrefix(value, "+"), 64); err == nil {
+			return osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", (pan+100)/200))
+		}
+	case kindString:
+	}
+	return osc_message.NewMessageArgument("string", value)
+}
+
+// splitNode splits the text by spaces, keeping the double-quoted values (e.g. names) together, without the quotes.
+func splitNode(text string) ([]string, error) {
+	tokens := []string{}
+	for text != "" {
+		if text[0] == '"' {
+			end := strings.IndexByte(text[1:], '"')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated quote in '%s'", text)
+			}
+			tokens = append(tokens, text[1:end+1])
+			text = strings.TrimLeft(text[end+2:], " ")
+			continue
+		}
+
+		token, rest, _ := strings.Cut(text, " ")
+		tokens = append(tokens, token)
+		text = strings.TrimLeft(rest, " ")
+	}
+	return tokens, nil
+}
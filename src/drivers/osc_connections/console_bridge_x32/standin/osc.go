@@ -0,0 +1,30 @@
+package standin
+
+import (
+	"fmt"
+
+	"github.com/loffa/gosc"
+	"net.kopias.oscbridge/app/drivers/osc_connections/console_bridge_x32"
+)
+
+// message is a decoded OSC message, the arguments are int32, float32, string or []byte values.
+type message struct {
+	address string
+	args    []any
+}
+
+func (m message) String() string {
+	return fmt.Sprintf("%s %v", m.address, m.args)
+}
+
+func encode(m message) ([]byte, error) {
+	return console_bridge_x32.EncodeMessage(&gosc.Message{Address: m.address, Arguments: m.args})
+}
+
+func decode(packet []byte) (message, error) {
+	msg, err := console_bridge_x32.DecodeMessage(packet)
+	if err != nil {
+		return message{}, err
+	}
+	return message{address: msg.Address, args: msg.Arguments}, nil
+}
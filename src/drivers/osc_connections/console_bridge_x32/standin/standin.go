@@ -0,0 +1,352 @@
+// Package standin is a minimal Behringer X32 console, to test the console bridges without the actual hardware.
+// It keeps the parameters in memory, answers the queries, /xinfo, /status and /node, pushes the changes to the
+// /xremote clients, and sends generated levels to the /meters subscribers.
+package standin
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"net.kopias.oscbridge/app/pkg/x32"
+)
+
+const (
+	// subscriptionTTL is the time the console keeps the /xremote and /meters subscriptions.
+	subscriptionTTL = 10 * time.Second
+	// meterInterval is the update interval of the meters with time factor 1.
+	meterInterval = 50 * time.Millisecond
+	// meterCount is the number of the levels in each meter bank.
+	meterCount = 96
+)
+
+// meterSubscription is a client receiving a meter bank.
+type meterSubscription struct {
+	addr     *net.UDPAddr
+	bank     string
+	interval time.Duration
+	expires  time.Time
+	next     time.Time
+}
+
+// Console is a stand-in X32 console listening on UDP.
+type Console struct {
+	conn *net.UDPConn
+	quit chan any
+
+	m       *sync.Mutex
+	params  map[string][]any
+	remotes map[string]*net.UDPAddr
+	expires map[string]time.Time
+	meters  map[string]*meterSubscription
+
+	// OnMessage is called after each received message, if set.
+	OnMessage func(from *net.UDPAddr, address string, args []any)
+}
+
+// NewConsole starts listening on [address], e.g. "127.0.0.1:10023", or "127.0.0.1:0" for a random port.
+func NewConsole(address string) (*Console, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Console{
+		conn:    conn,
+		quit:    make(chan any),
+		m:       &sync.Mutex{},
+		params:  defaultParams(),
+		remotes: map[string]*net.UDPAddr{},
+		expires: map[string]time.Time{},
+		meters:  map[string]*meterSubscription{},
+	}, nil
+}
+
+// defaultParams returns the parameters of the channels, the main stereo bus and the DCAs.
+func defaultParams() map[string][]any {
+	params := map[string][]any{}
+	for ch := 1; ch <= 32; ch++ {
+		prefix := fmt.Sprintf("/ch/%02d", ch)
+		params[prefix+"/config/name"] = []any{fmt.Sprintf("CH %02d", ch)}
+		params[prefix+"/config/icon"] = []any{int32(1)}
+		params[prefix+"/config/color"] = []any{int32(ch % 8)}
+		params[prefix+"/config/source"] = []any{int32(ch)}
+		params[prefix+"/mix/on"] = []any{int32(1)}
+		params[prefix+"/mix/fader"] = []any{float32(0.75)}
+		params[prefix+"/mix/st"] = []any{int32(1)}
+		params[prefix+"/mix/pan"] = []any{float32(0.5)}
+		params[prefix+"/mix/mono"] = []any{int32(0)}
+		params[prefix+"/mix/mlevel"] = []any{float32(0)}
+	}
+
+	params["/main/st/mix/on"] = []any{int32(1)}
+	params["/main/st/mix/fader"] = []any{float32(0.75)}
+	params["/main/st/mix/pan"] = []any{float32(0.5)}
+
+	for dca := 1; dca <= 8; dca++ {
+		params[fmt.Sprintf("/dca/%d/on", dca)] = []any{int32(1)}
+		params[fmt.Sprintf("/dca/%d/fader", dca)] = []any{float32(0.75)}
+	}
+	return params
+}
+
+// Addr returns the address the console is listening on.
+func (c *Console) Addr() *net.UDPAddr {
+	// nolint:forcetypeassert
+	return c.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Get returns the arguments of a parameter, or nil if there is no such parameter.
+func (c *Console) Get(address string) []any {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.params[address]
+}
+
+// Set changes a parameter as if it was changed on the console itself, and pushes it to the /xremote clients.
+func (c *Console) Set(address string, args ...any) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.set(nil, message{address: address, args: args})
+}
+
+// set stores a parameter and pushes it to the /xremote clients except its sender, the lock must be held.
+func (c *Console) set(from *net.UDPAddr, msg message) {
+	c.params[msg.address] = msg.args
+
+	now := time.Now()
+	for key, addr := range c.remotes {
+		if now.After(c.expires[key]) {
+			delete(c.remotes, key)
+			delete(c.expires, key)
+			continue
+		}
+		if from != nil && key == from.String() {
+			continue
+		}
+		c.send(addr, msg)
+	}
+}
+
+// Serve answers the incoming packets until the console is closed.
+func (c *Console) Serve() error {
+	go c.sendMeters()
+
+	buf := make([]byte, 65536)
+	for {
+		n, from, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		msg, err := decode(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		c.m.Lock()
+		c.handle(from, msg)
+		c.m.Unlock()
+
+		if c.OnMessage != nil {
+			c.OnMessage(from, msg.address, msg.args)
+		}
+	}
+}
+
+// Close stops the console.
+func (c *Console) Close() error {
+	close(c.quit)
+	return c.conn.Close()
+}
+
+// handle executes a message, the lock must be held.
+func (c *Console) handle(from *net.UDPAddr, msg message) {
+	switch msg.address {
+	case "/xinfo":
+		c.send(from, message{address: "/xinfo", args: []any{c.Addr().IP.String(), "osc-bridge-x32", "X32", "4.06"}})
+	case "/status":
+		c.send(from, message{address: "/status", args: []any{"active", c.Addr().IP.String(), "osc-bridge-x32"}})
+	case "/xremote":
+		c.remotes[from.String()] = from
+		c.expires[from.String()] = time.Now().Add(subscriptionTTL)
+	case "/node":
+		if len(msg.args) == 1 {
+			if node, ok := msg.args[0].(string); ok {
+				c.send(from, message{address: "node", args: []any{c.node(node) + "\n"}})
+			}
+		}
+	case "/meters", "/renew":
+		c.subscribeMeters(from, msg)
+	default:
+		if _, ok := c.params[msg.address]; !ok {
+			return
+		}
+		if len(msg.args) == 0 {
+			c.send(from, message{address: msg.address, args: c.params[msg.address]})
+			return
+		}
+		c.set(from, msg)
+	}
+}
+
+// nodeFields are the values of the nodes in the order of the node responses, the values of the rest are sorted.
+var nodeFields = map[string][]string{
+	"config": {"name", "icon", "color", "source"},
+	"mix":    {"on", "fader", "st", "pan", "mono", "mlevel"},
+	"dca":    {"on", "fader"},
+}
+
+// node formats the values of a node as the console does, e.g. `/ch/01/config "CH 01" 1 GN 1`.
+func (c *Console) node(node string) string {
+	node = "/" + strings.TrimPrefix(node, "/")
+
+	fields := nodeFields[node[strings.LastIndex(node, "/")+1:]]
+	if strings.HasPrefix(node, "/dca/") && strings.Count(node, "/") == 2 {
+		fields = nodeFields["dca"]
+	}
+	if fields == nil {
+		for address := range c.params {
+			if field, ok := strings.CutPrefix(address, node+"/"); ok && !strings.Contains(field, "/") {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+	}
+
+	values := []string{node}
+	for _, field := range fields {
+		args, ok := c.params[node+"/"+field]
+		if !ok || len(args) == 0 {
+			continue
+		}
+		values = append(values, formatNodeValue(field, args[0]))
+	}
+	return strings.Join(values, " ")
+}
+
+func formatNodeValue(field string, value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case int32:
+		switch field {
+		case "on", "st", "mono":
+			if v == 0 {
+				return "OFF"
+			}
+			return "ON"
+		case "color":
+			return x32.ColorName(int(v))
+		}
+		return fmt.Sprintf("%d", v)
+	case float32:
+		switch field {
+		case "fader", "mlevel":
+			db := x32.RoundDB(x32.FaderToDB(float64(v)))
+			if db >= 0 {
+				return "+" + x32.FormatDB(db)
+			}
+			return x32.FormatDB(db)
+		case "pan":
+			return fmt.Sprintf("%+.0f", float64(v)*200-100)
+		}
+		return fmt.Sprintf("%.4f", v)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// subscribeMeters handles /meters ,s[i...] bank [arguments] [time factor] and /renew ,s bank.
+func (c *Console) subscribeMeters(from *net.UDPAddr, msg message) {
+	if len(msg.args) == 0 {
+		return
+	}
+	bank, ok := msg.args[0].(string)
+	if !ok {
+		return
+	}
+
+	key := from.String() + bank
+	if msg.address == "/renew" {
+		if sub, ok := c.meters[key]; ok {
+			sub.expires = time.Now().Add(subscriptionTTL)
+		}
+		return
+	}
+
+	interval := meterInterval
+	if len(msg.args) > 1 {
+		if tf, ok := msg.args[len(msg.args)-1].(int32); ok && tf > 0 {
+			interval = time.Duration(tf) * meterInterval
+		}
+	}
+
+	c.meters[key] = &meterSubscription{addr: from, bank: bank, interval: interval, expires: time.Now().Add(subscriptionTTL)}
+}
+
+// sendMeters sends the meter blobs to the subscribers, the levels follow the faders, with a slow wave on them.
+func (c *Console) sendMeters() {
+	ticker := time.NewTicker(meterInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			return
+		case now := <-ticker.C:
+			c.m.Lock()
+			for key, sub := range c.meters {
+				if now.After(sub.expires) {
+					delete(c.meters, key)
+					continue
+				}
+				if now.Before(sub.next) {
+					continue
+				}
+				sub.next = now.Add(sub.interval)
+				c.send(sub.addr, message{address: sub.bank, args: []any{c.meterBlob(now)}})
+			}
+			c.m.Unlock()
+		}
+	}
+}
+
+// meterBlob returns a blob of little endian float32 levels, preceded by their number, the lock must be held.
+func (c *Console) meterBlob(now time.Time) []byte {
+	blob := make([]byte, 4+4*meterCount)
+	binary.LittleEndian.PutUint32(blob, meterCount)
+
+	phase := float64(now.UnixMilli()%4000) / 4000 * 2 * math.Pi
+	for i := 0; i < meterCount; i++ {
+		fader := float32(0)
+		if args := c.params[fmt.Sprintf("/ch/%02d/mix/fader", i%32+1)]; len(args) > 0 {
+			fader, _ = args[0].(float32)
+		}
+		level := float64(fader) * (0.55 + 0.45*math.Sin(phase+float64(i)))
+		binary.LittleEndian.PutUint32(blob[4+4*i:], math.Float32bits(float32(level)))
+	}
+	return blob
+}
+
+// send sends a message, the errors are ignored as the console does.
+func (c *Console) send(to *net.UDPAddr, msg message) {
+	packet, err := encode(msg)
+	if err != nil {
+		return
+	}
+	_, _ = c.conn.WriteToUDP(packet, to)
+}
@@ -0,0 +1,210 @@
+package obs_bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/andreykaipov/goobs/api/events"
+	"net.kopias.oscbridge/app/adapters/config"
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// MappingArgumentTypes lists the valid types of the mapped arguments.
+var MappingArgumentTypes = []string{"string", "int32", "float32", "bool"}
+
+// GenericEventAddressPrefix is the prefix of the generically published events.
+const GenericEventAddressPrefix = "/obs/events/"
+
+var addressFieldRe = regexp.MustCompile(`{(\w+)}`)
+
+type compiledMapping struct {
+	config.OBSEventMapping
+	// addressFields are the fields referred to by the address.
+	addressFields []string
+}
+
+// compileEventMappings verifies, that the mapped events and fields exist.
+func (c *OBSBridge) compileEventMappings() error {
+	c.mappings = map[string][]*compiledMapping{}
+
+	for i, mapping := range c.cfg.EventMappings {
+		event := events.GetType(mapping.Event)
+		if event == nil {
+			return fmt.Errorf("invalid event mapping #%d: unknown event: '%s'", i, mapping.Event)
+		}
+		eventType := reflect.TypeOf(event).Elem()
+
+		compiled := &compiledMapping{OBSEventMapping: mapping}
+		for _, match := range addressFieldRe.FindAllStringSubmatch(mapping.Address, -1) {
+			compiled.addressFields = append(compiled.addressFields, match[1])
+		}
+
+		fields := compiled.addressFields
+		for _, arg := range mapping.Arguments {
+			if slicetools.IndexOf(MappingArgumentTypes, arg.Type) == -1 {
+				return fmt.Errorf("invalid event mapping #%d: invalid argument type: '%s'", i, arg.Type)
+			}
+			fields = append(fields, arg.Field)
+		}
+
+		for _, field := range fields {
+			if _, ok := eventType.FieldByName(field); !ok {
+				return fmt.Errorf("invalid event mapping #%d: %s has no field named '%s'", i, mapping.Event, field)
+			}
+		}
+
+		c.mappings[mapping.Event] = append(c.mappings[mapping.Event], compiled)
+	}
+	return nil
+}
+
+// applyEventMappings publishes the messages of the mappings of the event, returns true if there was any.
+func (c *OBSBridge) applyEventMappings(ctx context.Context, event interface{}) bool {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	value = value.Elem()
+
+	mappings := c.mappings[value.Type().Name()]
+	for _, mapping := range mappings {
+		msg, err := mapping.convert(value)
+		if err != nil {
+			c.log.Warnf(ctx, "Failed to map %s: %s", mapping.Event, err)
+			continue
+		}
+		c.publishMessage(msg)
+	}
+	return len(mappings) > 0
+}
+
+func (m *compiledMapping) convert(event reflect.Value) (usecaseifs.IOSCMessage, error) {
+	address := addressFieldRe.ReplaceAllStringFunc(m.Address, func(s string) string {
+		return fmt.Sprintf("%v", event.FieldByName(s[1:len(s)-1]).Interface())
+	})
+
+	args := []usecaseifs.IOSCMessageArgument{}
+	for _, arg := range m.Arguments {
+		value, err := convertValue(event.FieldByName(arg.Field), arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", arg.Field, err)
+		}
+		args = append(args, osc_message.NewMessageArgument(arg.Type, value))
+	}
+
+	return osc_message.NewMessage(address, args), nil
+}
+
+// publishGenericEvent publishes every field of the event as /obs/events/<event>/<field>, with a type inferred from the field.
+func (c *OBSBridge) publishGenericEvent(ctx context.Context, event interface{}) {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	value = value.Elem()
+
+	prefix := GenericEventAddressPrefix + value.Type().Name()
+
+	// Events without fields (e.g. ExitStarted) are published too.
+	if value.NumField() == 0 {
+		c.publishMessage(osc_message.NewMessage(prefix, []usecaseifs.IOSCMessageArgument{}))
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		argType := inferType(field)
+
+		argValue, err := convertValue(field, argType)
+		if err != nil {
+			c.log.Warnf(ctx, "Failed to publish %s: %s", prefix, err)
+			continue
+		}
+
+		c.publishMessage(osc_message.NewMessage(prefix+"/"+value.Type().Field(i).Name, []usecaseifs.IOSCMessageArgument{
+			osc_message.NewMessageArgument(argType, argValue),
+		}))
+	}
+}
+
+// inferType returns the argument type matching the kind of a field, complex values become (JSON) strings.
+func inferType(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return "int32"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int32"
+	case reflect.Float32, reflect.Float64:
+		return "float32"
+	default:
+		return "string"
+	}
+}
+
+// convertValue converts a field to the string value of an argument of [argType].
+func convertValue(v reflect.Value, argType string) (string, error) {
+	var number float64
+	isNumber := true
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			number = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number = float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		number = v.Float()
+	case reflect.String:
+		var err error
+		if number, err = strconv.ParseFloat(v.String(), 64); err != nil {
+			isNumber = false
+		}
+	default:
+		isNumber = false
+	}
+
+	switch argType {
+	case "int32":
+		if !isNumber {
+			return "", fmt.Errorf("can not convert %v to int32", v.Interface())
+		}
+		return fmt.Sprintf("%d", int32(number)), nil
+
+	case "float32":
+		if !isNumber {
+			return "", fmt.Errorf("can not convert %v to float32", v.Interface())
+		}
+		return fmt.Sprintf("%f", number), nil
+
+	case "bool":
+		if v.Kind() == reflect.String {
+			b, err := strconv.ParseBool(v.String())
+			if err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		if !isNumber {
+			return "", fmt.Errorf("can not convert %v to bool", v.Interface())
+		}
+		return strconv.FormatBool(number != 0), nil
+
+	default:
+		switch v.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Struct, reflect.Pointer, reflect.Interface:
+			b, err := json.Marshal(v.Interface())
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		default:
+			return fmt.Sprintf("%v", v.Interface()), nil
+		}
+	}
+}
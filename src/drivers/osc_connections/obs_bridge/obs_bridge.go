@@ -3,8 +3,10 @@ package obs_bridge
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/andreykaipov/goobs/api/events"
+	"net.kopias.oscbridge/app/adapters/config"
 	"net.kopias.oscbridge/app/drivers/osc_message"
 
 	"net.kopias.oscbridge/app/drivers/obsremote"
@@ -17,6 +19,12 @@ var _ usecaseifs.IOSCConnection = &OBSBridge{}
 type Config struct {
 	Debug      bool
 	Connection *obsremote.OBSRemote
+	// MediaRefreshRateMillis is the interval of polling the cursor of the playing media inputs.
+	MediaRefreshRateMillis int64
+	// EventMappings convert further events to messages.
+	EventMappings []config.OBSEventMapping
+	// PublishUnhandledEvents publishes the fields of the events, that are neither built-in nor mapped.
+	PublishUnhandledEvents bool
 }
 
 // OBSBridge uses a named OBS Connection, subscribes for certain events from OBS and emits an OSC Message when an update is received.
@@ -27,6 +35,20 @@ type OBSBridge struct {
 	quit   chan any
 	cfg    Config
 	notify chan error
+
+	// mappings are the compiled event mappings by event name.
+	mappings map[string][]*compiledMapping
+
+	// sceneItems maps the scene item ids to source names by scene, as the events only contain the id.
+	sceneItems map[string]map[int]string
+
+	// initialMessages collects the messages of the initialization, as they are only consumed after all the sources started.
+	initialMessages []usecaseifs.IOSCMessage
+	initializing    bool
+
+	mediaM *sync.Mutex
+	// playingMedia lists the media inputs, of which the cursor is polled.
+	playingMedia map[string]bool
 }
 
 func NewOBSBridge(log usecaseifs.ILogger, cfg Config) usecaseifs.IOSCConnection {
@@ -36,15 +58,33 @@ func NewOBSBridge(log usecaseifs.ILogger, cfg Config) usecaseifs.IOSCConnection
 		quit:     make(chan any),
 		messages: make(chan usecaseifs.IOSCMessage, 10),
 		notify:   make(chan error, 1),
+
+		sceneItems:   map[string]map[int]string{},
+		mediaM:       &sync.Mutex{},
+		playingMedia: map[string]bool{},
 	}
 }
 
 func (c *OBSBridge) Start(ctx context.Context) error {
-	if err := c.initialize(ctx); err != nil {
+	if err := c.compileEventMappings(); err != nil {
+		return err
+	}
+
+	c.initializing = true
+	err := c.initialize(ctx)
+	c.initializing = false
+	if err != nil {
 		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
-	go c.listen(ctx)
+	go func() {
+		for _, msg := range c.initialMessages {
+			c.send(msg)
+		}
+		c.initialMessages = nil
+		c.listen(ctx)
+	}()
+	go c.pollMedia(ctx)
 	return nil
 }
 
@@ -93,7 +133,7 @@ func (c *OBSBridge) initialize(ctx context.Context) error {
 	}
 	c.handleObsEvent(ctx, &events.RecordStateChanged{OutputActive: isRecording})
 
-	return nil
+	return c.initializeState(ctx)
 }
 
 // listen watches for incoming messages from OBS.
@@ -112,36 +152,106 @@ func (c *OBSBridge) listen(ctx context.Context) {
 	}
 }
 
-// handleObsEvent filters the incoming events and converts the appropriate ones to OSC Messages.
+// handleObsEvent converts the incoming events to OSC Messages, by the built-in handlers and the configured mappings.
+// The rest of the events are published generically, if it is enabled.
 func (c *OBSBridge) handleObsEvent(ctx context.Context, event interface{}) {
 	// c.log.Debugf(ctx, "INCOMING OBS EVENT: %#v", event)
 
+	handled := c.handleKnownEvent(ctx, event)
+	if c.applyEventMappings(ctx, event) {
+		handled = true
+	}
+
+	if !handled && c.cfg.PublishUnhandledEvents {
+		c.publishGenericEvent(ctx, event)
+	}
+}
+
+// handleKnownEvent filters the incoming events and converts the appropriate ones to OSC Messages.
+func (c *OBSBridge) handleKnownEvent(ctx context.Context, event interface{}) bool {
 	switch t := event.(type) {
 	case *events.CurrentPreviewSceneChanged:
-		args := []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("string", t.SceneName)}
-		c.messages <- osc_message.NewMessage("/obs/preview_scene", args)
+		c.publish("/obs/preview_scene", "string", t.SceneName)
 
 	case *events.CurrentProgramSceneChanged:
-		args := []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("string", t.SceneName)}
-		c.messages <- osc_message.NewMessage("/obs/program_scene", args)
+		c.publish("/obs/program_scene", "string", t.SceneName)
 
 	case *events.RecordStateChanged:
-		active := "0"
-		if t.OutputActive {
-			active = "1"
-		}
-		args := []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("int32", active)}
-		c.messages <- osc_message.NewMessage("/obs/recording", args)
+		c.publish("/obs/recording", "int32", boolToInt(t.OutputActive))
 
 	case *events.StreamStateChanged:
-		active := "0"
-		if t.OutputActive {
-			active = "1"
-		}
-		args := []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("int32", active)}
-		c.messages <- osc_message.NewMessage("/obs/streaming", args)
+		c.publish("/obs/streaming", "int32", boolToInt(t.OutputActive))
+
+	case *events.ReplayBufferStateChanged:
+		c.publish("/obs/replay_buffer", "int32", boolToInt(t.OutputActive))
+
+	case *events.VirtualcamStateChanged:
+		c.publish("/obs/virtual_cam", "int32", boolToInt(t.OutputActive))
+
+	case *events.StudioModeStateChanged:
+		c.publish("/obs/studio_mode", "int32", boolToInt(t.StudioModeEnabled))
+
+	case *events.CurrentSceneTransitionChanged:
+		c.publish("/obs/transition", "string", t.TransitionName)
+
+	case *events.CurrentSceneTransitionDurationChanged:
+		c.publish("/obs/transition_duration", "int32", fmt.Sprintf("%d", int(t.TransitionDuration)))
+
+	case *events.InputMuteStateChanged:
+		c.publish(fmt.Sprintf("/obs/input/%s/muted", t.InputName), "int32", boolToInt(t.InputMuted))
+
+	case *events.InputVolumeChanged:
+		c.publish(fmt.Sprintf("/obs/input/%s/volume_db", t.InputName), "float32", fmt.Sprintf("%f", t.InputVolumeDb))
+		c.publish(fmt.Sprintf("/obs/input/%s/volume_mul", t.InputName), "float32", fmt.Sprintf("%f", t.InputVolumeMul))
+
+	case *events.SceneItemEnableStateChanged:
+		sourceName := c.getSceneItemSourceName(ctx, t.SceneName, int(t.SceneItemId))
+		c.publish(fmt.Sprintf("/obs/scene/%s/item/%s/enabled", t.SceneName, sourceName), "int32", boolToInt(t.SceneItemEnabled))
+
+	case *events.SourceFilterEnableStateChanged:
+		c.publish(fmt.Sprintf("/obs/source/%s/filter/%s/enabled", t.SourceName, t.FilterName), "int32", boolToInt(t.FilterEnabled))
+
+	case *events.MediaInputPlaybackStarted:
+		c.updateMediaStatus(ctx, t.InputName)
+
+	case *events.MediaInputPlaybackEnded:
+		c.updateMediaStatus(ctx, t.InputName)
+
+	case *events.MediaInputActionTriggered:
+		c.updateMediaStatus(ctx, t.InputName)
 
 	default:
 		// c.log.Debugf(ctx, "UNHANDLED INCOMING OBS EVENT: %#v", t)
+		return false
+	}
+	return true
+}
+
+// publish emits a message with a single argument.
+func (c *OBSBridge) publish(address string, argType string, value string) {
+	c.publishMessage(osc_message.NewMessage(address, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument(argType, value)}))
+}
+
+// publishMessage emits a message, or collects it during the initialization.
+func (c *OBSBridge) publishMessage(msg usecaseifs.IOSCMessage) {
+	if c.initializing {
+		c.initialMessages = append(c.initialMessages, msg)
+		return
+	}
+	c.send(msg)
+}
+
+// send emits a message, unless the bridge was stopped.
+func (c *OBSBridge) send(msg usecaseifs.IOSCMessage) {
+	select {
+	case c.messages <- msg:
+	case <-c.quit:
+	}
+}
+
+func boolToInt(b bool) string {
+	if b {
+		return "1"
 	}
+	return "0"
 }
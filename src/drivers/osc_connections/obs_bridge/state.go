@@ -0,0 +1,191 @@
+package obs_bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreykaipov/goobs/api/events"
+)
+
+// mediaInputKinds are the kinds of inputs, that have a playback state.
+var mediaInputKinds = []string{"ffmpeg_source", "vlc_source"}
+
+// initializeState retrieves the initial values of the inputs, transitions, outputs, scene items and filters.
+func (c *OBSBridge) initializeState(ctx context.Context) error {
+	studioMode, err := c.cfg.Connection.IsStudioModeEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	c.handleObsEvent(ctx, &events.StudioModeStateChanged{StudioModeEnabled: studioMode})
+
+	transition, duration, err := c.cfg.Connection.GetCurrentTransition(ctx)
+	if err != nil {
+		return err
+	}
+	c.handleObsEvent(ctx, &events.CurrentSceneTransitionChanged{TransitionName: transition})
+	c.handleObsEvent(ctx, &events.CurrentSceneTransitionDurationChanged{TransitionDuration: float64(duration)})
+
+	virtualCam, err := c.cfg.Connection.IsVirtualCamActive(ctx)
+	if err != nil {
+		return err
+	}
+	c.handleObsEvent(ctx, &events.VirtualcamStateChanged{OutputActive: virtualCam})
+
+	// The replay buffer is not available, unless it is enabled in the output settings.
+	if replayBuffer, err := c.cfg.Connection.IsReplayBufferActive(ctx); err == nil {
+		c.handleObsEvent(ctx, &events.ReplayBufferStateChanged{OutputActive: replayBuffer})
+	} else {
+		c.log.Infof(ctx, "OBS replay buffer state is not published: %s", err)
+	}
+
+	inputNames, err := c.cfg.Connection.ListInputs(ctx, "")
+	if err != nil {
+		return err
+	}
+	c.initializeInputs(ctx, inputNames)
+
+	sceneNames, err := c.cfg.Connection.ListScenes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sceneName := range sceneNames {
+		if err := c.refreshSceneItems(ctx, sceneName, true); err != nil {
+			return err
+		}
+	}
+
+	c.initializeFilters(ctx, append(sceneNames, inputNames...))
+
+	for _, kind := range mediaInputKinds {
+		mediaInputs, err := c.cfg.Connection.ListInputs(ctx, kind)
+		if err != nil {
+			return err
+		}
+		for _, inputName := range mediaInputs {
+			c.updateMediaStatus(ctx, inputName)
+		}
+	}
+
+	return nil
+}
+
+// initializeInputs publishes the mute state and volume of the inputs, that have audio.
+func (c *OBSBridge) initializeInputs(ctx context.Context, inputNames []string) {
+	for _, inputName := range inputNames {
+		// The inputs without audio (e.g. images) return an error.
+		muted, err := c.cfg.Connection.IsInputMuted(ctx, inputName)
+		if err != nil {
+			if c.cfg.Debug {
+				c.log.Debugf(ctx, "Skipping the audio state of %s: %s", inputName, err)
+			}
+			continue
+		}
+		c.handleObsEvent(ctx, &events.InputMuteStateChanged{InputName: inputName, InputMuted: muted})
+
+		db, mul, err := c.cfg.Connection.GetInputVolume(ctx, inputName)
+		if err != nil {
+			c.log.Warnf(ctx, "Skipping the volume of %s: %s", inputName, err)
+			continue
+		}
+		c.handleObsEvent(ctx, &events.InputVolumeChanged{InputName: inputName, InputVolumeDb: db, InputVolumeMul: mul})
+	}
+}
+
+// initializeFilters publishes the enabled state of the filters of the given sources.
+func (c *OBSBridge) initializeFilters(ctx context.Context, sourceNames []string) {
+	for _, sourceName := range sourceNames {
+		filters, err := c.cfg.Connection.ListSourceFilters(ctx, sourceName)
+		if err != nil {
+			c.log.Warnf(ctx, "Skipping the filters of %s: %s", sourceName, err)
+			continue
+		}
+
+		for _, filter := range filters {
+			c.handleObsEvent(ctx, &events.SourceFilterEnableStateChanged{SourceName: sourceName, FilterName: filter.Name, FilterEnabled: filter.Enabled})
+		}
+	}
+}
+
+// refreshSceneItems updates the id-name map of the items of a scene, and publishes their enabled state if [publish] is set.
+func (c *OBSBridge) refreshSceneItems(ctx context.Context, sceneName string, publish bool) error {
+	items, err := c.cfg.Connection.ListSceneItems(ctx, sceneName)
+	if err != nil {
+		return err
+	}
+
+	c.sceneItems[sceneName] = map[int]string{}
+	for _, item := range items {
+		c.sceneItems[sceneName][item.ID] = item.SourceName
+	}
+
+	if publish {
+		for _, item := range items {
+			c.handleObsEvent(ctx, &events.SceneItemEnableStateChanged{SceneName: sceneName, SceneItemId: float64(item.ID), SceneItemEnabled: item.Enabled})
+		}
+	}
+	return nil
+}
+
+// getSceneItemSourceName returns the source name of a scene item, or its id, if it is not found.
+func (c *OBSBridge) getSceneItemSourceName(ctx context.Context, sceneName string, id int) string {
+	if name, ok := c.sceneItems[sceneName][id]; ok {
+		return name
+	}
+
+	// The item may have been added after the initialization.
+	if err := c.refreshSceneItems(ctx, sceneName, false); err != nil {
+		c.log.Warnf(ctx, "Failed to look up scene item %d of %s: %s", id, sceneName, err)
+	}
+
+	if name, ok := c.sceneItems[sceneName][id]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", id)
+}
+
+// updateMediaStatus publishes the playback state of a media input, and tracks it for polling, while it is playing.
+func (c *OBSBridge) updateMediaStatus(ctx context.Context, inputName string) {
+	status, err := c.cfg.Connection.GetMediaInputStatus(ctx, inputName)
+	if err != nil {
+		c.log.Warnf(ctx, "Failed to update media state: %s", err)
+		return
+	}
+
+	c.mediaM.Lock()
+	if status.State == "OBS_MEDIA_STATE_PLAYING" {
+		c.playingMedia[inputName] = true
+	} else {
+		delete(c.playingMedia, inputName)
+	}
+	c.mediaM.Unlock()
+
+	c.publish(fmt.Sprintf("/obs/media/%s/state", inputName), "string", status.State)
+	c.publish(fmt.Sprintf("/obs/media/%s/cursor", inputName), "int32", fmt.Sprintf("%d", status.CursorMillis))
+	c.publish(fmt.Sprintf("/obs/media/%s/duration", inputName), "int32", fmt.Sprintf("%d", status.DurationMillis))
+}
+
+// pollMedia updates the cursor of the playing media inputs, as OBS sends no events about it.
+func (c *OBSBridge) pollMedia(ctx context.Context) {
+	t := time.NewTicker(time.Duration(c.cfg.MediaRefreshRateMillis) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-c.quit:
+			return
+		}
+
+		c.mediaM.Lock()
+		inputNames := make([]string, 0, len(c.playingMedia))
+		for inputName := range c.playingMedia {
+			inputNames = append(inputNames, inputName)
+		}
+		c.mediaM.Unlock()
+
+		for _, inputName := range inputNames {
+			c.updateMediaStatus(ctx, inputName)
+		}
+	}
+}
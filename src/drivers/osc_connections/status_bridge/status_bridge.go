@@ -0,0 +1,160 @@
+package status_bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/pkg/chantools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IOSCConnection = &StatusBridge{}
+
+// uptimeResolution is the step of the published uptime, so it does not change the store (and trigger the evaluation
+// of the actions) on every refresh.
+const uptimeResolution = time.Minute
+
+type Config struct {
+	Debug             bool
+	RefreshRateMillis int64
+}
+
+// StatusBridge publishes the health of the bridge itself as OSC Messages:
+//
+//	/bridge/uptime                            int32, seconds since start, in whole minutes
+//	/bridge/store/size                        int32, number of records in the store
+//	/bridge/connections/<name>/up             int32, 1 if the connection is up, 0 otherwise
+//	/bridge/connections/<name>/last_message   string, RFC3339 time of the last received message
+//	/bridge/actions/<name>/last_run           string, RFC3339 time of the last finished execution
+//	/bridge/actions/<name>/last_error         string, the error of the last failed execution
+//	/bridge/actions/<name>/last_error_at      string, RFC3339 time of the last failed execution
+//	/bridge/actions/<name>/suppressed         int32, number of executions dropped by the rate limits
+type StatusBridge struct {
+	log      usecaseifs.ILogger
+	messages chan usecaseifs.IOSCMessage
+	// A channel that shows when the client exited with an error.
+	quit   chan any
+	cfg    Config
+	notify chan error
+
+	status      *entities.BridgeStatus
+	connections []entities.OscConnectionDetails
+}
+
+// NewStatusBridge creates a status bridge, reporting on the given connections.
+func NewStatusBridge(log usecaseifs.ILogger, cfg Config, status *entities.BridgeStatus, connections []entities.OscConnectionDetails) usecaseifs.IOSCConnection {
+	return &StatusBridge{
+		log:         log,
+		cfg:         cfg,
+		quit:        make(chan any),
+		messages:    make(chan usecaseifs.IOSCMessage, 1),
+		notify:      make(chan error, 1),
+		status:      status,
+		connections: connections,
+	}
+}
+
+func (c *StatusBridge) Start(ctx context.Context) error {
+	go c.run(ctx)
+	return nil
+}
+
+func (c *StatusBridge) run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(c.cfg.RefreshRateMillis) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if c.cfg.Debug {
+				c.log.Debugf(ctx, "Publishing bridge status...")
+			}
+			if !c.publish() {
+				return
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// publish emits the messages of the current status, returns false if the bridge was stopped meanwhile.
+func (c *StatusBridge) publish() bool {
+	snapshot := c.status.GetSnapshot()
+
+	messages := []usecaseifs.IOSCMessage{
+		newMessage("/bridge/uptime", "int32", fmt.Sprintf("%d", int(time.Since(snapshot.StartedAt).Truncate(uptimeResolution).Seconds()))),
+		newMessage("/bridge/store/size", "int32", fmt.Sprintf("%d", snapshot.StoreSize)),
+	}
+
+	for _, cd := range c.connections {
+		prefix := "/bridge/connections/" + cd.Name
+
+		up := "1"
+		if health, ok := cd.Connection.(usecaseifs.IConnectionHealth); ok && !health.IsUp() {
+			up = "0"
+		}
+		messages = append(messages, newMessage(prefix+"/up", "int32", up))
+
+		if status, ok := snapshot.Connections[cd.Name]; ok {
+			messages = append(messages, newMessage(prefix+"/last_message", "string", formatTime(status.LastMessageAt)))
+		}
+	}
+
+	for name, status := range snapshot.Actions {
+		prefix := entities.BridgeActionsAddressPrefix + name
+		if !status.LastRunAt.IsZero() {
+			messages = append(messages, newMessage(prefix+"/last_run", "string", formatTime(status.LastRunAt)))
+		}
+		if status.LastError != "" {
+			messages = append(messages,
+				newMessage(prefix+"/last_error", "string", status.LastError),
+				newMessage(prefix+"/last_error_at", "string", formatTime(status.LastErrorAt)),
+			)
+		}
+		if status.Suppressed > 0 {
+			messages = append(messages, newMessage(prefix+"/suppressed", "int32", fmt.Sprintf("%d", status.Suppressed)))
+		}
+	}
+
+	for _, msg := range messages {
+		select {
+		case c.messages <- msg:
+		case <-c.quit:
+			return false
+		}
+	}
+	return true
+}
+
+// Notify returns the notification channel that can be used to listen for the client's exit
+func (c *StatusBridge) Notify() <-chan error {
+	return c.notify
+}
+
+func (c *StatusBridge) Stop(ctx context.Context) {
+	if chantools.ChanIsOpenReader(c.quit) {
+		close(c.quit)
+	}
+}
+
+func (c *StatusBridge) GetEventChan(ctx context.Context) <-chan usecaseifs.IOSCMessage {
+	return c.messages
+}
+
+func (c *StatusBridge) SendMessage(ctx context.Context, msg usecaseifs.IOSCMessage) error {
+	return fmt.Errorf("status bridge does not support sending messages")
+}
+
+func newMessage(address string, argType string, value string) usecaseifs.IOSCMessage {
+	return osc_message.NewMessage(address, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument(argType, value)})
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
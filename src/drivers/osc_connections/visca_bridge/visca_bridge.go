@@ -0,0 +1,164 @@
+package visca_bridge
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/pkg/chantools"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var (
+	_ usecaseifs.IOSCConnection    = &VISCABridge{}
+	_ usecaseifs.IConnectionHealth = &VISCABridge{}
+)
+
+type Config struct {
+	Debug             bool
+	Connection        *viscaremote.VISCARemote
+	RefreshRateMillis int64
+}
+
+// VISCABridge polls a named VISCA connection with inquiries and emits the answers as OSC Messages:
+//
+//	/visca/power    int32, 1 if the camera is on, 0 otherwise
+//	/visca/pan      int32, pan position
+//	/visca/tilt     int32, tilt position
+//	/visca/zoom     int32, zoom position
+//	/visca/preset   int32, the current (or last recalled) preset
+//
+// The positions are only polled while the camera is on.
+type VISCABridge struct {
+	log      usecaseifs.ILogger
+	messages chan usecaseifs.IOSCMessage
+	// A channel that shows when the client exited with an error.
+	quit   chan any
+	cfg    Config
+	notify chan error
+
+	// up tells if the last poll succeeded.
+	up atomic.Bool
+}
+
+func NewVISCABridge(log usecaseifs.ILogger, cfg Config) usecaseifs.IOSCConnection {
+	return &VISCABridge{
+		log:      log,
+		cfg:      cfg,
+		quit:     make(chan any),
+		messages: make(chan usecaseifs.IOSCMessage, 10),
+		notify:   make(chan error, 1),
+	}
+}
+
+func (c *VISCABridge) Start(ctx context.Context) error {
+	go c.run(ctx)
+	return nil
+}
+
+// Notify returns the notification channel that can be used to listen for the client's exit
+func (c *VISCABridge) Notify() <-chan error {
+	return c.notify
+}
+
+func (c *VISCABridge) Stop(ctx context.Context) {
+	if chantools.ChanIsOpenReader(c.quit) {
+		close(c.quit)
+	}
+}
+
+func (c *VISCABridge) GetEventChan(ctx context.Context) <-chan usecaseifs.IOSCMessage {
+	return c.messages
+}
+
+func (c *VISCABridge) SendMessage(ctx context.Context, msg usecaseifs.IOSCMessage) error {
+	return fmt.Errorf("VISCABridge does not support sending messages")
+}
+
+// IsUp tells if the camera answered the last inquiries.
+func (c *VISCABridge) IsUp() bool {
+	return c.up.Load()
+}
+
+func (c *VISCABridge) run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(c.cfg.RefreshRateMillis) * time.Millisecond)
+	defer t.Stop()
+
+	first := true
+	for {
+		err := c.poll(ctx)
+
+		// Only the changes are logged, not to flood the log while the camera is unreachable.
+		if err != nil && (c.up.Swap(false) || first) {
+			c.log.Warnf(ctx, "VISCA camera is not responding: %s", err)
+		}
+		if err == nil && !c.up.Swap(true) {
+			c.log.Infof(ctx, "VISCA camera is responding.")
+		}
+		first = false
+
+		select {
+		case <-t.C:
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// poll inquires the camera and emits the answers.
+func (c *VISCABridge) poll(ctx context.Context) error {
+	if c.cfg.Debug {
+		c.log.Debugf(ctx, "Polling VISCA camera...")
+	}
+
+	power, err := c.cfg.Connection.GetPower(ctx)
+	if err != nil {
+		return err
+	}
+	c.emit("/visca/power", boolToInt(power))
+
+	if !power {
+		return nil
+	}
+
+	pan, tilt, err := c.cfg.Connection.GetPanTiltPosition(ctx)
+	if err != nil {
+		return err
+	}
+	c.emit("/visca/pan", pan)
+	c.emit("/visca/tilt", tilt)
+
+	zoom, err := c.cfg.Connection.GetZoomPosition(ctx)
+	if err != nil {
+		return err
+	}
+	c.emit("/visca/zoom", zoom)
+
+	// Not every camera supports the preset inquiry, and there may be no preset recalled yet.
+	if preset, err := c.cfg.Connection.GetPreset(ctx); err == nil {
+		c.emit("/visca/preset", preset)
+	} else if c.cfg.Debug {
+		c.log.Debugf(ctx, "VISCA preset is unknown: %s", err)
+	}
+
+	return nil
+}
+
+func (c *VISCABridge) emit(address string, value int) {
+	args := []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("int32", fmt.Sprintf("%d", value))}
+
+	select {
+	case c.messages <- osc_message.NewMessage(address, args):
+	case <-c.quit:
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,328 @@
+// Package synclink keeps parameters (e.g. channel mutes, names) identical on two OSC connections, in both directions.
+package synclink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const (
+	sideA = 0
+	sideB = 1
+)
+
+type Config struct {
+	Debug bool
+	Name  string
+	// Sides are the two connections, A and B.
+	Sides    [2]Side
+	Mappings []Mapping
+
+	// EchoWindow is the time within which a message equal to a mirrored one is taken as its echo.
+	EchoWindow time.Duration
+	// ReconcileDelay is the wait at start, before the stored values of the two sides are reconciled.
+	ReconcileDelay time.Duration
+}
+
+// Side is one of the two connections of a link.
+type Side struct {
+	Name       string
+	Prefix     string
+	Connection usecaseifs.IOSCConnection
+}
+
+// Mapping pairs the address patterns of the two sides. The numeric arguments are transformed from A to B as
+// b = a * Scale + Offset, and back.
+type Mapping struct {
+	Addresses [2]string
+	Scale     float64
+	Offset    float64
+}
+
+// SyncLink mirrors the changes of the mapped addresses between the two sides.
+//
+// The writes of the link are remembered until their echo arrives (or the echo window is over), so the echoes are not
+// mirrored back. Every unconfirmed write is kept, as during a fast move the echoes of the earlier writes arrive after
+// the later ones were sent. Conflicting changes are resolved by the last writer winning: a change is accepted if it is newer than
+// the last accepted one, and it is re-sent to its own side too, if a write of the link to that side is still unconfirmed.
+type SyncLink struct {
+	log     usecaseifs.ILogger
+	cfg     Config
+	watcher usecaseifs.IMessageWatcher
+	reader  usecaseifs.IStoreReader
+
+	// params holds the state of the linked parameters by their address on side A, it is owned by the run loop.
+	params map[string]*param
+
+	stops []func()
+	quit  chan interface{}
+	done  chan interface{}
+}
+
+// param is the last agreed state of a linked parameter.
+type param struct {
+	// arguments are as on side A.
+	arguments []usecaseifs.IOSCMessageArgument
+	updatedAt time.Time
+	// pending are the unconfirmed writes of the link per side, in the order they were sent.
+	pending [2][]*write
+}
+
+// write is a message sent by the link, awaiting its echo.
+type write struct {
+	arguments []usecaseifs.IOSCMessageArgument
+	sentAt    time.Time
+}
+
+// change is a message that arrived on one of the sides.
+type change struct {
+	side      int
+	mapping   int
+	msg       usecaseifs.IOSCMessage
+	arrivedAt time.Time
+}
+
+func NewSyncLink(log usecaseifs.ILogger, cfg Config, watcher usecaseifs.IMessageWatcher, reader usecaseifs.IStoreReader) *SyncLink {
+	return &SyncLink{
+		log:     log,
+		cfg:     cfg,
+		watcher: watcher,
+		reader:  reader,
+		params:  map[string]*param{},
+		quit:    make(chan interface{}),
+		done:    make(chan interface{}),
+	}
+}
+
+// Start watches the mapped addresses on both sides, and reconciles the stored values after the reconcile delay.
+func (l *SyncLink) Start(ctx context.Context) error {
+	changes := make(chan change)
+
+	for mi, m := range l.cfg.Mappings {
+		for side := range l.cfg.Sides {
+			messages, stop, err := l.watcher.WatchMessages(l.cfg.Sides[side].Name, m.Addresses[side])
+			if err != nil {
+				l.stopWatching()
+				close(l.quit)
+				return fmt.Errorf("failed to start sync link %s: %w", l.cfg.Name, err)
+			}
+			l.stops = append(l.stops, stop)
+
+			go l.forward(side, mi, messages, changes)
+		}
+	}
+
+	go l.run(ctx, changes)
+	return nil
+}
+
+// Stop stops mirroring, it returns after the last change has been processed.
+func (l *SyncLink) Stop(ctx context.Context) {
+	l.stopWatching()
+	close(l.quit)
+	<-l.done
+}
+
+func (l *SyncLink) stopWatching() {
+	for _, stop := range l.stops {
+		stop()
+	}
+	l.stops = nil
+}
+
+// forward timestamps the messages of a watcher, and passes them to the run loop.
+func (l *SyncLink) forward(side int, mapping int, messages <-chan usecaseifs.IOSCMessage, changes chan<- change) {
+	for {
+		select {
+		case msg := <-messages:
+			select {
+			case changes <- change{side: side, mapping: mapping, msg: msg, arrivedAt: time.Now()}:
+			case <-l.quit:
+				return
+			}
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+func (l *SyncLink) run(ctx context.Context, changes <-chan change) {
+	defer close(l.done)
+
+	reconcile := time.NewTimer(l.cfg.ReconcileDelay)
+	defer reconcile.Stop()
+
+	for {
+		select {
+		case c := <-changes:
+			l.handleChange(ctx, c)
+		case <-reconcile.C:
+			l.reconcile(ctx)
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// handleChange mirrors a change to the other side, unless it is an echo, or it is already in sync.
+func (l *SyncLink) handleChange(ctx context.Context, c change) {
+	m := l.cfg.Mappings[c.mapping]
+
+	addressA, argumentsA, ok := toSideA(m, c.side, c.msg)
+	if !ok {
+		return
+	}
+
+	p := l.getParam(addressA)
+
+	l.expirePending(p, c.side, c.arrivedAt)
+
+	if l.confirmPending(p, c.side, c.msg.GetArguments()) {
+		if l.cfg.Debug {
+			l.log.Infof(ctx, "Sync link %s: suppressed echo %v.", l.cfg.Name, c.msg)
+		}
+		return
+	}
+
+	if p.arguments != nil && argumentsEqual(p.arguments, argumentsA) {
+		return
+	}
+
+	// The last writer wins.
+	if c.arrivedAt.Before(p.updatedAt) {
+		return
+	}
+
+	p.arguments = argumentsA
+	p.updatedAt = c.arrivedAt
+
+	other := 1 - c.side
+	l.write(ctx, m, p, other, addressA)
+
+	// A write of the link to this side may land after this change, overriding it, so this side gets the winner too.
+	if len(p.pending[c.side]) > 0 {
+		l.write(ctx, m, p, c.side, addressA)
+	}
+}
+
+// reconcile makes the two sides equal, based on the stored values: a value present on one side only is copied to the
+// other, and if they differ, the one that changed later wins.
+func (l *SyncLink) reconcile(ctx context.Context) {
+	l.log.Infof(ctx, "Sync link %s: reconciling...", l.cfg.Name)
+	store := l.reader.GetStoreSnapshot()
+
+	for _, m := range l.cfg.Mappings {
+		// The stored values of both sides, by their address on side A.
+		stored := map[string]*[2]*change{}
+		addresses := []string{}
+
+		for side := range l.cfg.Sides {
+			for _, record := range store.GetRecordsByPrefix(l.cfg.Sides[side].Prefix, false) {
+				address, ok := entities.UnprefixAddress(l.cfg.Sides[side].Prefix, record.GetMessage().GetAddress())
+				if !ok {
+					continue
+				}
+
+				msg := osc_message.NewMessage(address, record.GetMessage().GetArguments())
+				addressA, _, ok := toSideA(m, side, msg)
+				if !ok {
+					continue
+				}
+
+				if _, ok := stored[addressA]; !ok {
+					stored[addressA] = &[2]*change{}
+					addresses = append(addresses, addressA)
+				}
+				stored[addressA][side] = &change{side: side, msg: msg, arrivedAt: record.GetArrivedAt()}
+			}
+		}
+
+		for _, addressA := range addresses {
+			changes := stored[addressA]
+
+			winner := changes[sideA]
+			if winner == nil || (changes[sideB] != nil && changes[sideB].arrivedAt.After(winner.arrivedAt)) {
+				winner = changes[sideB]
+			}
+			loser := changes[1-winner.side]
+
+			p := l.getParam(addressA)
+
+			// The parameter changed since the start, which was mirrored already.
+			if !p.updatedAt.IsZero() {
+				continue
+			}
+
+			_, p.arguments, _ = toSideA(m, winner.side, winner.msg)
+			p.updatedAt = winner.arrivedAt
+
+			if loser != nil {
+				if _, loserArguments, _ := toSideA(m, loser.side, loser.msg); argumentsEqual(loserArguments, p.arguments) {
+					continue
+				}
+			}
+			l.write(ctx, m, p, 1-winner.side, addressA)
+		}
+	}
+}
+
+// getParam returns the state of the parameter, creating it if it is new.
+func (l *SyncLink) getParam(addressA string) *param {
+	p, ok := l.params[addressA]
+	if !ok {
+		p = &param{}
+		l.params[addressA] = p
+	}
+	return p
+}
+
+// write sends the agreed state of a parameter to the side, and remembers it to recognize its echo.
+func (l *SyncLink) write(ctx context.Context, m Mapping, p *param, side int, addressA string) {
+	address, arguments, ok := fromSideA(m, side, addressA, p.arguments)
+	if !ok {
+		return
+	}
+
+	msg := osc_message.NewMessage(address, arguments)
+	if l.cfg.Debug {
+		l.log.Infof(ctx, "Sync link %s: mirroring to %s: %v", l.cfg.Name, l.cfg.Sides[side].Name, msg)
+	}
+
+	if err := l.cfg.Sides[side].Connection.SendMessage(ctx, msg); err != nil {
+		l.log.Err(ctx, fmt.Errorf("sync link %s failed to mirror %v to %s: %w", l.cfg.Name, msg, l.cfg.Sides[side].Name, err))
+		return
+	}
+
+	now := time.Now()
+	l.expirePending(p, side, now)
+	p.pending[side] = append(p.pending[side], &write{arguments: arguments, sentAt: now})
+}
+
+// expirePending forgets the writes to the side, whose echo did not arrive within the echo window.
+func (l *SyncLink) expirePending(p *param, side int, now time.Time) {
+	pending := p.pending[side][:0]
+	for _, w := range p.pending[side] {
+		if now.Sub(w.sentAt) <= l.cfg.EchoWindow {
+			pending = append(pending, w)
+		}
+	}
+	p.pending[side] = pending
+}
+
+// confirmPending tells if [arguments] are the echo of a pending write to the side. The echoes arrive in order,
+// so the matching write and the ones before it are confirmed.
+func (l *SyncLink) confirmPending(p *param, side int, arguments []usecaseifs.IOSCMessageArgument) bool {
+	for i, w := range p.pending[side] {
+		if argumentsEqual(w.arguments, arguments) {
+			p.pending[side] = p.pending[side][i+1:]
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package synclink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/messagestore"
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/logger"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const faderAddress = "/ch/01/mix/fader"
+
+// watcher delivers the messages of the stand-in consoles to the link, like the listener of the bridge does.
+type watcher struct {
+	m        *sync.Mutex
+	watchers map[string][]watch
+}
+
+type watch struct {
+	pattern  string
+	messages chan usecaseifs.IOSCMessage
+}
+
+func newWatcher() *watcher {
+	return &watcher{m: &sync.Mutex{}, watchers: map[string][]watch{}}
+}
+
+func (w *watcher) WatchMessages(connection string, pattern string) (<-chan usecaseifs.IOSCMessage, func(), error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	messages := make(chan usecaseifs.IOSCMessage, 1000)
+	w.watchers[connection] = append(w.watchers[connection], watch{pattern: pattern, messages: messages})
+	return messages, func() {}, nil
+}
+
+func (w *watcher) deliver(connection string, msg usecaseifs.IOSCMessage) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	for _, wt := range w.watchers[connection] {
+		if oscpattern.Match(wt.pattern, msg.GetAddress()) {
+			wt.messages <- msg
+		}
+	}
+}
+
+type storeReader struct{}
+
+func (storeReader) GetStoreSnapshot() usecaseifs.IMessageStore {
+	return messagestore.NewMessageStore()
+}
+
+// console is a stand-in console, it applies the received values and echoes them after its latency, in order.
+type console struct {
+	name    string
+	watcher *watcher
+	latency time.Duration
+	echoes  chan usecaseifs.IOSCMessage
+
+	m      *sync.Mutex
+	writes []string
+}
+
+func newConsole(name string, w *watcher, latency time.Duration) *console {
+	c := &console{name: name, watcher: w, latency: latency, echoes: make(chan usecaseifs.IOSCMessage, 1000), m: &sync.Mutex{}}
+	go func() {
+		for msg := range c.echoes {
+			time.Sleep(c.latency)
+			c.watcher.deliver(c.name, msg)
+		}
+	}()
+	return c
+}
+
+func (c *console) Start(context.Context) error { return nil }
+func (c *console) Stop(context.Context)        {}
+func (c *console) Notify() <-chan error        { return nil }
+func (c *console) GetEventChan(context.Context) <-chan usecaseifs.IOSCMessage {
+	return nil
+}
+
+func (c *console) SendMessage(_ context.Context, msg usecaseifs.IOSCMessage) error {
+	c.m.Lock()
+	c.writes = append(c.writes, msg.GetArguments()[0].GetValue())
+	c.m.Unlock()
+
+	c.echoes <- msg
+	return nil
+}
+
+// move is a manual move of the fader on the console.
+func (c *console) move(value float64) {
+	c.watcher.deliver(c.name, faderMessage(value))
+}
+
+func (c *console) getWrites() []string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]string{}, c.writes...)
+}
+
+func faderMessage(value float64) usecaseifs.IOSCMessage {
+	return osc_message.NewMessage(faderAddress, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", value))})
+}
+
+func TestBurstIsNotMirroredBack(t *testing.T) {
+	w := newWatcher()
+	a := newConsole("a", w, 20*time.Millisecond)
+	b := newConsole("b", w, 20*time.Millisecond)
+
+	link := NewSyncLink(logger.New(), Config{
+		Name: "ab",
+		Sides: [2]Side{
+			{Name: "a", Prefix: "/a", Connection: a},
+			{Name: "b", Prefix: "/b", Connection: b},
+		},
+		Mappings:       []Mapping{{Addresses: [2]string{faderAddress, faderAddress}, Scale: 1}},
+		EchoWindow:     time.Second,
+		ReconcileDelay: time.Hour,
+	}, w, storeReader{})
+	if err := link.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start the link: %s", err)
+	}
+	defer link.Stop(context.Background())
+
+	// A fast fader move on A, the echoes of B arrive after the later values were already mirrored.
+	for i := 1; i <= 10; i++ {
+		a.move(float64(i) / 10)
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	if writes := a.getWrites(); len(writes) != 0 {
+		t.Errorf("the echoes of B were mirrored back to A: %v", writes)
+	}
+
+	writes := b.getWrites()
+	if len(writes) != 10 {
+		t.Fatalf("B received %d writes, expected 10: %v", len(writes), writes)
+	}
+	if last := writes[len(writes)-1]; last != "1.000000" {
+		t.Errorf("B ended up at %s, expected 1.000000", last)
+	}
+}
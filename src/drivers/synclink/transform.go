@@ -0,0 +1,88 @@
+package synclink
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// floatTolerance is the difference within which two floats are taken as equal, as the consoles round them.
+const floatTolerance = 0.0001
+
+// toSideA translates a message of [side] to the address and the arguments of side A.
+func toSideA(m Mapping, side int, msg usecaseifs.IOSCMessage) (string, []usecaseifs.IOSCMessageArgument, bool) {
+	address, ok := oscpattern.Translate(m.Addresses[side], m.Addresses[sideA], msg.GetAddress())
+	if !ok {
+		return "", nil, false
+	}
+
+	if side == sideA {
+		return address, msg.GetArguments(), true
+	}
+	return address, transform(msg.GetArguments(), func(b float64) float64 { return (b - m.Offset) / m.Scale }), true
+}
+
+// fromSideA translates the address and the arguments of side A to [side].
+func fromSideA(m Mapping, side int, addressA string, arguments []usecaseifs.IOSCMessageArgument) (string, []usecaseifs.IOSCMessageArgument, bool) {
+	address, ok := oscpattern.Translate(m.Addresses[sideA], m.Addresses[side], addressA)
+	if !ok {
+		return "", nil, false
+	}
+
+	if side == sideA {
+		return address, arguments, true
+	}
+	return address, transform(arguments, func(a float64) float64 { return a*m.Scale + m.Offset }), true
+}
+
+// transform applies [f] to the numeric arguments, the rest are kept as they are.
+func transform(arguments []usecaseifs.IOSCMessageArgument, f func(float64) float64) []usecaseifs.IOSCMessageArgument {
+	transformed := []usecaseifs.IOSCMessageArgument{}
+
+	for _, arg := range arguments {
+		value, err := strconv.ParseFloat(arg.GetValue(), 64)
+
+		switch {
+		case err != nil:
+			transformed = append(transformed, arg)
+		case arg.GetType() == "int32":
+			transformed = append(transformed, osc_message.NewMessageArgument(arg.GetType(), strconv.Itoa(int(math.Round(f(value))))))
+		case arg.GetType() == "float32":
+			transformed = append(transformed, osc_message.NewMessageArgument(arg.GetType(), fmt.Sprintf("%f", f(value))))
+		default:
+			transformed = append(transformed, arg)
+		}
+	}
+	return transformed
+}
+
+// argumentsEqual compares two lists of arguments, the floats with a tolerance.
+func argumentsEqual(a []usecaseifs.IOSCMessageArgument, b []usecaseifs.IOSCMessageArgument) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].GetType() != b[i].GetType() {
+			return false
+		}
+		if a[i].GetValue() == b[i].GetValue() {
+			continue
+		}
+		if a[i].GetType() != "float32" {
+			return false
+		}
+
+		valueA, errA := strconv.ParseFloat(a[i].GetValue(), 64)
+		valueB, errB := strconv.ParseFloat(b[i].GetValue(), 64)
+		if errA != nil || errB != nil || math.Abs(valueA-valueB) > floatTolerance {
+			return false
+		}
+	}
+	return true
+}
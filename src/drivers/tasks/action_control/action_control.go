@@ -0,0 +1,105 @@
+package action_control
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &ActionControl{}
+
+// ActionControl enables, disables, toggles or triggers another action at runtime.
+type ActionControl struct {
+	controller  usecaseifs.IActionController
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+	actionNames []string
+
+	action  string
+	command string
+}
+
+const (
+	ParamActionKey  = "action"
+	ParamCommandKey = "command"
+
+	CommandEnable  = "enable"
+	CommandDisable = "disable"
+	CommandToggle  = "toggle"
+	CommandTrigger = "trigger"
+)
+
+func NewFactory(controller usecaseifs.IActionController, log usecaseifs.ILogger, debug bool, actionNames []string) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &ActionControl{controller: controller, log: log, debug: debug, actionNames: actionNames}
+	}
+}
+
+func (o *ActionControl) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamActionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		},
+		{
+			Name:         ParamCommandKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s|%s)$", CommandEnable, CommandDisable, CommandToggle, CommandTrigger),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.action = sanitized[ParamActionKey].(string)
+	// nolint:forcetypeassert
+	o.command = sanitized[ParamCommandKey].(string)
+
+	if !slicetools.Contains(o.actionNames, o.action) {
+		o.configError = fmt.Errorf("invalid action: '%s', valid values: %s", o.action, strings.Join(o.actionNames, ","))
+	}
+}
+
+func (o *ActionControl) Validate() error {
+	return o.configError
+}
+
+func (o *ActionControl) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: %s action %s", o.command, o.action)
+
+	if o.command == CommandTrigger {
+		if err := o.controller.TriggerAction(ctx, o.action); err != nil {
+			return fmt.Errorf("failed to trigger action %s: %w", o.action, err)
+		}
+		return nil
+	}
+
+	enabled, err := o.controller.SetActionEnabled(ctx, o.action, func(enabled bool) bool {
+		switch o.command {
+		case CommandEnable:
+			return true
+		case CommandDisable:
+			return false
+		default:
+			return !enabled
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s action %s: %w", o.command, o.action, err)
+	}
+
+	if o.debug {
+		o.log.Infof(ctx, "\tAction %s is now enabled: %t", o.action, enabled)
+	}
+	return nil
+}
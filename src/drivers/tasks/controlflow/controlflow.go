@@ -0,0 +1,84 @@
+// Package controlflow contains the tasks that execute nested tasks: if, parallel, retry and try.
+package controlflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// The keys of the nested task lists, as they appear in the YAML.
+const (
+	BranchTasks   = "tasks"
+	BranchThen    = "then"
+	BranchElse    = "else"
+	BranchOnError = "on_error_tasks"
+)
+
+// controlFlowTask holds the nested condition and task lists, that are common to the control flow tasks.
+type controlFlowTask struct {
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	condition usecaseifs.IActionCondition
+	branches  map[string][]usecaseifs.IActionTask
+	onError   string
+}
+
+func (o *controlFlowTask) SetCondition(condition usecaseifs.IActionCondition) {
+	o.condition = condition
+}
+
+func (o *controlFlowTask) SetBranches(branches map[string][]usecaseifs.IActionTask, onError string) {
+	o.branches = branches
+	o.onError = onError
+}
+
+// validateStructure checks the presence of the condition and the branches.
+// [required] branches must be present and not empty, the ones not listed in [required] or [optional] must be absent.
+func (o *controlFlowTask) validateStructure(needsCondition bool, required []string, optional []string) error {
+	if o.configError != nil {
+		return o.configError
+	}
+
+	if needsCondition && o.condition == nil {
+		return fmt.Errorf("a condition is required")
+	}
+	if !needsCondition && o.condition != nil {
+		return fmt.Errorf("a condition is not allowed")
+	}
+
+	for _, name := range required {
+		if len(o.branches[name]) == 0 {
+			return fmt.Errorf("'%s' requires at least one task", name)
+		}
+	}
+
+	allowed := append(append([]string{}, required...), optional...)
+	for _, name := range sortedKeys(o.branches) {
+		if !slicetools.Contains(allowed, name) {
+			return fmt.Errorf("'%s' is not allowed, valid nested task lists: %s", name, strings.Join(allowed, ","))
+		}
+	}
+	return nil
+}
+
+// getTaskList returns the branch as a task list, following the [onError] policy.
+func (o *controlFlowTask) getTaskList(name string, onError string) *entities.TaskList {
+	return entities.NewTaskList(o.branches[name], onError)
+}
+
+func sortedKeys(m map[string][]usecaseifs.IActionTask) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+package controlflow
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IControlFlowTask = &If{}
+
+// If evaluates its condition against the store as it is at the time of the execution,
+// then executes either the 'then', or the 'else' tasks.
+type If struct {
+	controlFlowTask
+	storeReader usecaseifs.IStoreReader
+}
+
+func NewIfFactory(storeReader usecaseifs.IStoreReader, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &If{controlFlowTask: controlFlowTask{log: log, debug: debug}, storeReader: storeReader}
+	}
+}
+
+func (o *If) SetParameters(_ map[string]interface{}) {}
+
+func (o *If) Validate() error {
+	return o.validateStructure(true, []string{BranchThen}, []string{BranchElse})
+}
+
+func (o *If) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: if")
+
+	matched, err := o.condition.Evaluate(ctx, o.storeReader.GetStoreSnapshot())
+	if err != nil {
+		return fmt.Errorf("failed to evaluate condition: %w", err)
+	}
+
+	branch := BranchElse
+	if matched {
+		branch = BranchThen
+	}
+
+	if o.debug {
+		o.log.Infof(ctx, "\tThe condition resolved to %t, executing the '%s' tasks.", matched, branch)
+	}
+	return o.getTaskList(branch, o.onError).Execute(ctx, store)
+}
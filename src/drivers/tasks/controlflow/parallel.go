@@ -0,0 +1,71 @@
+package controlflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IControlFlowTask = &Parallel{}
+
+// Parallel executes its tasks concurrently, and waits for all of them to finish.
+// With the abort error policy, the first failure cancels the rest of the tasks.
+type Parallel struct {
+	controlFlowTask
+}
+
+func NewParallelFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Parallel{controlFlowTask: controlFlowTask{log: log, debug: debug}}
+	}
+}
+
+func (o *Parallel) SetParameters(_ map[string]interface{}) {}
+
+func (o *Parallel) Validate() error {
+	return o.validateStructure(false, []string{BranchTasks}, nil)
+}
+
+func (o *Parallel) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	tasks := o.branches[BranchTasks]
+	o.log.Infof(ctx, "\tExecuting task: parallel (%d tasks)", len(tasks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(tasks))
+	wg := &sync.WaitGroup{}
+
+	for i, task := range tasks {
+		i, task := i, task
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := task.Execute(ctx, store); err != nil {
+				errs[i] = fmt.Errorf("failed to execute parallel task %d: %w", i, err)
+				if o.onError == entities.OnErrorAbort {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	failed := []any{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to execute task(s) "+strings.Repeat(": %w", len(failed)), failed...)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package controlflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IControlFlowTask = &Retry{}
+
+// Retry executes its tasks until they succeed, at most 'attempts' times.
+// The tasks stop at the first failure, and the next attempt starts over after the backoff.
+type Retry struct {
+	controlFlowTask
+
+	attempts          int
+	backoffMillis     int
+	backoffMultiplier float64
+}
+
+const (
+	ParamAttemptsKey          = "attempts"
+	ParamBackoffMillisKey     = "backoff_millis"
+	ParamBackoffMultiplierKey = "backoff_multiplier"
+)
+
+func NewRetryFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Retry{controlFlowTask: controlFlowTask{log: log, debug: debug}}
+	}
+}
+
+func (o *Retry) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamAttemptsKey,
+			Optional:     true,
+			DefaultValue: 3,
+			Type:         []string{"int"},
+		},
+		{
+			Name:         ParamBackoffMillisKey,
+			Optional:     true,
+			DefaultValue: 1000,
+			Type:         []string{"int"},
+		},
+		{
+			Name:         ParamBackoffMultiplierKey,
+			Optional:     true,
+			DefaultValue: 1,
+			Type:         []string{"int", "float64"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.attempts = sanitized[ParamAttemptsKey].(int)
+	// nolint:forcetypeassert
+	o.backoffMillis = sanitized[ParamBackoffMillisKey].(int)
+
+	switch multiplier := sanitized[ParamBackoffMultiplierKey].(type) {
+	case int:
+		o.backoffMultiplier = float64(multiplier)
+	case float64:
+		o.backoffMultiplier = multiplier
+	}
+
+	switch {
+	case o.attempts < 1:
+		o.configError = fmt.Errorf("%s must be at least 1", ParamAttemptsKey)
+	case o.backoffMillis < 0:
+		o.configError = fmt.Errorf("%s must not be negative", ParamBackoffMillisKey)
+	case o.backoffMultiplier < 1:
+		o.configError = fmt.Errorf("%s must be at least 1", ParamBackoffMultiplierKey)
+	}
+}
+
+func (o *Retry) Validate() error {
+	return o.validateStructure(false, []string{BranchTasks}, nil)
+}
+
+func (o *Retry) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: retry (at most %d attempts)", o.attempts)
+
+	tasks := o.getTaskList(BranchTasks, entities.OnErrorAbort)
+	backoff := time.Duration(o.backoffMillis) * time.Millisecond
+
+	var err error
+	for attempt := 1; attempt <= o.attempts; attempt++ {
+		if err = tasks.Execute(ctx, store); err == nil {
+			return nil
+		}
+
+		if attempt == o.attempts {
+			break
+		}
+
+		o.log.Warnf(ctx, "\tAttempt %d/%d failed, retrying in %s: %s", attempt, o.attempts, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("retry cancelled after %d attempts: %w", attempt, err)
+		}
+		backoff = time.Duration(float64(backoff) * o.backoffMultiplier)
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", o.attempts, err)
+}
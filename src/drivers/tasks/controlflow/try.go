@@ -0,0 +1,46 @@
+package controlflow
+
+import (
+	"context"
+
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IControlFlowTask = &Try{}
+
+// Try executes its tasks, stopping at the first failure, then executes the 'on_error_tasks' if there was one.
+// The failure is handled, so it is not reported further, unless the 'on_error_tasks' fail as well.
+type Try struct {
+	controlFlowTask
+}
+
+func NewTryFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Try{controlFlowTask: controlFlowTask{log: log, debug: debug}}
+	}
+}
+
+func (o *Try) SetParameters(_ map[string]interface{}) {}
+
+func (o *Try) Validate() error {
+	return o.validateStructure(false, []string{BranchTasks}, []string{BranchOnError})
+}
+
+func (o *Try) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: try")
+
+	err := o.getTaskList(BranchTasks, entities.OnErrorAbort).Execute(ctx, store)
+	if err == nil {
+		return nil
+	}
+
+	// A cancelled execution must not go on with the error handling.
+	if ctx.Err() != nil {
+		return err
+	}
+
+	o.log.Warnf(ctx, "\tExecuting the on_error_tasks, because: %s", err)
+	return o.getTaskList(BranchOnError, o.onError).Execute(ctx, store)
+}
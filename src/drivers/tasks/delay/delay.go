@@ -46,7 +46,15 @@ func (o *Delay) Execute(ctx context.Context, store usecaseifs.IMessageStore) err
 	}
 
 	// All that code for a bit of sleep...
-	time.Sleep(time.Millisecond * time.Duration(delayMillis))
+	timer := time.NewTimer(time.Millisecond * time.Duration(delayMillis))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting %d milliseconds was cancelled: %w", delayMillis, ctx.Err())
+	}
+
 	if o.debug {
 		o.log.Debugf(ctx, "Waiting %d milliseconds is over.", delayMillis)
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/tasks/taskresult"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -17,6 +18,7 @@ var _ usecaseifs.IActionTask = &HTTPRequest{}
 
 // HTTPRequest makes a HTTP request as configured.
 type HTTPRequest struct {
+	updater     usecaseifs.IRecordUpdater
 	log         usecaseifs.ILogger
 	debug       bool
 	configError error
@@ -25,6 +27,7 @@ type HTTPRequest struct {
 	method      string
 	headers     []string
 	timeoutSecs int
+	result      *taskresult.Storer
 }
 
 const (
@@ -37,8 +40,8 @@ const (
 	ParamTimeoutSecsKey = "timeout_secs"
 )
 
-func NewFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
-	return func() usecaseifs.IActionTask { return &HTTPRequest{log: log, debug: debug} }
+func NewFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return &HTTPRequest{updater: updater, log: log, debug: debug} }
 }
 
 func (o *HTTPRequest) SetParameters(m map[string]interface{}) {
@@ -69,6 +72,7 @@ func (o *HTTPRequest) SetParameters(m map[string]interface{}) {
 			DefaultValue: []interface{}{},
 			Type:         []string{"[]interface {}"},
 		},
+		taskresult.StoreResultParameter,
 	})
 	if err != nil {
 		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
@@ -87,6 +91,11 @@ func (o *HTTPRequest) SetParameters(m map[string]interface{}) {
 	// nolint:forcetypeassert
 	o.method = sanitized[ParamMethodKey].(string)
 
+	if o.result, err = taskresult.NewStorer(o.updater, sanitized[taskresult.ParamStoreResultKey]); err != nil {
+		o.configError = err
+		return
+	}
+
 	// nolint:forcetypeassert
 	headerSlice := sanitized[ParamHeadersKey].([]interface{})
 
@@ -156,5 +165,14 @@ func (o *HTTPRequest) Execute(ctx context.Context, store usecaseifs.IMessageStor
 		o.log.Infof(ctx, "Response Status: %s", resp.Status)
 		o.log.Infof(ctx, "Response Body: %s", respBody.String())
 	}
+
+	if o.result != nil {
+		if err := o.result.StoreCode(ctx, "status", resp.StatusCode); err != nil {
+			return err
+		}
+		if err := o.result.StoreOutput(ctx, "body", respBody.String()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,90 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Filter{}
+
+const (
+	ParamFilterKey      = "filter"
+	ParamCommandEnable  = "enable"
+	ParamCommandDisable = "disable"
+)
+
+// Filter enables, disables or toggles a filter of a source (or scene).
+type Filter struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	source     string
+	filter     string
+	command    string
+}
+
+func (o *Filter) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamSourceKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamFilterKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamCommandKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s)$", ParamCommandEnable, ParamCommandDisable, ParamCommandToggle),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.source = sanitized[ParamSourceKey].(string)
+	// nolint:forcetypeassert
+	o.filter = sanitized[ParamFilterKey].(string)
+	// nolint:forcetypeassert
+	o.command = sanitized[ParamCommandKey].(string)
+}
+
+func (o *Filter) Validate() error {
+	return o.configError
+}
+
+func (o *Filter) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: obs %s filter %s of %s", o.command, o.filter, o.source)
+
+	enabled := o.command == ParamCommandEnable
+	if o.command == ParamCommandToggle {
+		current, err := o.connection.IsSourceFilterEnabled(ctx, o.source, o.filter)
+		if err != nil {
+			return err
+		}
+		enabled = !current
+	}
+
+	return o.connection.SetSourceFilterEnabled(ctx, o.source, o.filter, enabled)
+}
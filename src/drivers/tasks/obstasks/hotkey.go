@@ -0,0 +1,116 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Hotkey{}
+
+const (
+	ParamNameKey    = "name"
+	ParamKeyKey     = "key"
+	ParamShiftKey   = "shift"
+	ParamControlKey = "control"
+	ParamAltKey     = "alt"
+	ParamCmdKey     = "command"
+)
+
+// Hotkey triggers a hotkey in OBS, either by its name (e.g. OBSBasic.StartRecording),
+// or by a key sequence (e.g. OBS_KEY_F1 with modifiers).
+type Hotkey struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	name       string
+	key        string
+	modifiers  usecaseifs.OBSKeyModifiers
+}
+
+func (o *Hotkey) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamNameKey,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamKeyKey,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: "^(OBS_KEY_[A-Z0-9_]+|)$",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamShiftKey,
+			Optional:     true,
+			DefaultValue: false,
+			Type:         []string{"bool"},
+		}, {
+			Name:         ParamControlKey,
+			Optional:     true,
+			DefaultValue: false,
+			Type:         []string{"bool"},
+		}, {
+			Name:         ParamAltKey,
+			Optional:     true,
+			DefaultValue: false,
+			Type:         []string{"bool"},
+		}, {
+			Name:         ParamCmdKey,
+			Optional:     true,
+			DefaultValue: false,
+			Type:         []string{"bool"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.name = sanitized[ParamNameKey].(string)
+	// nolint:forcetypeassert
+	o.key = sanitized[ParamKeyKey].(string)
+	// nolint:forcetypeassert
+	o.modifiers = usecaseifs.OBSKeyModifiers{
+		Shift:   sanitized[ParamShiftKey].(bool),
+		Control: sanitized[ParamControlKey].(bool),
+		Alt:     sanitized[ParamAltKey].(bool),
+		Command: sanitized[ParamCmdKey].(bool),
+	}
+
+	if (o.name == "") == (o.key == "") {
+		o.configError = fmt.Errorf("exactly one of %s and %s must be set", ParamNameKey, ParamKeyKey)
+	}
+}
+
+func (o *Hotkey) Validate() error {
+	return o.configError
+}
+
+func (o *Hotkey) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	if o.name != "" {
+		o.log.Infof(ctx, "\tExecuting task: obs hotkey %s", o.name)
+		return o.connection.TriggerHotkeyByName(ctx, o.name)
+	}
+
+	o.log.Infof(ctx, "\tExecuting task: obs hotkey %s %+v", o.key, o.modifiers)
+	return o.connection.TriggerHotkeyByKeySequence(ctx, o.key, o.modifiers)
+}
@@ -0,0 +1,139 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &InputAudio{}
+
+const (
+	ParamInputKey     = "input"
+	ParamMuteKey      = "mute"
+	ParamMuteMute     = "mute"
+	ParamMuteUnmute   = "unmute"
+	ParamMuteToggle   = "toggle"
+	ParamVolumeDbKey  = "volume_db"
+	ParamVolumeMulKey = "volume_mul"
+)
+
+// InputAudio mutes, unmutes or toggles an input, and/or sets its volume.
+type InputAudio struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	input      string
+	mute       string
+	volumeDb   *float64
+	volumeMul  *float64
+}
+
+func (o *InputAudio) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamInputKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamMuteKey,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s|)$", ParamMuteMute, ParamMuteUnmute, ParamMuteToggle),
+			Type:         []string{"string"},
+		}, {
+			Name:     ParamVolumeDbKey,
+			Optional: true,
+			Type:     []string{"int", "float64"},
+		}, {
+			Name:     ParamVolumeMulKey,
+			Optional: true,
+			Type:     []string{"int", "float64"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.input = sanitized[ParamInputKey].(string)
+	// nolint:forcetypeassert
+	o.mute = sanitized[ParamMuteKey].(string)
+	o.volumeDb = toFloat(sanitized[ParamVolumeDbKey])
+	o.volumeMul = toFloat(sanitized[ParamVolumeMulKey])
+
+	if o.volumeDb != nil && o.volumeMul != nil {
+		o.configError = fmt.Errorf("only one of %s and %s can be set", ParamVolumeDbKey, ParamVolumeMulKey)
+		return
+	}
+	if o.mute == "" && o.volumeDb == nil && o.volumeMul == nil {
+		o.configError = fmt.Errorf("at least one of %s, %s or %s must be set", ParamMuteKey, ParamVolumeDbKey, ParamVolumeMulKey)
+		return
+	}
+	if o.volumeDb != nil && (*o.volumeDb < -100 || *o.volumeDb > 26) {
+		o.configError = fmt.Errorf("%s must be between -100 and 26", ParamVolumeDbKey)
+		return
+	}
+	if o.volumeMul != nil && (*o.volumeMul < 0 || *o.volumeMul > 20) {
+		o.configError = fmt.Errorf("%s must be between 0 and 20", ParamVolumeMulKey)
+	}
+}
+
+func (o *InputAudio) Validate() error {
+	return o.configError
+}
+
+func (o *InputAudio) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: obs input audio of %s", o.input)
+
+	if o.volumeDb != nil {
+		if err := o.connection.SetInputVolumeDb(ctx, o.input, *o.volumeDb); err != nil {
+			return err
+		}
+	}
+
+	if o.volumeMul != nil {
+		if err := o.connection.SetInputVolumeMul(ctx, o.input, *o.volumeMul); err != nil {
+			return err
+		}
+	}
+
+	switch o.mute {
+	case ParamMuteMute, ParamMuteUnmute:
+		return o.connection.SetInputMute(ctx, o.input, o.mute == ParamMuteMute)
+	case ParamMuteToggle:
+		_, err := o.connection.ToggleInputMute(ctx, o.input)
+		return err
+	}
+	return nil
+}
+
+// toFloat converts an optional int or float64 parameter.
+func toFloat(v interface{}) *float64 {
+	switch t := v.(type) {
+	case int:
+		f := float64(t)
+		return &f
+	case float64:
+		return &t
+	default:
+		return nil
+	}
+}
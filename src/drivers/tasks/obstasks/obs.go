@@ -1,6 +1,10 @@
 package obstasks
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+
 	"net.kopias.oscbridge/app/drivers/obsremote"
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -11,6 +15,85 @@ func NewSceneChangerFactory(obsConnections map[string]*obsremote.OBSRemote, log
 	return func() usecaseifs.IActionTask { return NewSceneChanger(obsConnections, log, debug) }
 }
 
-func NewVendorRequestFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
-	return func() usecaseifs.IActionTask { return NewVendorRequest(obsConnections, log, debug) }
+func NewVendorRequestFactory(obsConnections map[string]*obsremote.OBSRemote, updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return NewVendorRequest(obsConnections, updater, log, debug) }
+}
+
+func NewOutputFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return &Output{obsConnections: obsConnections, log: log, debug: debug} }
+}
+
+func NewInputAudioFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &InputAudio{obsConnections: obsConnections, log: log, debug: debug}
+	}
+}
+
+func NewTextFactory(obsConnections map[string]*obsremote.OBSRemote, storeReader usecaseifs.IStoreReader, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Text{obsConnections: obsConnections, storeReader: storeReader, log: log, debug: debug}
+	}
+}
+
+func NewSceneItemFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &SceneItem{obsConnections: obsConnections, log: log, debug: debug}
+	}
+}
+
+func NewStudioTransitionFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &StudioTransition{obsConnections: obsConnections, log: log, debug: debug}
+	}
+}
+
+func NewHotkeyFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return &Hotkey{obsConnections: obsConnections, log: log, debug: debug} }
+}
+
+func NewFilterFactory(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return &Filter{obsConnections: obsConnections, log: log, debug: debug} }
+}
+
+func NewScreenshotFactory(obsConnections map[string]*obsremote.OBSRemote, storeReader usecaseifs.IStoreReader, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Screenshot{obsConnections: obsConnections, storeReader: storeReader, log: log, debug: debug}
+	}
+}
+
+// getConnection returns the named connection, or an error if there is none.
+func getConnection(obsConnections map[string]*obsremote.OBSRemote, name string) (*obsremote.OBSRemote, error) {
+	conn, ok := obsConnections[name]
+	if !ok {
+		return nil, fmt.Errorf("there is no obs connection named '%s'", name)
+	}
+	return conn, nil
+}
+
+// storeValueRe matches the references to store values, e.g. {/var/speaker} or {/ch/01/config/name[1]}.
+var storeValueRe = regexp.MustCompile(`{(/[^}\[]*)(?:\[(\d+)])?}`)
+
+// expandStoreValues replaces the references to store values in [text] with the value of the referenced argument
+// (the first one by default). Missing records and arguments are replaced with an empty string.
+// The [store] should be the current one, not the snapshot of the trigger, so the results of the earlier tasks are seen.
+func expandStoreValues(text string, store usecaseifs.IMessageStore) string {
+	return storeValueRe.ReplaceAllStringFunc(text, func(s string) string {
+		match := storeValueRe.FindStringSubmatch(s)
+
+		index := 0
+		if match[2] != "" {
+			index, _ = strconv.Atoi(match[2])
+		}
+
+		record, ok := store.GetRecord(match[1], false)
+		if !ok {
+			return ""
+		}
+
+		args := record.GetMessage().GetArguments()
+		if index >= len(args) {
+			return ""
+		}
+		return args[index].GetValue()
+	})
 }
@@ -0,0 +1,101 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Output{}
+
+const (
+	ParamOutputKey          = "output"
+	ParamOutputRecord       = "record"
+	ParamOutputStream       = "stream"
+	ParamOutputReplayBuffer = "replay_buffer"
+
+	ParamCommandKey    = "command"
+	ParamCommandStart  = "start"
+	ParamCommandStop   = "stop"
+	ParamCommandToggle = "toggle"
+	ParamCommandSave   = "save"
+)
+
+// Output starts, stops or toggles the recording or the streaming, or saves the replay buffer.
+type Output struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	output     string
+	command    string
+}
+
+func (o *Output) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamOutputKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s)$", ParamOutputRecord, ParamOutputStream, ParamOutputReplayBuffer),
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamCommandKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s|%s)$", ParamCommandStart, ParamCommandStop, ParamCommandToggle, ParamCommandSave),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.output = sanitized[ParamOutputKey].(string)
+	// nolint:forcetypeassert
+	o.command = sanitized[ParamCommandKey].(string)
+
+	if (o.output == ParamOutputReplayBuffer) != (o.command == ParamCommandSave) {
+		o.configError = fmt.Errorf("the replay buffer can only be saved, and only the replay buffer can be saved")
+	}
+}
+
+func (o *Output) Validate() error {
+	return o.configError
+}
+
+func (o *Output) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: obs %s %s", o.command, o.output)
+
+	switch o.output + "/" + o.command {
+	case ParamOutputRecord + "/" + ParamCommandStart:
+		return o.connection.StartRecording(ctx)
+	case ParamOutputRecord + "/" + ParamCommandStop:
+		return o.connection.StopRecording(ctx)
+	case ParamOutputRecord + "/" + ParamCommandToggle:
+		return o.connection.ToggleRecording(ctx)
+	case ParamOutputStream + "/" + ParamCommandStart:
+		return o.connection.StartStreaming(ctx)
+	case ParamOutputStream + "/" + ParamCommandStop:
+		return o.connection.StopStreaming(ctx)
+	case ParamOutputStream + "/" + ParamCommandToggle:
+		return o.connection.ToggleStreaming(ctx)
+	default:
+		return o.connection.SaveReplayBuffer(ctx)
+	}
+}
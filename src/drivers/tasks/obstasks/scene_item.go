@@ -0,0 +1,90 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &SceneItem{}
+
+const (
+	ParamSourceKey   = "source"
+	ParamCommandShow = "show"
+	ParamCommandHide = "hide"
+)
+
+// SceneItem shows, hides or toggles the visibility of a source on a scene.
+type SceneItem struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	scene      string
+	source     string
+	command    string
+}
+
+func (o *SceneItem) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamSceneKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamSourceKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamCommandKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s)$", ParamCommandShow, ParamCommandHide, ParamCommandToggle),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.scene = sanitized[ParamSceneKey].(string)
+	// nolint:forcetypeassert
+	o.source = sanitized[ParamSourceKey].(string)
+	// nolint:forcetypeassert
+	o.command = sanitized[ParamCommandKey].(string)
+}
+
+func (o *SceneItem) Validate() error {
+	return o.configError
+}
+
+func (o *SceneItem) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: obs %s %s on %s", o.command, o.source, o.scene)
+
+	enabled := o.command == ParamCommandShow
+	if o.command == ParamCommandToggle {
+		current, err := o.connection.IsSceneItemEnabled(ctx, o.scene, o.source)
+		if err != nil {
+			return err
+		}
+		enabled = !current
+	}
+
+	return o.connection.SetSceneItemEnabled(ctx, o.scene, o.source, enabled)
+}
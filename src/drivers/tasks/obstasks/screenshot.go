@@ -0,0 +1,130 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Screenshot{}
+
+const (
+	ParamFilePathKey = "file_path"
+	ParamFormatKey   = "format"
+	ParamWidthKey    = "width"
+	ParamHeightKey   = "height"
+	ParamQualityKey  = "quality"
+)
+
+// Screenshot saves a screenshot of a source (or scene) to a file on the machine running OBS.
+// The file path may contain values from the store, e.g. {/var/filename}.
+type Screenshot struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	storeReader    usecaseifs.IStoreReader
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	source     string
+	filePath   string
+	format     string
+	width      int
+	height     int
+	quality    int
+}
+
+func (o *Screenshot) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamSourceKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamFilePathKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamFormatKey,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: "^[a-z0-9]*$",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamWidthKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamHeightKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamQualityKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.source = sanitized[ParamSourceKey].(string)
+	// nolint:forcetypeassert
+	o.filePath = sanitized[ParamFilePathKey].(string)
+	// nolint:forcetypeassert
+	o.format = sanitized[ParamFormatKey].(string)
+	// nolint:forcetypeassert
+	o.width = sanitized[ParamWidthKey].(int)
+	// nolint:forcetypeassert
+	o.height = sanitized[ParamHeightKey].(int)
+	// nolint:forcetypeassert
+	o.quality = sanitized[ParamQualityKey].(int)
+
+	if o.format == "" {
+		o.format = strings.ToLower(strings.TrimPrefix(filepath.Ext(o.filePath), "."))
+	}
+	if o.format == "" || strings.Contains(o.format, "}") {
+		o.format = "png"
+	}
+
+	switch {
+	case o.width != 0 && (o.width < 8 || o.width > 4096):
+		o.configError = fmt.Errorf("%s must be between 8 and 4096", ParamWidthKey)
+	case o.height != 0 && (o.height < 8 || o.height > 4096):
+		o.configError = fmt.Errorf("%s must be between 8 and 4096", ParamHeightKey)
+	case o.quality < 0 || o.quality > 100:
+		o.configError = fmt.Errorf("%s must be between 0 and 100", ParamQualityKey)
+	}
+}
+
+func (o *Screenshot) Validate() error {
+	return o.configError
+}
+
+func (o *Screenshot) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	// The earlier tasks of the action may have changed the store since the trigger, e.g. with store_result.
+	filePath := expandStoreValues(o.filePath, o.storeReader.GetStoreSnapshot())
+	o.log.Infof(ctx, "\tExecuting task: obs screenshot of %s to %s", o.source, filePath)
+
+	return o.connection.SaveSourceScreenshot(ctx, o.source, filePath, o.format, o.width, o.height, o.quality)
+}
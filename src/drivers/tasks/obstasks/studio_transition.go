@@ -0,0 +1,78 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &StudioTransition{}
+
+const (
+	ParamTransitionKey     = "transition"
+	ParamDurationMillisKey = "duration_millis"
+)
+
+// StudioTransition transitions the preview scene to program in studio mode, optionally with a given transition.
+type StudioTransition struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection     *obsremote.OBSRemote
+	transition     string
+	durationMillis int
+}
+
+func (o *StudioTransition) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamTransitionKey,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamDurationMillisKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.transition = sanitized[ParamTransitionKey].(string)
+	// nolint:forcetypeassert
+	o.durationMillis = sanitized[ParamDurationMillisKey].(int)
+
+	if o.durationMillis != 0 && (o.durationMillis < 50 || o.durationMillis > 20000) {
+		o.configError = fmt.Errorf("%s must be between 50 and 20000", ParamDurationMillisKey)
+	}
+}
+
+func (o *StudioTransition) Validate() error {
+	return o.configError
+}
+
+func (o *StudioTransition) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: obs studio mode transition %s", o.transition)
+
+	return o.connection.TriggerStudioModeTransition(ctx, o.transition, o.durationMillis)
+}
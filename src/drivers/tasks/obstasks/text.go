@@ -0,0 +1,74 @@
+package obstasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/obsremote"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Text{}
+
+const (
+	ParamTextKey = "text"
+)
+
+// Text sets the content of a text source, the text may contain values from the store, e.g. {/var/speaker}.
+type Text struct {
+	obsConnections map[string]*obsremote.OBSRemote
+	storeReader    usecaseifs.IStoreReader
+	log            usecaseifs.ILogger
+	debug          bool
+	configError    error
+
+	connection *obsremote.OBSRemote
+	input      string
+	text       string
+}
+
+func (o *Text) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamInputKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamTextKey,
+			Optional: false,
+			Type:     []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.obsConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.input = sanitized[ParamInputKey].(string)
+	// nolint:forcetypeassert
+	o.text = sanitized[ParamTextKey].(string)
+}
+
+func (o *Text) Validate() error {
+	return o.configError
+}
+
+func (o *Text) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	// The earlier tasks of the action may have changed the store since the trigger, e.g. with store_result.
+	text := expandStoreValues(o.text, o.storeReader.GetStoreSnapshot())
+	o.log.Infof(ctx, "\tExecuting task: obs text of %s: %s", o.input, text)
+
+	return o.connection.SetInputText(ctx, o.input, text)
+}
@@ -2,11 +2,13 @@ package obstasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"net.kopias.oscbridge/app/drivers/obsremote"
 
 	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/tasks/taskresult"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -22,6 +24,7 @@ const (
 // VendorRequest allows sending arbitrary "vendor" messages.
 type VendorRequest struct {
 	obsConnections map[string]*obsremote.OBSRemote
+	updater        usecaseifs.IRecordUpdater
 
 	debug          bool
 	log            usecaseifs.ILogger
@@ -30,10 +33,11 @@ type VendorRequest struct {
 	requestType    string
 	requestData    map[string]interface{}
 	connectionName string
+	result         *taskresult.Storer
 }
 
-func NewVendorRequest(obsConnections map[string]*obsremote.OBSRemote, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
-	return &VendorRequest{obsConnections: obsConnections, log: log, debug: debug}
+func NewVendorRequest(obsConnections map[string]*obsremote.OBSRemote, updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
+	return &VendorRequest{obsConnections: obsConnections, updater: updater, log: log, debug: debug}
 }
 
 func (o *VendorRequest) Validate() error {
@@ -43,9 +47,20 @@ func (o *VendorRequest) Validate() error {
 func (o *VendorRequest) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
 	o.log.Infof(ctx, "\tExecuting task: OBS vendor request")
 
-	if _, err := o.obsConnections[o.connectionName].VendorRequest(ctx, o.vendorName, o.requestType, o.requestData); err != nil {
+	responseData, err := o.obsConnections[o.connectionName].VendorRequest(ctx, o.vendorName, o.requestType, o.requestData)
+	if err != nil {
 		return fmt.Errorf("failed to execute vendor request: %w", err)
 	}
+
+	if o.result != nil {
+		response, err := json.Marshal(responseData)
+		if err != nil {
+			return fmt.Errorf("failed to encode the vendor response: %w", err)
+		}
+		if err := o.result.StoreOutput(ctx, "response", string(response)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -68,6 +83,7 @@ func (o *VendorRequest) SetParameters(m map[string]interface{}) {
 			Optional: false,
 			Type:     []string{"string"},
 		},
+		taskresult.StoreResultParameter,
 	})
 	if err != nil {
 		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
@@ -85,4 +101,8 @@ func (o *VendorRequest) SetParameters(m map[string]interface{}) {
 
 	// nolint:forcetypeassert
 	o.connectionName = sanitized[ParamConnectionKey].(string)
+
+	if o.result, err = taskresult.NewStorer(o.updater, sanitized[taskresult.ParamStoreResultKey]); err != nil {
+		o.configError = err
+	}
 }
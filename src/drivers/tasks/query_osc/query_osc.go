@@ -0,0 +1,180 @@
+package query_osc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/tasks/send_osc_message"
+	"net.kopias.oscbridge/app/drivers/tasks/taskresult"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &QueryOscTask{}
+
+// QueryOscTask sends an OSC Message to the named connection, and waits for the reply to arrive on the same connection.
+// By the time the task finishes, the reply is in the store, so the next tasks reading the current store
+// (e.g. if, ramp_osc, the store values of obs_text) can rely on it.
+type QueryOscTask struct {
+	watcher       usecaseifs.IMessageWatcher
+	updater       usecaseifs.IRecordUpdater
+	log           usecaseifs.ILogger
+	debug         bool
+	configError   error
+	connections   map[string]usecaseifs.IOSCConnection
+	connection    string
+	address       string
+	arguments     []usecaseifs.IOSCMessageArgument
+	replyAddress  string
+	timeoutMillis int
+	result        *taskresult.Storer
+}
+
+const (
+	ParamConnectionKey    = "connection"
+	ParamAddress          = "address"
+	ParamArguments        = "arguments"
+	ParamReplyAddress     = "reply_address"
+	ParamTimeoutMillisKey = "timeout_millis"
+
+	// ResultName is the name of the stored reply, under the address of store_result.
+	ResultName = "reply"
+)
+
+func NewFactory(
+	watcher usecaseifs.IMessageWatcher,
+	updater usecaseifs.IRecordUpdater,
+	log usecaseifs.ILogger,
+	debug bool,
+	connections map[string]usecaseifs.IOSCConnection,
+) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &QueryOscTask{watcher: watcher, updater: updater, log: log, debug: debug, connections: connections}
+	}
+}
+
+func (o *QueryOscTask) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamAddress,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamArguments,
+			Optional:     true,
+			DefaultValue: []interface{}{},
+			Type:         []string{"[]interface {}"},
+		}, {
+			Name:         ParamReplyAddress,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamTimeoutMillisKey,
+			Optional:     true,
+			DefaultValue: 1000,
+			Type:         []string{"int"},
+		},
+		taskresult.StoreResultParameter,
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.connection = sanitized[ParamConnectionKey].(string)
+
+	// nolint:forcetypeassert
+	o.address = sanitized[ParamAddress].(string)
+
+	// nolint:forcetypeassert
+	o.timeoutMillis = sanitized[ParamTimeoutMillisKey].(int)
+	if o.timeoutMillis <= 0 {
+		o.configError = fmt.Errorf("%s must be positive", ParamTimeoutMillisKey)
+		return
+	}
+
+	// The reply usually arrives on the queried address.
+	// nolint:forcetypeassert
+	o.replyAddress = sanitized[ParamReplyAddress].(string)
+	if o.replyAddress == "" {
+		o.replyAddress = o.address
+	}
+	if err := oscpattern.Validate(o.replyAddress); err != nil {
+		o.configError = fmt.Errorf("invalid %s: %w", ParamReplyAddress, err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.arguments, err = send_osc_message.ParseArguments(sanitized[ParamArguments].([]interface{})); err != nil {
+		o.configError = fmt.Errorf("query_osc task failed to verify parameters: %w", err)
+		return
+	}
+
+	if o.result, err = taskresult.NewStorer(o.updater, sanitized[taskresult.ParamStoreResultKey]); err != nil {
+		o.configError = err
+		return
+	}
+}
+
+func (o *QueryOscTask) Validate() error {
+	return o.configError
+}
+
+func (o *QueryOscTask) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: OSC query")
+
+	conn, ok := o.connections[o.connection]
+	if !ok {
+		return fmt.Errorf("there is no osc connection named '%s'", o.connection)
+	}
+
+	msg := osc_message.NewMessage(o.address, o.arguments)
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not sending query: %s: %w", msg.String(), err)
+	}
+
+	// The watching starts before sending, so a quick reply is not missed.
+	replies, stop, err := o.watcher.WatchMessages(o.connection, o.replyAddress)
+	if err != nil {
+		return fmt.Errorf("failed to watch for the reply: %w", err)
+	}
+	defer stop()
+
+	if err := conn.SendMessage(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send query: %s: %w", msg.String(), err)
+	}
+
+	timer := time.NewTimer(time.Millisecond * time.Duration(o.timeoutMillis))
+	defer timer.Stop()
+
+	var reply usecaseifs.IOSCMessage
+	select {
+	case reply = <-replies:
+	case <-timer.C:
+		return fmt.Errorf("no reply on %s to %s in %d milliseconds", o.replyAddress, msg.String(), o.timeoutMillis)
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for the reply to %s was cancelled: %w", msg.String(), ctx.Err())
+	}
+
+	if o.debug {
+		o.log.Infof(ctx, "\tReceived reply: %s", reply.String())
+	}
+
+	if o.result != nil {
+		if err := o.result.StoreArguments(ctx, ResultName, reply.GetArguments()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package ramp_osc
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	CurveLinear    = "linear"
+	CurveDBLinear  = "db_linear"
+	CurveEaseIn    = "ease_in"
+	CurveEaseOut   = "ease_out"
+	CurveEaseInOut = "ease_in_out"
+
+	// dbFloor is the level a gain of 0 (-inf dB) is interpolated from or to, by the db_linear curve.
+	dbFloor = -90
+)
+
+// Curves lists the valid curves.
+var Curves = []string{CurveLinear, CurveDBLinear, CurveEaseIn, CurveEaseOut, CurveEaseInOut}
+
+// interpolate returns the value between [from] and [to] at [t] (0..1) of the ramp, following the [curve].
+func interpolate(curve string, from float64, to float64, t float64) (float64, error) {
+	switch curve {
+	case CurveLinear:
+		return lerp(from, to, t), nil
+	case CurveEaseIn:
+		return lerp(from, to, t*t), nil
+	case CurveEaseOut:
+		return lerp(from, to, 1-(1-t)*(1-t)), nil
+	case CurveEaseInOut:
+		// Smoothstep: slow at both ends, fastest in the middle.
+		return lerp(from, to, t*t*(3-2*t)), nil
+	case CurveDBLinear:
+		// The values are gains (e.g. 0..1), interpolated evenly in dB, as the ear perceives the loudness.
+		if t >= 1 {
+			return to, nil
+		}
+		db := lerp(gainToDB(from), gainToDB(to), t)
+		if db <= dbFloor {
+			return 0, nil
+		}
+		return math.Pow(10, db/20), nil
+	default:
+		return 0, fmt.Errorf("unknown curve: '%s'", curve)
+	}
+}
+
+func lerp(from float64, to float64, t float64) float64 {
+	return from + (to-from)*t
+}
+
+// gainToDB converts a gain to dB, the gains at or below 0 are taken as the floor.
+func gainToDB(gain float64) float64 {
+	if gain <= 0 {
+		return dbFloor
+	}
+	return math.Max(20*math.Log10(gain), dbFloor)
+}
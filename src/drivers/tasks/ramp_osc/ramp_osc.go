@@ -0,0 +1,359 @@
+package ramp_osc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/tasks/send_osc_message"
+	"net.kopias.oscbridge/app/pkg/x32"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &RampOscTask{}
+
+// RampOscTask sends a series of OSC messages to the named connection, moving a single numeric argument from the
+// current (or a configured) value to the target over time, e.g. to fade a channel down.
+type RampOscTask struct {
+	storeReader usecaseifs.IStoreReader
+	watcher     usecaseifs.IMessageWatcher
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+	connections map[string]usecaseifs.IOSCConnection
+
+	connection         string
+	address            string
+	valueType          string
+	unit               string
+	from               *float64
+	fromAddress        string
+	to                 float64
+	durationMillis     int
+	stepsPerSecond     int
+	curve              string
+	cancelOnManualMove bool
+}
+
+const (
+	ParamConnectionKey         = "connection"
+	ParamAddress               = "address"
+	ParamType                  = "type"
+	ParamUnit                  = "unit"
+	ParamFrom                  = "from"
+	ParamFromAddress           = "from_address"
+	ParamTo                    = "to"
+	ParamDurationMillis        = "duration_millis"
+	ParamStepsPerSecond        = "steps_per_second"
+	ParamCurve                 = "curve"
+	ParamCancelOnManualMoveKey = "cancel_on_manual_move"
+
+	// manualMoveTolerance is the difference between a sent and an incoming float, that is still taken as an echo.
+	manualMoveTolerance = 0.0001
+)
+
+func NewFactory(
+	storeReader usecaseifs.IStoreReader,
+	watcher usecaseifs.IMessageWatcher,
+	log usecaseifs.ILogger,
+	debug bool,
+	connections map[string]usecaseifs.IOSCConnection,
+) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &RampOscTask{storeReader: storeReader, watcher: watcher, log: log, debug: debug, connections: connections}
+	}
+}
+
+// nolint:cyclop
+func (o *RampOscTask) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamAddress,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamType,
+			Optional:     true,
+			DefaultValue: "float32",
+			ValuePattern: "^(float32|int32)$",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamUnit,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: fmt.Sprintf("^(%s|)$", send_osc_message.UnitX32DB),
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamFrom,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamFromAddress,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:     ParamTo,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamDurationMillis,
+			Optional: false,
+			Type:     []string{"int"},
+		}, {
+			Name:         ParamStepsPerSecond,
+			Optional:     true,
+			DefaultValue: 25,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamCurve,
+			Optional:     true,
+			DefaultValue: CurveLinear,
+			ValuePattern: fmt.Sprintf("^(%s)$", strings.Join(Curves, "|")),
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamCancelOnManualMoveKey,
+			Optional:     true,
+			DefaultValue: true,
+			Type:         []string{"bool"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.connection = sanitized[ParamConnectionKey].(string)
+	// nolint:forcetypeassert
+	o.address = sanitized[ParamAddress].(string)
+	// nolint:forcetypeassert
+	o.valueType = sanitized[ParamType].(string)
+	// nolint:forcetypeassert
+	o.unit = sanitized[ParamUnit].(string)
+	// nolint:forcetypeassert
+	o.fromAddress = sanitized[ParamFromAddress].(string)
+	// nolint:forcetypeassert
+	o.durationMillis = sanitized[ParamDurationMillis].(int)
+	// nolint:forcetypeassert
+	o.stepsPerSecond = sanitized[ParamStepsPerSecond].(int)
+	// nolint:forcetypeassert
+	o.curve = sanitized[ParamCurve].(string)
+	// nolint:forcetypeassert
+	o.cancelOnManualMove = sanitized[ParamCancelOnManualMoveKey].(bool)
+
+	if o.unit == send_osc_message.UnitX32DB && o.valueType != "float32" {
+		o.configError = fmt.Errorf("%s %s requires a float32 argument", ParamUnit, send_osc_message.UnitX32DB)
+		return
+	}
+
+	if o.durationMillis <= 0 {
+		o.configError = fmt.Errorf("%s must be positive", ParamDurationMillis)
+		return
+	}
+
+	// With x32_db the values are dB levels, so they are interpolated in dB already.
+	if o.unit == send_osc_message.UnitX32DB && o.curve == CurveDBLinear {
+		o.curve = CurveLinear
+	}
+
+	if o.stepsPerSecond <= 0 {
+		o.configError = fmt.Errorf("%s must be positive", ParamStepsPerSecond)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.to, err = o.parseValue(sanitized[ParamTo].(string)); err != nil {
+		o.configError = fmt.Errorf("invalid %s: %w", ParamTo, err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if from := sanitized[ParamFrom].(string); from != "" {
+		value, err := o.parseValue(from)
+		if err != nil {
+			o.configError = fmt.Errorf("invalid %s: %w", ParamFrom, err)
+			return
+		}
+		o.from = &value
+	}
+
+	if o.from == nil && o.fromAddress == "" {
+		o.configError = fmt.Errorf("either %s or %s must be set", ParamFrom, ParamFromAddress)
+		return
+	}
+}
+
+// parseValue parses a configured value, a dB level if the unit is x32_db.
+func (o *RampOscTask) parseValue(s string) (float64, error) {
+	if o.unit == send_osc_message.UnitX32DB {
+		db, err := x32.ParseDB(s)
+		if err != nil {
+			return 0, err
+		}
+		return math.Max(db, x32.MinDB), nil
+	}
+
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+func (o *RampOscTask) Validate() error {
+	return o.configError
+}
+
+// nolint:cyclop
+func (o *RampOscTask) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: OSC ramp")
+
+	conn, ok := o.connections[o.connection]
+	if !ok {
+		return fmt.Errorf("there is no osc connection named '%s'", o.connection)
+	}
+
+	from, err := o.getFrom()
+	if err != nil {
+		return err
+	}
+
+	steps := o.durationMillis * o.stepsPerSecond / 1000
+	if steps < 1 {
+		steps = 1
+	}
+
+	// The messages arriving on the address while ramping are either the echoes of the sent values, or manual moves.
+	var incoming <-chan usecaseifs.IOSCMessage
+	if o.cancelOnManualMove {
+		messages, stop, err := o.watcher.WatchMessages(o.connection, o.address)
+		if err != nil {
+			return fmt.Errorf("failed to watch for manual moves: %w", err)
+		}
+		defer stop()
+		incoming = messages
+	}
+
+	if o.debug {
+		o.log.Infof(ctx, "\tRamping %s from %f to %f in %d steps, following %s.", o.address, from, o.to, steps, o.curve)
+	}
+
+	ticker := time.NewTicker(time.Duration(o.durationMillis) * time.Millisecond / time.Duration(steps))
+	defer ticker.Stop()
+
+	// Every step is sent after waiting for its tick, so the target is reached at the end of the duration.
+	sent := []string{}
+	for i := 1; i <= steps; i++ {
+		if moved := o.waitForStep(ctx, ticker, incoming, sent); moved != nil {
+			o.log.Infof(ctx, "\tRamping %s stopped, it was moved manually to %s.", o.address, moved.String())
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("ramping %s was cancelled: %w", o.address, err)
+		}
+
+		value, err := interpolate(o.curve, from, o.to, float64(i)/float64(steps))
+		if err != nil {
+			return err
+		}
+
+		formatted := o.formatValue(value)
+		if len(sent) > 0 && sent[len(sent)-1] == formatted {
+			continue
+		}
+
+		msg := osc_message.NewMessage(o.address, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument(o.valueType, formatted)})
+		if err := conn.SendMessage(ctx, msg); err != nil {
+			return fmt.Errorf("failed to send message: %s: %w", msg.String(), err)
+		}
+		sent = append(sent, formatted)
+	}
+
+	return nil
+}
+
+// waitForStep waits for the next step, and returns the incoming message meanwhile, that is not an echo of the sent values.
+// It returns early if the execution is cancelled.
+func (o *RampOscTask) waitForStep(ctx context.Context, ticker *time.Ticker, incoming <-chan usecaseifs.IOSCMessage, sent []string) usecaseifs.IOSCMessage {
+	for {
+		select {
+		case <-ticker.C:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case msg := <-incoming:
+			if !o.isEcho(msg, sent) {
+				return msg
+			}
+		}
+	}
+}
+
+// isEcho tells if the first argument of the message is one of the sent values.
+func (o *RampOscTask) isEcho(msg usecaseifs.IOSCMessage, sent []string) bool {
+	args := msg.GetArguments()
+	if len(args) == 0 {
+		return false
+	}
+
+	value, err := strconv.ParseFloat(args[0].GetValue(), 64)
+	if err != nil {
+		return false
+	}
+
+	for _, s := range sent {
+		// nolint:errcheck
+		sentValue, _ := strconv.ParseFloat(s, 64)
+		if math.Abs(value-sentValue) <= manualMoveTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// getFrom returns the start of the ramp: the configured value, or the current value in the store.
+func (o *RampOscTask) getFrom() (float64, error) {
+	if o.from != nil {
+		return *o.from, nil
+	}
+
+	record, ok := o.storeReader.GetStoreSnapshot().GetRecord(o.fromAddress, false)
+	if !ok {
+		return 0, fmt.Errorf("there is no current value at %s to ramp from", o.fromAddress)
+	}
+
+	args := record.GetMessage().GetArguments()
+	if len(args) == 0 {
+		return 0, fmt.Errorf("the current value at %s has no arguments", o.fromAddress)
+	}
+
+	value, err := strconv.ParseFloat(args[0].GetValue(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("the current value at %s is not a number: %w", o.fromAddress, err)
+	}
+
+	if o.unit == send_osc_message.UnitX32DB {
+		return math.Max(x32.FaderToDB(value), x32.MinDB), nil
+	}
+	return value, nil
+}
+
+// formatValue formats an interpolated value as the argument to send.
+func (o *RampOscTask) formatValue(value float64) string {
+	if o.unit == send_osc_message.UnitX32DB {
+		value = x32.DBToFader(value)
+	}
+
+	if o.valueType == "int32" {
+		return strconv.Itoa(int(math.Round(value)))
+	}
+	return fmt.Sprintf("%f", value)
+}
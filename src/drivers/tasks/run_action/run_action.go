@@ -0,0 +1,94 @@
+package run_action
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionReferencingTask = &RunAction{}
+
+// RunAction executes the tasks of another action or macro, as part of the current execution.
+// The referenced action is resolved by the action composer.
+type RunAction struct {
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	actionName      string
+	checkConditions bool
+	action          usecaseifs.IAction
+}
+
+const (
+	ParamActionKey          = "action"
+	ParamCheckConditionsKey = "check_conditions"
+)
+
+func NewFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &RunAction{log: log, debug: debug}
+	}
+}
+
+func (o *RunAction) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamActionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		},
+		{
+			Name:         ParamCheckConditionsKey,
+			Optional:     true,
+			DefaultValue: false,
+			Type:         []string{"bool"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.actionName = sanitized[ParamActionKey].(string)
+	// nolint:forcetypeassert
+	o.checkConditions = sanitized[ParamCheckConditionsKey].(bool)
+}
+
+func (o *RunAction) Validate() error {
+	return o.configError
+}
+
+func (o *RunAction) GetReferencedAction() (string, bool) {
+	return o.actionName, o.checkConditions
+}
+
+func (o *RunAction) SetReferencedAction(action usecaseifs.IAction) {
+	o.action = action
+}
+
+func (o *RunAction) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: run action %s", o.actionName)
+
+	if o.checkConditions {
+		matched, err := o.action.Evaluate(ctx, store)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate %s: %w", o.actionName, err)
+		}
+		if !matched {
+			if o.debug {
+				o.log.Infof(ctx, "\tSkipping %s, its trigger chain did not match.", o.actionName)
+			}
+			return nil
+		}
+	}
+
+	if err := o.action.Execute(ctx, store); err != nil {
+		return fmt.Errorf("failed to run %s: %w", o.actionName, err)
+	}
+	return nil
+}
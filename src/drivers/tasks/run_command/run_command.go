@@ -1,12 +1,14 @@
 package run_command
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os/exec"
 	"strings"
 
 	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/tasks/taskresult"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -15,6 +17,7 @@ var _ usecaseifs.IActionTask = &RunCommandTask{}
 
 // RunCommandTask executes the given command as-is.
 type RunCommandTask struct {
+	updater         usecaseifs.IRecordUpdater
 	log             usecaseifs.ILogger
 	debug           bool
 	configError     error
@@ -22,6 +25,7 @@ type RunCommandTask struct {
 	arguments       []string
 	runInBackground bool
 	directory       string
+	result          *taskresult.Storer
 }
 
 const (
@@ -31,9 +35,9 @@ const (
 	ParamDirectory  = "directory"
 )
 
-func NewFactory(log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+func NewFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
 	return func() usecaseifs.IActionTask {
-		return &RunCommandTask{log: log, debug: debug, arguments: []string{}}
+		return &RunCommandTask{updater: updater, log: log, debug: debug, arguments: []string{}}
 	}
 }
 
@@ -59,6 +63,7 @@ func (o *RunCommandTask) SetParameters(m map[string]interface{}) {
 			DefaultValue: "",
 			Type:         []string{"string"},
 		},
+		taskresult.StoreResultParameter,
 	})
 	if err != nil {
 		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
@@ -72,6 +77,11 @@ func (o *RunCommandTask) SetParameters(m map[string]interface{}) {
 	// nolint:forcetypeassert
 	o.directory = sanitized[ParamDirectory].(string)
 
+	if o.result, err = taskresult.NewStorer(o.updater, sanitized[taskresult.ParamStoreResultKey]); err != nil {
+		o.configError = err
+		return
+	}
+
 	args, ok := sanitized[ParamArguments]
 	if !ok {
 		o.configError = fmt.Errorf("key %s was not found", ParamArguments)
@@ -98,22 +108,49 @@ func (o *RunCommandTask) Execute(ctx context.Context, store usecaseifs.IMessageS
 	o.log.Infof(ctx, "\tExecuting task: Run command")
 
 	if o.runInBackground {
-		go o.execute(ctx)
-	} else {
-		o.execute(ctx)
+		// Background commands outlive the action on purpose, therefore they are not bound to its context.
+		go func() {
+			// nolint: gosec
+			if err := o.execute(ctx, exec.Command(o.command, o.arguments...)); err != nil {
+				o.log.Err(ctx, err)
+			}
+		}()
+		return nil
 	}
-	return nil
-}
 
-func (o *RunCommandTask) execute(ctx context.Context) {
+	// The command is killed if the execution gets cancelled or times out.
 	// nolint: gosec
-	cmd := exec.Command(o.command, o.arguments...)
+	return o.execute(ctx, exec.CommandContext(ctx, o.command, o.arguments...))
+}
+
+// execute runs the command, and stores its results if configured. A failed command (e.g. a non-zero exit code)
+// is returned after its results are stored, so the error handling (e.g. retry, try) can react to it.
+func (o *RunCommandTask) execute(ctx context.Context, cmd *exec.Cmd) error {
 	if o.directory != "" {
 		cmd.Dir = o.directory
 	}
-	err := cmd.Run()
-	if err != nil {
-		o.log.Err(ctx, fmt.Errorf("failed to execute %s %s: %w", o.command, strings.Join(o.arguments, " "), err))
+
+	stdout := &bytes.Buffer{}
+	if o.result != nil {
+		cmd.Stdout = stdout
+	}
+
+	runErr := cmd.Run()
+	if runErr != nil {
+		runErr = fmt.Errorf("failed to execute %s %s: %w", o.command, strings.Join(o.arguments, " "), runErr)
+	}
+	if cmd.ProcessState == nil {
+		return runErr
 	}
 	o.log.Infof(ctx, "Command exit code: %d", cmd.ProcessState.ExitCode())
+
+	if o.result != nil {
+		if err := o.result.StoreCode(ctx, "exit_code", cmd.ProcessState.ExitCode()); err != nil {
+			return err
+		}
+		if err := o.result.StoreOutput(ctx, "stdout", strings.TrimRight(stdout.String(), "\r\n")); err != nil {
+			return err
+		}
+	}
+	return runErr
 }
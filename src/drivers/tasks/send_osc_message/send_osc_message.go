@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/pkg/x32"
 
 	"net.kopias.oscbridge/app/drivers/paramsanitizer"
 
@@ -21,12 +22,7 @@ type SendOscMessageTask struct {
 	connections map[string]usecaseifs.IOSCConnection
 	connection  string
 	address     string
-	arguments   []argument
-}
-
-type argument struct {
-	variableType  string
-	variableValue string
+	arguments   []usecaseifs.IOSCMessageArgument
 }
 
 const (
@@ -35,6 +31,10 @@ const (
 	ParamArguments     = "arguments"
 	ParamArgumentType  = "type"
 	ParamArgumentValue = "value"
+	ParamArgumentUnit  = "unit"
+
+	// UnitX32DB converts a dB level (e.g. -10, -inf) to an X32 fader float.
+	UnitX32DB = "x32_db"
 )
 
 func NewFactory(log usecaseifs.ILogger, debug bool, connections map[string]usecaseifs.IOSCConnection) usecaseifs.ActionTaskFactory {
@@ -76,21 +76,31 @@ func (o *SendOscMessageTask) SetParameters(m map[string]interface{}) {
 		return
 	}
 	// nolint:forcetypeassert
-	argsSlice := args.([]interface{})
+	if o.arguments, err = ParseArguments(args.([]interface{})); err != nil {
+		o.configError = fmt.Errorf("osc_message task failed to verify parameters: %w", err)
+		return
+	}
+}
+
+// ParseArguments verifies the list of arguments (type, value and unit), and converts them to message arguments.
+// It is shared by the tasks sending OSC messages.
+func ParseArguments(argsSlice []interface{}) ([]usecaseifs.IOSCMessageArgument, error) {
+	arguments := []usecaseifs.IOSCMessageArgument{}
 
 	for i, argParams := range argsSlice {
-		err = o.setArgumentParameters(argParams)
+		arg, err := parseArgument(argParams)
 		if err != nil {
-			o.configError = fmt.Errorf("osc_message task failed to verify parameters: Agrgument[%d]: %w", i, err)
-			return
+			return nil, fmt.Errorf("argument[%d]: %w", i, err)
 		}
+		arguments = append(arguments, arg)
 	}
+	return arguments, nil
 }
 
-func (o *SendOscMessageTask) setArgumentParameters(m interface{}) error {
+func parseArgument(m interface{}) (usecaseifs.IOSCMessageArgument, error) {
 	mCasted, ok := m.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("failed to cast supplied arguments")
+		return nil, fmt.Errorf("failed to cast supplied arguments")
 	}
 	sanitized, err := paramsanitizer.SanitizeParams(mCasted, []paramsanitizer.ParameterDefinition{
 		{
@@ -103,20 +113,35 @@ func (o *SendOscMessageTask) setArgumentParameters(m interface{}) error {
 			Optional: false,
 			Type:     []string{"string"},
 		},
+		{
+			Name:         ParamArgumentUnit,
+			Optional:     true,
+			DefaultValue: "",
+			ValuePattern: fmt.Sprintf("^(%s|)$", UnitX32DB),
+			Type:         []string{"string"},
+		},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	newArg := argument{}
-
 	// nolint:forcetypeassert
-	newArg.variableType = sanitized[ParamArgumentType].(string)
+	variableType := sanitized[ParamArgumentType].(string)
 	// nolint:forcetypeassert
-	newArg.variableValue = sanitized[ParamArgumentValue].(string)
+	variableValue := sanitized[ParamArgumentValue].(string)
 
-	o.arguments = append(o.arguments, newArg)
-	return nil
+	if sanitized[ParamArgumentUnit] == UnitX32DB {
+		if variableType != "float32" {
+			return nil, fmt.Errorf("%s %s requires a float32 argument", ParamArgumentUnit, UnitX32DB)
+		}
+		db, err := x32.ParseDB(variableValue)
+		if err != nil {
+			return nil, err
+		}
+		variableValue = fmt.Sprintf("%f", x32.DBToFader(db))
+	}
+
+	return osc_message.NewMessageArgument(variableType, variableValue), nil
 }
 
 func (o *SendOscMessageTask) Validate() error {
@@ -131,13 +156,11 @@ func (o *SendOscMessageTask) Execute(ctx context.Context, store usecaseifs.IMess
 		return fmt.Errorf("there is no osc connection named '%s'", o.connection)
 	}
 
-	args := []usecaseifs.IOSCMessageArgument{}
+	msg := osc_message.NewMessage(o.address, o.arguments)
 
-	for _, a := range o.arguments {
-		arg := osc_message.NewMessageArgument(a.variableType, a.variableValue)
-		args = append(args, arg)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not sending message: %s: %w", msg.String(), err)
 	}
-	msg := osc_message.NewMessage(o.address, args)
 
 	err := conn.SendMessage(ctx, msg)
 	if err != nil {
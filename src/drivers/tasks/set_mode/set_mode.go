@@ -0,0 +1,77 @@
+package set_mode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &SetMode{}
+
+// SetMode switches the active mode, by updating it in the message store.
+type SetMode struct {
+	updater     usecaseifs.IRecordUpdater
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+	modes       []string
+
+	mode string
+}
+
+const (
+	ParamModeKey = "mode"
+)
+
+func NewFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool, modes []string) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &SetMode{updater: updater, log: log, debug: debug, modes: modes}
+	}
+}
+
+func (o *SetMode) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamModeKey,
+			Optional: false,
+			Type:     []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.mode = sanitized[ParamModeKey].(string)
+
+	if !slicetools.Contains(o.modes, o.mode) {
+		o.configError = fmt.Errorf("invalid mode: '%s', valid values: %s", o.mode, strings.Join(o.modes, ","))
+	}
+}
+
+func (o *SetMode) Validate() error {
+	return o.configError
+}
+
+func (o *SetMode) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: set mode to %s", o.mode)
+
+	err := o.updater.UpdateRecord(ctx, entities.BridgeModeAddress, func(_ usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		return osc_message.NewMessage(entities.BridgeModeAddress, []usecaseifs.IOSCMessageArgument{
+			osc_message.NewMessageArgument("string", o.mode),
+		}), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set mode to %s: %w", o.mode, err)
+	}
+
+	return nil
+}
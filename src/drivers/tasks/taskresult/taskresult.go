@@ -0,0 +1,202 @@
+// Package taskresult stores the results of the tasks (e.g. the response of a HTTP request) into the message store,
+// configured by the store_result parameter.
+package taskresult
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/pkg/jsonpath"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const (
+	ParamStoreResultKey = "store_result"
+	ParamAddressKey     = "address"
+	ParamJSONPathKey    = "json_path"
+	ParamRegexpKey      = "regexp"
+
+	// ValueName is the name of the address holding the extracted value, if an extraction is configured.
+	ValueName = "value"
+)
+
+// StoreResultParameter is the parameter definition of store_result, shared by the tasks supporting it.
+var StoreResultParameter = paramsanitizer.ParameterDefinition{
+	Name:         ParamStoreResultKey,
+	Optional:     true,
+	DefaultValue: nil,
+	Type:         []string{"map[string]interface {}"},
+}
+
+// Storer writes the results under the configured address, e.g. the status code of a HTTP request to <address>/status.
+// The output (e.g. the body) is stored as <address>/<name>, or if an extraction is configured,
+// only the extracted value is stored as <address>/value.
+type Storer struct {
+	updater  usecaseifs.IRecordUpdater
+	address  string
+	jsonPath string
+	regexp   *regexp.Regexp
+}
+
+// NewStorer parses the value of the store_result parameter, it returns nil if it is not set.
+func NewStorer(updater usecaseifs.IRecordUpdater, parameter interface{}) (*Storer, error) {
+	if parameter == nil {
+		return nil, nil
+	}
+
+	m, ok := parameter.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map, got %T", ParamStoreResultKey, parameter)
+	}
+
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamAddressKey,
+			Optional:     false,
+			ValuePattern: "^/.*[^/]$",
+			Type:         []string{"string"},
+		},
+		{
+			Name:         ParamJSONPathKey,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		},
+		{
+			Name:         ParamRegexpKey,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify %s: %w", ParamStoreResultKey, err)
+	}
+
+	// nolint:forcetypeassert
+	s := &Storer{
+		updater:  updater,
+		address:  sanitized[ParamAddressKey].(string),
+		jsonPath: sanitized[ParamJSONPathKey].(string),
+	}
+
+	// nolint:forcetypeassert
+	pattern := sanitized[ParamRegexpKey].(string)
+
+	if s.jsonPath != "" && pattern != "" {
+		return nil, fmt.Errorf("only one of %s and %s can be set", ParamJSONPathKey, ParamRegexpKey)
+	}
+
+	if s.jsonPath != "" {
+		if err := jsonpath.Validate(s.jsonPath); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ParamJSONPathKey, err)
+		}
+	}
+
+	if pattern != "" {
+		if s.regexp, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ParamRegexpKey, err)
+		}
+	}
+
+	return s, nil
+}
+
+// StoreCode stores [code] as an int32 under <address>/<name>.
+func (s *Storer) StoreCode(ctx context.Context, name string, code int) error {
+	return s.store(ctx, name, osc_message.NewMessageArgument("int32", strconv.Itoa(code)))
+}
+
+// StoreOutput stores [output] as a string under <address>/<name>, or the extracted value under <address>/value.
+func (s *Storer) StoreOutput(ctx context.Context, name string, output string) error {
+	switch {
+	case s.jsonPath != "":
+		var document interface{}
+		if err := json.Unmarshal([]byte(output), &document); err != nil {
+			return fmt.Errorf("failed to parse %s as JSON: %w", name, err)
+		}
+
+		value, err := jsonpath.Get(document, s.jsonPath)
+		if err != nil {
+			return fmt.Errorf("failed to select %s from %s: %w", s.jsonPath, name, err)
+		}
+		return s.store(ctx, ValueName, newValueArgument(value))
+
+	case s.regexp != nil:
+		match := s.regexp.FindStringSubmatch(output)
+		if match == nil {
+			return fmt.Errorf("%s does not match %s", name, s.regexp.String())
+		}
+
+		// The first capturing group is the value, if there is one.
+		value := match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+		return s.store(ctx, ValueName, osc_message.NewMessageArgument("string", value))
+
+	default:
+		return s.store(ctx, name, osc_message.NewMessageArgument("string", output))
+	}
+}
+
+// StoreArguments stores [arguments] (e.g. of an OSC reply) as they are under <address>/<name>,
+// or if an extraction is configured, the value extracted from the first argument under <address>/value.
+func (s *Storer) StoreArguments(ctx context.Context, name string, arguments []usecaseifs.IOSCMessageArgument) error {
+	if s.jsonPath == "" && s.regexp == nil {
+		return s.storeAll(ctx, name, arguments)
+	}
+
+	if len(arguments) == 0 {
+		return fmt.Errorf("%s has no arguments to select from", name)
+	}
+	return s.StoreOutput(ctx, name, arguments[0].GetValue())
+}
+
+func (s *Storer) store(ctx context.Context, name string, argument usecaseifs.IOSCMessageArgument) error {
+	return s.storeAll(ctx, name, []usecaseifs.IOSCMessageArgument{argument})
+}
+
+func (s *Storer) storeAll(ctx context.Context, name string, arguments []usecaseifs.IOSCMessageArgument) error {
+	address := s.address + "/" + name
+
+	err := s.updater.UpdateRecord(ctx, address, func(_ usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		return osc_message.NewMessage(address, arguments), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store the result to %s: %w", address, err)
+	}
+	return nil
+}
+
+// newValueArgument converts a JSON value to an argument: whole numbers to int32, other numbers to float32,
+// booleans to int32 0 or 1, strings as they are, and the rest (objects, arrays, null) to their JSON form.
+func newValueArgument(value interface{}) usecaseifs.IOSCMessageArgument {
+	switch v := value.(type) {
+	case string:
+		return osc_message.NewMessageArgument("string", v)
+	case bool:
+		if v {
+			return osc_message.NewMessageArgument("int32", "1")
+		}
+		return osc_message.NewMessageArgument("int32", "0")
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+			return osc_message.NewMessageArgument("int32", fmt.Sprintf("%d", int32(v)))
+		}
+		return osc_message.NewMessageArgument("float32", fmt.Sprintf("%f", v))
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return osc_message.NewMessageArgument("string", fmt.Sprintf("%v", v))
+		}
+		return osc_message.NewMessageArgument("string", string(encoded))
+	}
+}
@@ -0,0 +1,66 @@
+package variables
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &ClearVariable{}
+
+// ClearVariable removes the value of a variable, the record remains in the store without arguments.
+type ClearVariable struct {
+	updater     usecaseifs.IRecordUpdater
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	name string
+}
+
+func NewClearVariable(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
+	return &ClearVariable{updater: updater, log: log, debug: debug}
+}
+
+func (o *ClearVariable) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamNameKey,
+			Optional:     false,
+			ValuePattern: namePattern,
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.name = sanitized[ParamNameKey].(string)
+}
+
+func (o *ClearVariable) Validate() error {
+	return o.configError
+}
+
+func (o *ClearVariable) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: clear variable %s", o.name)
+
+	err := o.updater.UpdateRecord(ctx, GetAddress(o.name), func(current usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		// There is nothing to clear, no need to create the record.
+		if current == nil {
+			return nil, nil
+		}
+		return osc_message.NewMessage(GetAddress(o.name), []usecaseifs.IOSCMessageArgument{}), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear variable %s: %w", o.name, err)
+	}
+
+	return nil
+}
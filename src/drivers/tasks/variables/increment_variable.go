@@ -0,0 +1,90 @@
+package variables
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &IncrementVariable{}
+
+// IncrementVariable adds a number to a numeric variable, a missing variable starts from 0.
+type IncrementVariable struct {
+	updater     usecaseifs.IRecordUpdater
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	name string
+	by   float64
+	// byIsFloat makes the result a float32, even if the variable was an int32 before.
+	byIsFloat bool
+}
+
+func NewIncrementVariable(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
+	return &IncrementVariable{updater: updater, log: log, debug: debug}
+}
+
+func (o *IncrementVariable) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamNameKey,
+			Optional:     false,
+			ValuePattern: namePattern,
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamByKey,
+			Optional:     true,
+			DefaultValue: 1,
+			Type:         []string{"int", "float64"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.name = sanitized[ParamNameKey].(string)
+
+	switch by := sanitized[ParamByKey].(type) {
+	case int:
+		o.by = float64(by)
+	case float64:
+		o.by = by
+		o.byIsFloat = true
+	}
+}
+
+func (o *IncrementVariable) Validate() error {
+	return o.configError
+}
+
+func (o *IncrementVariable) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: increment variable %s", o.name)
+
+	err := o.updater.UpdateRecord(ctx, GetAddress(o.name), func(current usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		value, valueType, err := getNumericValue(current)
+		if err != nil {
+			return nil, err
+		}
+
+		value += o.by
+		if o.byIsFloat || valueType == TypeFloat32 {
+			return newVariableMessage(o.name, TypeFloat32, formatFloat32(value)), nil
+		}
+		formatted, err := formatInt32(value)
+		if err != nil {
+			return nil, err
+		}
+		return newVariableMessage(o.name, TypeInt32, formatted), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to increment variable %s: %w", o.name, err)
+	}
+
+	return nil
+}
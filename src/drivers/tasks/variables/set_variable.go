@@ -0,0 +1,83 @@
+package variables
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &SetVariable{}
+
+// SetVariable writes a typed value into a variable.
+type SetVariable struct {
+	updater     usecaseifs.IRecordUpdater
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	name      string
+	valueType string
+	value     string
+}
+
+func NewSetVariable(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
+	return &SetVariable{updater: updater, log: log, debug: debug}
+}
+
+func (o *SetVariable) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamNameKey,
+			Optional:     false,
+			ValuePattern: namePattern,
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamTypeKey,
+			Optional:     true,
+			DefaultValue: TypeString,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s)$", TypeString, TypeInt32, TypeFloat32),
+			Type:         []string{"string"},
+		}, {
+			Name:     ParamValueKey,
+			Optional: false,
+			Type:     []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.name = sanitized[ParamNameKey].(string)
+	// nolint:forcetypeassert
+	o.valueType = sanitized[ParamTypeKey].(string)
+	// nolint:forcetypeassert
+	o.value, err = normalizeValue(o.valueType, sanitized[ParamValueKey].(string))
+	if err != nil {
+		o.configError = fmt.Errorf("invalid %s: %w", ParamValueKey, err)
+	}
+}
+
+func (o *SetVariable) Validate() error {
+	return o.configError
+}
+
+func (o *SetVariable) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: set variable %s", o.name)
+
+	err := o.updater.UpdateRecord(ctx, GetAddress(o.name), func(_ usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		return newVariableMessage(o.name, o.valueType, o.value), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set variable %s: %w", o.name, err)
+	}
+
+	if o.debug {
+		o.log.Debugf(ctx, "Variable %s is set to %s:%s", o.name, o.valueType, o.value)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package variables
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &ToggleVariable{}
+
+// ToggleVariable flips a variable between int32 0 and 1. Missing, cleared, zero or "false" values count as off.
+type ToggleVariable struct {
+	updater     usecaseifs.IRecordUpdater
+	log         usecaseifs.ILogger
+	debug       bool
+	configError error
+
+	name string
+}
+
+func NewToggleVariable(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.IActionTask {
+	return &ToggleVariable{updater: updater, log: log, debug: debug}
+}
+
+func (o *ToggleVariable) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:         ParamNameKey,
+			Optional:     false,
+			ValuePattern: namePattern,
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.name = sanitized[ParamNameKey].(string)
+}
+
+func (o *ToggleVariable) Validate() error {
+	return o.configError
+}
+
+func (o *ToggleVariable) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: toggle variable %s", o.name)
+
+	err := o.updater.UpdateRecord(ctx, GetAddress(o.name), func(current usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		if isOn(current) {
+			return newVariableMessage(o.name, TypeInt32, "0"), nil
+		}
+		return newVariableMessage(o.name, TypeInt32, "1"), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to toggle variable %s: %w", o.name, err)
+	}
+
+	return nil
+}
+
+func isOn(current usecaseifs.IOSCMessage) bool {
+	if current == nil || len(current.GetArguments()) == 0 {
+		return false
+	}
+
+	switch current.GetArguments()[0].GetValue() {
+	case "", "0", "0.000000", "false":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,105 @@
+// Package variables implements the tasks that read & write the bridge-internal variables.
+// The variables are ordinary records in the message store under the /var/ namespace,
+// therefore conditions can match them, their changes trigger the evaluation of the actions, and they are persisted with the store.
+package variables
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const (
+	// AddressPrefix is the reserved store namespace of the variables.
+	AddressPrefix = entities.VariablesAddressPrefix
+
+	ParamNameKey  = "name"
+	ParamTypeKey  = "type"
+	ParamValueKey = "value"
+	ParamByKey    = "by"
+
+	TypeString  = "string"
+	TypeInt32   = "int32"
+	TypeFloat32 = "float32"
+)
+
+// namePattern allows nested names like camera/current, but no empty parts or wildcards.
+const namePattern = `^[A-Za-z0-9_.\-]+(/[A-Za-z0-9_.\-]+)*$`
+
+func NewSetVariableFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return NewSetVariable(updater, log, debug) }
+}
+
+func NewIncrementVariableFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return NewIncrementVariable(updater, log, debug) }
+}
+
+func NewToggleVariableFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return NewToggleVariable(updater, log, debug) }
+}
+
+func NewClearVariableFactory(updater usecaseifs.IRecordUpdater, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask { return NewClearVariable(updater, log, debug) }
+}
+
+// GetAddress returns the store address of the named variable.
+func GetAddress(name string) string {
+	return AddressPrefix + name
+}
+
+// newVariableMessage creates the store message of a variable holding a single value.
+func newVariableMessage(name string, valueType string, value string) usecaseifs.IOSCMessage {
+	return osc_message.NewMessage(GetAddress(name), []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument(valueType, value)})
+}
+
+// normalizeValue checks if [value] can be represented as [valueType], and formats it the way the console bridges do.
+func normalizeValue(valueType string, value string) (string, error) {
+	switch valueType {
+	case TypeInt32:
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("'%s' is not a valid %s: %w", value, valueType, err)
+		}
+		return formatInt32(float64(i))
+	case TypeFloat32:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return "", fmt.Errorf("'%s' is not a valid %s: %w", value, valueType, err)
+		}
+		return formatFloat32(f), nil
+	default:
+		return value, nil
+	}
+}
+
+// formatInt32 formats the value as an int32, it fails if the value is out of the int32 range, instead of wrapping around.
+func formatInt32(value float64) (string, error) {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return "", fmt.Errorf("%v is out of the %s range", value, TypeInt32)
+	}
+	return fmt.Sprintf("%d", int32(value)), nil
+}
+
+func formatFloat32(value float64) string {
+	return fmt.Sprintf("%f", float32(value))
+}
+
+// getNumericValue returns the first argument of the variable as a number, along with its type.
+// A missing (or cleared) variable counts as an int32 0.
+func getNumericValue(current usecaseifs.IOSCMessage) (float64, string, error) {
+	if current == nil || len(current.GetArguments()) == 0 {
+		return 0, TypeInt32, nil
+	}
+
+	arg := current.GetArguments()[0]
+	value, err := strconv.ParseFloat(arg.GetValue(), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("variable %s is not numeric: %s", current.GetAddress(), arg.String())
+	}
+	return value, arg.GetType(), nil
+}
@@ -0,0 +1,32 @@
+package variables
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatInt32(t *testing.T) {
+	tests := []struct {
+		value   float64
+		want    string
+		wantErr bool
+	}{
+		{value: 0, want: "0"},
+		{value: -5, want: "-5"},
+		{value: math.MaxInt32, want: "2147483647"},
+		{value: math.MinInt32, want: "-2147483648"},
+		{value: math.MaxInt32 + 1, wantErr: true},
+		{value: math.MinInt32 - 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := formatInt32(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("formatInt32(%v) error = %v, wantErr %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("formatInt32(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
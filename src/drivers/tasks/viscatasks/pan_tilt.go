@@ -0,0 +1,145 @@
+package viscatasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &PanTilt{}
+
+const (
+	ParamPanKey       = "pan"
+	ParamTiltKey      = "tilt"
+	ParamDirectionKey = "direction"
+
+	ParamPanTiltAbsolute = "absolute"
+	ParamPanTiltRelative = "relative"
+	ParamPanTiltDrive    = "drive"
+	ParamPanTiltHome     = "home"
+)
+
+// PanTilt moves a camera to an absolute position, by a relative amount, in a direction, or home.
+type PanTilt struct {
+	viscaConnections map[string]*viscaremote.VISCARemote
+	log              usecaseifs.ILogger
+	debug            bool
+	configError      error
+
+	connection *viscaremote.VISCARemote
+	mode       string
+	pan        int
+	tilt       int
+	direction  string
+	panSpeed   int
+	tiltSpeed  int
+}
+
+func (o *PanTilt) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamModeKey,
+			Optional:     true,
+			DefaultValue: ParamPanTiltAbsolute,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s|%s)$", ParamPanTiltAbsolute, ParamPanTiltRelative, ParamPanTiltDrive, ParamPanTiltHome),
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamPanKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamTiltKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamDirectionKey,
+			Optional:     true,
+			DefaultValue: "",
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamPanSpeedKey,
+			Optional:     true,
+			DefaultValue: defaultPanSpeed,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamTiltSpeedKey,
+			Optional:     true,
+			DefaultValue: defaultTiltSpeed,
+			Type:         []string{"int"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.viscaConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.mode = sanitized[ParamModeKey].(string)
+	// nolint:forcetypeassert
+	o.pan = sanitized[ParamPanKey].(int)
+	// nolint:forcetypeassert
+	o.tilt = sanitized[ParamTiltKey].(int)
+	// nolint:forcetypeassert
+	o.direction = sanitized[ParamDirectionKey].(string)
+	// nolint:forcetypeassert
+	o.panSpeed = sanitized[ParamPanSpeedKey].(int)
+	// nolint:forcetypeassert
+	o.tiltSpeed = sanitized[ParamTiltSpeedKey].(int)
+
+	for _, err := range []error{
+		checkRange(ParamPanKey, o.pan, -32768, 32767),
+		checkRange(ParamTiltKey, o.tilt, -32768, 32767),
+		checkRange(ParamPanSpeedKey, o.panSpeed, 1, viscaremote.MaxPanSpeed),
+		checkRange(ParamTiltSpeedKey, o.tiltSpeed, 1, viscaremote.MaxTiltSpeed),
+	} {
+		if err != nil {
+			o.configError = err
+			return
+		}
+	}
+
+	if o.mode == ParamPanTiltDrive && !slicetools.Contains(viscaremote.DriveDirections, o.direction) {
+		o.configError = fmt.Errorf("invalid %s: '%s', valid values: %s", ParamDirectionKey, o.direction, strings.Join(viscaremote.DriveDirections, ","))
+	}
+}
+
+func (o *PanTilt) Validate() error {
+	return o.configError
+}
+
+func (o *PanTilt) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	switch o.mode {
+	case ParamPanTiltDrive:
+		o.log.Infof(ctx, "\tExecuting task: visca pan/tilt drive %s", o.direction)
+		return o.connection.PanTiltDrive(ctx, o.direction, o.panSpeed, o.tiltSpeed)
+
+	case ParamPanTiltHome:
+		o.log.Infof(ctx, "\tExecuting task: visca pan/tilt home")
+		return o.connection.PanTiltHome(ctx)
+
+	case ParamPanTiltRelative:
+		o.log.Infof(ctx, "\tExecuting task: visca pan/tilt relative %d/%d", o.pan, o.tilt)
+		return o.connection.PanTiltRelative(ctx, o.pan, o.tilt, o.panSpeed, o.tiltSpeed)
+
+	default:
+		o.log.Infof(ctx, "\tExecuting task: visca pan/tilt absolute %d/%d", o.pan, o.tilt)
+		return o.connection.PanTiltAbsolute(ctx, o.pan, o.tilt, o.panSpeed, o.tiltSpeed)
+	}
+}
@@ -0,0 +1,67 @@
+package viscatasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Power{}
+
+const (
+	ParamPowerKey = "power"
+	ParamPowerOn  = "on"
+	ParamPowerOff = "off"
+)
+
+// Power switches a camera on or off (standby).
+type Power struct {
+	viscaConnections map[string]*viscaremote.VISCARemote
+	log              usecaseifs.ILogger
+	debug            bool
+	configError      error
+
+	connection *viscaremote.VISCARemote
+	power      string
+}
+
+func (o *Power) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamPowerKey,
+			Optional:     false,
+			ValuePattern: fmt.Sprintf("^(%s|%s)$", ParamPowerOn, ParamPowerOff),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.viscaConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.power = sanitized[ParamPowerKey].(string)
+}
+
+func (o *Power) Validate() error {
+	return o.configError
+}
+
+func (o *Power) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: visca power %s", o.power)
+
+	return o.connection.SetPower(ctx, o.power == ParamPowerOn)
+}
@@ -0,0 +1,83 @@
+package viscatasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Preset{}
+
+const (
+	ParamPresetKey    = "preset"
+	ParamPresetRecall = "recall"
+	ParamPresetStore  = "store"
+)
+
+// Preset recalls or stores a preset of a camera.
+type Preset struct {
+	viscaConnections map[string]*viscaremote.VISCARemote
+	log              usecaseifs.ILogger
+	debug            bool
+	configError      error
+
+	connection *viscaremote.VISCARemote
+	preset     int
+	mode       string
+}
+
+func (o *Preset) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:     ParamPresetKey,
+			Optional: false,
+			Type:     []string{"int"},
+		}, {
+			Name:         ParamModeKey,
+			Optional:     true,
+			DefaultValue: ParamPresetRecall,
+			ValuePattern: fmt.Sprintf("^(%s|%s)$", ParamPresetRecall, ParamPresetStore),
+			Type:         []string{"string"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.viscaConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.preset = sanitized[ParamPresetKey].(int)
+	if err := checkRange(ParamPresetKey, o.preset, 0, 254); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.mode = sanitized[ParamModeKey].(string)
+}
+
+func (o *Preset) Validate() error {
+	return o.configError
+}
+
+func (o *Preset) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	o.log.Infof(ctx, "\tExecuting task: visca preset %s %d", o.mode, o.preset)
+
+	if o.mode == ParamPresetStore {
+		return o.connection.StorePreset(ctx, o.preset)
+	}
+	return o.connection.RecallPreset(ctx, o.preset)
+}
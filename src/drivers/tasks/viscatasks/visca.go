@@ -0,0 +1,61 @@
+package viscatasks
+
+import (
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+const (
+	ParamConnectionKey = "connection"
+	ParamPanSpeedKey   = "pan_speed"
+	ParamTiltSpeedKey  = "tilt_speed"
+	ParamSpeedKey      = "speed"
+	ParamModeKey       = "mode"
+
+	defaultPanSpeed  = 12
+	defaultTiltSpeed = 10
+	defaultZoomSpeed = 3
+)
+
+func NewPresetFactory(viscaConnections map[string]*viscaremote.VISCARemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Preset{viscaConnections: viscaConnections, log: log, debug: debug}
+	}
+}
+
+func NewPanTiltFactory(viscaConnections map[string]*viscaremote.VISCARemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &PanTilt{viscaConnections: viscaConnections, log: log, debug: debug}
+	}
+}
+
+func NewZoomFactory(viscaConnections map[string]*viscaremote.VISCARemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Zoom{viscaConnections: viscaConnections, log: log, debug: debug}
+	}
+}
+
+func NewPowerFactory(viscaConnections map[string]*viscaremote.VISCARemote, log usecaseifs.ILogger, debug bool) usecaseifs.ActionTaskFactory {
+	return func() usecaseifs.IActionTask {
+		return &Power{viscaConnections: viscaConnections, log: log, debug: debug}
+	}
+}
+
+// getConnection returns the named connection, or an error if there is none.
+func getConnection(viscaConnections map[string]*viscaremote.VISCARemote, name string) (*viscaremote.VISCARemote, error) {
+	conn, ok := viscaConnections[name]
+	if !ok {
+		return nil, fmt.Errorf("there is no visca connection named '%s'", name)
+	}
+	return conn, nil
+}
+
+// checkRange returns an error if the int parameter [name] is not within [min, max].
+func checkRange(name string, value int, min int, max int) error {
+	if value < min || value > max {
+		return fmt.Errorf("%s must be between %d and %d, got %d", name, min, max, value)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package viscatasks
+
+import (
+	"context"
+	"fmt"
+
+	"net.kopias.oscbridge/app/drivers/paramsanitizer"
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &Zoom{}
+
+const (
+	ParamPositionKey = "position"
+
+	ParamZoomAbsolute = "absolute"
+	ParamZoomRelative = "relative"
+
+	maxZoomPosition = 0xFFFF
+)
+
+// Zoom zooms a camera to an absolute position, by a relative amount, or drives it tele/wide until stopped.
+type Zoom struct {
+	viscaConnections map[string]*viscaremote.VISCARemote
+	log              usecaseifs.ILogger
+	debug            bool
+	configError      error
+
+	connection *viscaremote.VISCARemote
+	mode       string
+	position   int
+	speed      int
+}
+
+func (o *Zoom) SetParameters(m map[string]interface{}) {
+	sanitized, err := paramsanitizer.SanitizeParams(m, []paramsanitizer.ParameterDefinition{
+		{
+			Name:     ParamConnectionKey,
+			Optional: false,
+			Type:     []string{"string"},
+		}, {
+			Name:         ParamModeKey,
+			Optional:     true,
+			DefaultValue: ParamZoomAbsolute,
+			ValuePattern: fmt.Sprintf("^(%s|%s|%s|%s|%s)$", ParamZoomAbsolute, ParamZoomRelative, viscaremote.ZoomTele, viscaremote.ZoomWide, viscaremote.ZoomStop),
+			Type:         []string{"string"},
+		}, {
+			Name:         ParamPositionKey,
+			Optional:     true,
+			DefaultValue: 0,
+			Type:         []string{"int"},
+		}, {
+			Name:         ParamSpeedKey,
+			Optional:     true,
+			DefaultValue: defaultZoomSpeed,
+			Type:         []string{"int"},
+		},
+	})
+	if err != nil {
+		o.configError = fmt.Errorf("failed to verify parameters: %w", err)
+		return
+	}
+
+	// nolint:forcetypeassert
+	if o.connection, err = getConnection(o.viscaConnections, sanitized[ParamConnectionKey].(string)); err != nil {
+		o.configError = err
+		return
+	}
+
+	// nolint:forcetypeassert
+	o.mode = sanitized[ParamModeKey].(string)
+	// nolint:forcetypeassert
+	o.position = sanitized[ParamPositionKey].(int)
+	// nolint:forcetypeassert
+	o.speed = sanitized[ParamSpeedKey].(int)
+
+	minPosition := 0
+	if o.mode == ParamZoomRelative {
+		minPosition = -maxZoomPosition
+	}
+	if err := checkRange(ParamPositionKey, o.position, minPosition, maxZoomPosition); err != nil {
+		o.configError = err
+		return
+	}
+	if err := checkRange(ParamSpeedKey, o.speed, 0, viscaremote.MaxZoomSpeed); err != nil {
+		o.configError = err
+	}
+}
+
+func (o *Zoom) Validate() error {
+	return o.configError
+}
+
+func (o *Zoom) Execute(ctx context.Context, _ usecaseifs.IMessageStore) error {
+	switch o.mode {
+	case ParamZoomAbsolute:
+		o.log.Infof(ctx, "\tExecuting task: visca zoom to %d", o.position)
+		return o.connection.ZoomAbsolute(ctx, o.position)
+
+	case ParamZoomRelative:
+		current, err := o.connection.GetZoomPosition(ctx)
+		if err != nil {
+			return err
+		}
+
+		target := current + o.position
+		if target < 0 {
+			target = 0
+		}
+		if target > maxZoomPosition {
+			target = maxZoomPosition
+		}
+
+		o.log.Infof(ctx, "\tExecuting task: visca zoom by %d to %d", o.position, target)
+		return o.connection.ZoomAbsolute(ctx, target)
+
+	default:
+		o.log.Infof(ctx, "\tExecuting task: visca zoom %s", o.mode)
+		return o.connection.ZoomDrive(ctx, o.mode, o.speed)
+	}
+}
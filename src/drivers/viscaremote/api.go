@@ -0,0 +1,202 @@
+package viscaremote
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pan/tilt drive directions.
+const (
+	DirectionUp        = "up"
+	DirectionDown      = "down"
+	DirectionLeft      = "left"
+	DirectionRight     = "right"
+	DirectionUpLeft    = "up_left"
+	DirectionUpRight   = "up_right"
+	DirectionDownLeft  = "down_left"
+	DirectionDownRight = "down_right"
+	DirectionStop      = "stop"
+)
+
+// Zoom drive directions.
+const (
+	ZoomTele = "tele"
+	ZoomWide = "wide"
+	ZoomStop = "stop"
+)
+
+// Speed limits of the camera.
+const (
+	MaxPanSpeed  = 0x18
+	MaxTiltSpeed = 0x17
+	MaxZoomSpeed = 0x07
+)
+
+// driveDirections maps the directions to the pan (01 left, 02 right, 03 stop) and tilt (01 up, 02 down, 03 stop) bytes.
+var driveDirections = map[string][2]byte{
+	DirectionUp:        {0x03, 0x01},
+	DirectionDown:      {0x03, 0x02},
+	DirectionLeft:      {0x01, 0x03},
+	DirectionRight:     {0x02, 0x03},
+	DirectionUpLeft:    {0x01, 0x01},
+	DirectionUpRight:   {0x02, 0x01},
+	DirectionDownLeft:  {0x01, 0x02},
+	DirectionDownRight: {0x02, 0x02},
+	DirectionStop:      {0x03, 0x03},
+}
+
+// DriveDirections lists the valid directions of PanTiltDrive.
+var DriveDirections = []string{
+	DirectionUp, DirectionDown, DirectionLeft, DirectionRight,
+	DirectionUpLeft, DirectionUpRight, DirectionDownLeft, DirectionDownRight, DirectionStop,
+}
+
+func (vr *VISCARemote) SetPower(ctx context.Context, on bool) error {
+	value := byte(0x03)
+	if on {
+		value = 0x02
+	}
+
+	if err := vr.command(ctx, 0x81, 0x01, 0x04, 0x00, value, 0xFF); err != nil {
+		return fmt.Errorf("failed to set power: %w", err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) RecallPreset(ctx context.Context, preset int) error {
+	if err := vr.command(ctx, 0x81, 0x01, 0x04, 0x3F, 0x02, byte(preset), 0xFF); err != nil {
+		return fmt.Errorf("failed to recall preset %d: %w", preset, err)
+	}
+
+	vr.m.Lock()
+	vr.lastPreset = preset
+	vr.m.Unlock()
+	return nil
+}
+
+func (vr *VISCARemote) StorePreset(ctx context.Context, preset int) error {
+	if err := vr.command(ctx, 0x81, 0x01, 0x04, 0x3F, 0x01, byte(preset), 0xFF); err != nil {
+		return fmt.Errorf("failed to store preset %d: %w", preset, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) PanTiltDrive(ctx context.Context, direction string, panSpeed int, tiltSpeed int) error {
+	d, ok := driveDirections[direction]
+	if !ok {
+		return fmt.Errorf("invalid direction: %s", direction)
+	}
+
+	if err := vr.command(ctx, 0x81, 0x01, 0x06, 0x01, byte(panSpeed), byte(tiltSpeed), d[0], d[1], 0xFF); err != nil {
+		return fmt.Errorf("failed to drive pan/tilt %s: %w", direction, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) PanTiltAbsolute(ctx context.Context, pan int, tilt int, panSpeed int, tiltSpeed int) error {
+	if err := vr.command(ctx, vr.panTiltPosition(0x02, pan, tilt, panSpeed, tiltSpeed)...); err != nil {
+		return fmt.Errorf("failed to move pan/tilt to %d/%d: %w", pan, tilt, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) PanTiltRelative(ctx context.Context, pan int, tilt int, panSpeed int, tiltSpeed int) error {
+	if err := vr.command(ctx, vr.panTiltPosition(0x03, pan, tilt, panSpeed, tiltSpeed)...); err != nil {
+		return fmt.Errorf("failed to move pan/tilt by %d/%d: %w", pan, tilt, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) panTiltPosition(mode byte, pan int, tilt int, panSpeed int, tiltSpeed int) []byte {
+	payload := []byte{0x81, 0x01, 0x06, mode, byte(panSpeed), byte(tiltSpeed)}
+	payload = append(payload, EncodeSigned16(pan)...)
+	payload = append(payload, EncodeSigned16(tilt)...)
+	return append(payload, 0xFF)
+}
+
+func (vr *VISCARemote) PanTiltHome(ctx context.Context) error {
+	if err := vr.command(ctx, 0x81, 0x01, 0x06, 0x04, 0xFF); err != nil {
+		return fmt.Errorf("failed to move pan/tilt home: %w", err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) ZoomDrive(ctx context.Context, direction string, speed int) error {
+	var value byte
+	switch direction {
+	case ZoomStop:
+		value = 0x00
+	case ZoomTele:
+		value = 0x20 | byte(speed&0x0F)
+	case ZoomWide:
+		value = 0x30 | byte(speed&0x0F)
+	default:
+		return fmt.Errorf("invalid zoom direction: %s", direction)
+	}
+
+	if err := vr.command(ctx, 0x81, 0x01, 0x04, 0x07, value, 0xFF); err != nil {
+		return fmt.Errorf("failed to drive zoom %s: %w", direction, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) ZoomAbsolute(ctx context.Context, position int) error {
+	payload := append([]byte{0x81, 0x01, 0x04, 0x47}, EncodeNibbles(position, 4)...)
+	if err := vr.command(ctx, append(payload, 0xFF)...); err != nil {
+		return fmt.Errorf("failed to zoom to %d: %w", position, err)
+	}
+	return nil
+}
+
+func (vr *VISCARemote) GetPower(ctx context.Context) (bool, error) {
+	data, err := vr.inquiry(ctx, 0x81, 0x09, 0x04, 0x00, 0xFF)
+	if err != nil {
+		return false, fmt.Errorf("failed to inquire power: %w", err)
+	}
+	if len(data) < 1 {
+		return false, fmt.Errorf("failed to inquire power: %w", errNoData)
+	}
+	return data[0] == 0x02, nil
+}
+
+func (vr *VISCARemote) GetPanTiltPosition(ctx context.Context) (pan int, tilt int, err error) {
+	data, err := vr.inquiry(ctx, 0x81, 0x09, 0x06, 0x12, 0xFF)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to inquire pan/tilt position: %w", err)
+	}
+	if len(data) < 8 {
+		return 0, 0, fmt.Errorf("failed to inquire pan/tilt position: %w", errNoData)
+	}
+	return DecodeSigned16(data[0:4]), DecodeSigned16(data[4:8]), nil
+}
+
+func (vr *VISCARemote) GetZoomPosition(ctx context.Context) (int, error) {
+	data, err := vr.inquiry(ctx, 0x81, 0x09, 0x04, 0x47, 0xFF)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inquire zoom position: %w", err)
+	}
+	if len(data) < 4 {
+		return 0, fmt.Errorf("failed to inquire zoom position: %w", errNoData)
+	}
+	return DecodeNibbles(data[0:4]), nil
+}
+
+// GetPreset returns the current preset, or the last recalled one, if the camera does not support the inquiry.
+// It is -1 if neither is known.
+func (vr *VISCARemote) GetPreset(ctx context.Context) (int, error) {
+	data, err := vr.inquiry(ctx, 0x81, 0x09, 0x04, 0x3F, 0xFF)
+	if err == nil && len(data) >= 1 {
+		return int(data[0]), nil
+	}
+
+	vr.m.Lock()
+	defer vr.m.Unlock()
+
+	if vr.lastPreset >= 0 {
+		return vr.lastPreset, nil
+	}
+	if err == nil {
+		err = errNoData
+	}
+	return -1, fmt.Errorf("failed to inquire preset: %w", err)
+}
@@ -0,0 +1,112 @@
+package viscaremote
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// VISCA over IP frames every message with an 8 byte header: payload type (2), payload length (2), sequence number (4).
+const (
+	headerLength = 8
+
+	PayloadTypeCommand      uint16 = 0x0100
+	PayloadTypeInquiry      uint16 = 0x0110
+	PayloadTypeReply        uint16 = 0x0111
+	PayloadTypeControl      uint16 = 0x0200
+	PayloadTypeControlReply uint16 = 0x0201
+
+	// DefaultPort is the UDP port of VISCA over IP.
+	DefaultPort = 52381
+)
+
+var (
+	// ControlReset resets the sequence number of the camera.
+	ControlReset = []byte{0x01}
+	// ControlSequenceError is sent by the camera if the sequence number is unexpected.
+	ControlSequenceError = []byte{0x0F, 0x01}
+)
+
+// Packet is a single VISCA over IP message.
+type Packet struct {
+	PayloadType uint16
+	Sequence    uint32
+	Payload     []byte
+}
+
+// Encode returns the packet with its header.
+func (p Packet) Encode() []byte {
+	b := make([]byte, headerLength+len(p.Payload))
+	binary.BigEndian.PutUint16(b[0:2], p.PayloadType)
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(p.Payload)))
+	binary.BigEndian.PutUint32(b[4:8], p.Sequence)
+	copy(b[headerLength:], p.Payload)
+	return b
+}
+
+// DecodePacket parses a received datagram.
+func DecodePacket(b []byte) (Packet, error) {
+	if len(b) < headerLength {
+		return Packet{}, fmt.Errorf("packet too short: %d bytes", len(b))
+	}
+
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if len(b) < headerLength+length {
+		return Packet{}, fmt.Errorf("packet truncated: %d bytes, payload length: %d", len(b), length)
+	}
+
+	return Packet{
+		PayloadType: binary.BigEndian.Uint16(b[0:2]),
+		Sequence:    binary.BigEndian.Uint32(b[4:8]),
+		Payload:     append([]byte{}, b[headerLength:headerLength+length]...),
+	}, nil
+}
+
+// EncodeNibbles splits [value] into [count] bytes, each holding 4 bits, most significant first.
+// This is how VISCA transmits positions, e.g. 0x1234 becomes 01 02 03 04.
+func EncodeNibbles(value int, count int) []byte {
+	b := make([]byte, count)
+	for i := count - 1; i >= 0; i-- {
+		b[i] = byte(value & 0x0F)
+		value >>= 4
+	}
+	return b
+}
+
+// DecodeNibbles is the inverse of EncodeNibbles.
+func DecodeNibbles(b []byte) int {
+	value := 0
+	for _, nibble := range b {
+		value = value<<4 | int(nibble&0x0F)
+	}
+	return value
+}
+
+// EncodeSigned16 encodes a signed position (pan, tilt) as 4 nibbles in two's complement.
+func EncodeSigned16(value int) []byte {
+	return EncodeNibbles(int(uint16(int16(value))), 4)
+}
+
+// DecodeSigned16 is the inverse of EncodeSigned16.
+func DecodeSigned16(b []byte) int {
+	return int(int16(uint16(DecodeNibbles(b))))
+}
+
+// replyError converts the error code of an error reply (90 6y ee FF) to an error.
+func replyError(code byte) error {
+	switch code {
+	case 0x01:
+		return fmt.Errorf("message length error")
+	case 0x02:
+		return fmt.Errorf("syntax error")
+	case 0x03:
+		return fmt.Errorf("command buffer full")
+	case 0x04:
+		return fmt.Errorf("command cancelled")
+	case 0x05:
+		return fmt.Errorf("no socket")
+	case 0x41:
+		return fmt.Errorf("command not executable")
+	default:
+		return fmt.Errorf("error code 0x%02X", code)
+	}
+}
@@ -0,0 +1,223 @@
+// Package standin is a minimal VISCA over IP camera, to test the bridge without the actual hardware.
+// It keeps the state in memory, executes the commands used by viscaremote instantly, and answers their inquiries.
+package standin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+)
+
+// errNotExecutable is answered with the "command not executable" error, e.g. recalling a preset that was never stored.
+var errNotExecutable = errors.New("command not executable")
+
+// State is the state of the stand-in camera.
+type State struct {
+	Power  bool
+	Pan    int
+	Tilt   int
+	Zoom   int
+	Preset int
+	// Commands counts the executed commands.
+	Commands int
+}
+
+type position struct {
+	pan, tilt, zoom int
+}
+
+// Camera is a stand-in camera listening on UDP.
+type Camera struct {
+	conn *net.UDPConn
+
+	m       *sync.Mutex
+	state   State
+	presets map[int]position
+
+	// OnCommand is called after each executed command, if set.
+	OnCommand func(payload []byte, state State)
+}
+
+// NewCamera starts listening on [address], e.g. "127.0.0.1:52381", or "127.0.0.1:0" for a random port.
+func NewCamera(address string) (*Camera, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Camera{
+		conn:    conn,
+		m:       &sync.Mutex{},
+		state:   State{Power: true, Preset: -1},
+		presets: map[int]position{},
+	}, nil
+}
+
+// Addr returns the address the camera is listening on.
+func (c *Camera) Addr() *net.UDPAddr {
+	// nolint:forcetypeassert
+	return c.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// GetState returns a copy of the current state.
+func (c *Camera) GetState() State {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.state
+}
+
+// Serve answers the incoming packets until the camera is closed.
+func (c *Camera) Serve() error {
+	buf := make([]byte, 1024)
+	for {
+		n, from, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			return err
+		}
+
+		packet, err := viscaremote.DecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		for _, reply := range c.handle(packet) {
+			if _, err := c.conn.WriteToUDP(reply.Encode(), from); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close stops the camera.
+func (c *Camera) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Camera) handle(packet viscaremote.Packet) []viscaremote.Packet {
+	reply := func(payload ...byte) viscaremote.Packet {
+		return viscaremote.Packet{PayloadType: viscaremote.PayloadTypeReply, Sequence: packet.Sequence, Payload: payload}
+	}
+	syntaxError := reply(0x90, 0x60, 0x02, 0xFF)
+
+	switch packet.PayloadType {
+	case viscaremote.PayloadTypeControl:
+		return []viscaremote.Packet{{PayloadType: viscaremote.PayloadTypeControlReply, Sequence: packet.Sequence, Payload: []byte{0x01}}}
+
+	case viscaremote.PayloadTypeCommand:
+		if err := c.execute(packet.Payload); errors.Is(err, errNotExecutable) {
+			return []viscaremote.Packet{reply(0x90, 0x41, 0xFF), reply(0x90, 0x61, 0x41, 0xFF)}
+		} else if err != nil {
+			return []viscaremote.Packet{syntaxError}
+		}
+		return []viscaremote.Packet{reply(0x90, 0x41, 0xFF), reply(0x90, 0x51, 0xFF)}
+
+	case viscaremote.PayloadTypeInquiry:
+		data, err := c.inquire(packet.Payload)
+		if err != nil {
+			return []viscaremote.Packet{syntaxError}
+		}
+		return []viscaremote.Packet{reply(append(append([]byte{0x90, 0x50}, data...), 0xFF)...)}
+
+	default:
+		return nil
+	}
+}
+
+func (c *Camera) execute(p []byte) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	switch {
+	case hasPrefix(p, 0x81, 0x01, 0x04, 0x00) && len(p) == 6:
+		c.state.Power = p[4] == 0x02
+
+	case hasPrefix(p, 0x81, 0x01, 0x04, 0x3F, 0x01) && len(p) == 7:
+		c.presets[int(p[5])] = position{c.state.Pan, c.state.Tilt, c.state.Zoom}
+
+	case hasPrefix(p, 0x81, 0x01, 0x04, 0x3F, 0x02) && len(p) == 7:
+		pos, ok := c.presets[int(p[5])]
+		if !ok {
+			return fmt.Errorf("preset %d is not stored: %w", p[5], errNotExecutable)
+		}
+		c.state.Pan, c.state.Tilt, c.state.Zoom = pos.pan, pos.tilt, pos.zoom
+		c.state.Preset = int(p[5])
+
+	case hasPrefix(p, 0x81, 0x01, 0x06, 0x01) && len(p) == 9:
+		// Driving moves by one step per command.
+		c.state.Pan += map[byte]int{0x01: -1, 0x02: 1}[p[6]] * int(p[4])
+		c.state.Tilt += map[byte]int{0x01: 1, 0x02: -1}[p[7]] * int(p[5])
+
+	case hasPrefix(p, 0x81, 0x01, 0x06, 0x02) && len(p) == 15:
+		c.state.Pan = viscaremote.DecodeSigned16(p[6:10])
+		c.state.Tilt = viscaremote.DecodeSigned16(p[10:14])
+
+	case hasPrefix(p, 0x81, 0x01, 0x06, 0x03) && len(p) == 15:
+		c.state.Pan += viscaremote.DecodeSigned16(p[6:10])
+		c.state.Tilt += viscaremote.DecodeSigned16(p[10:14])
+
+	case bytes.Equal(p, []byte{0x81, 0x01, 0x06, 0x04, 0xFF}):
+		c.state.Pan, c.state.Tilt = 0, 0
+
+	case hasPrefix(p, 0x81, 0x01, 0x04, 0x07) && len(p) == 6:
+		speed := int(p[4]&0x0F) + 1
+		switch p[4] & 0xF0 {
+		case 0x20:
+			c.state.Zoom += speed * 0x100
+		case 0x30:
+			c.state.Zoom -= speed * 0x100
+		}
+
+	case hasPrefix(p, 0x81, 0x01, 0x04, 0x47) && len(p) == 9:
+		c.state.Zoom = viscaremote.DecodeNibbles(p[4:8])
+
+	default:
+		return fmt.Errorf("unknown command: % X", p)
+	}
+
+	c.state.Commands++
+	if c.OnCommand != nil {
+		c.OnCommand(p, c.state)
+	}
+	return nil
+}
+
+func (c *Camera) inquire(p []byte) ([]byte, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	switch {
+	case bytes.Equal(p, []byte{0x81, 0x09, 0x04, 0x00, 0xFF}):
+		if c.state.Power {
+			return []byte{0x02}, nil
+		}
+		return []byte{0x03}, nil
+
+	case bytes.Equal(p, []byte{0x81, 0x09, 0x06, 0x12, 0xFF}):
+		return append(viscaremote.EncodeSigned16(c.state.Pan), viscaremote.EncodeSigned16(c.state.Tilt)...), nil
+
+	case bytes.Equal(p, []byte{0x81, 0x09, 0x04, 0x47, 0xFF}):
+		return viscaremote.EncodeNibbles(c.state.Zoom, 4), nil
+
+	case bytes.Equal(p, []byte{0x81, 0x09, 0x04, 0x3F, 0xFF}):
+		if c.state.Preset < 0 {
+			return nil, fmt.Errorf("no preset was recalled")
+		}
+		return []byte{byte(c.state.Preset)}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown inquiry: % X", p)
+	}
+}
+
+func hasPrefix(p []byte, prefix ...byte) bool {
+	return bytes.HasPrefix(p, prefix)
+}
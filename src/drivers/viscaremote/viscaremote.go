@@ -0,0 +1,256 @@
+package viscaremote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IVISCARemote = &VISCARemote{}
+
+type Config struct {
+	Host string
+	Port int64
+	// TimeoutMillis is the time to wait for the completion of a command or inquiry.
+	TimeoutMillis int64
+	Debug         bool
+}
+
+// VISCARemote controls a PTZ camera with VISCA over IP (UDP), and provides the IVISCARemote interface.
+type VISCARemote struct {
+	log usecaseifs.ILogger
+	cfg Config
+
+	conn    *net.UDPConn
+	replies chan Packet
+	quit    chan interface{}
+
+	stopOnce *sync.Once
+
+	// m serializes the requests, as the camera answers them in order, and the sequence numbers must be increasing.
+	m        *sync.Mutex
+	sequence uint32
+	// needsReset is set if the camera rejected the sequence number, so it is reset before the next request.
+	needsReset bool
+
+	// lastPreset is the last recalled preset, used if the camera does not answer the preset inquiry.
+	lastPreset int
+}
+
+func NewVISCARemote(log usecaseifs.ILogger, cfg Config) *VISCARemote {
+	return &VISCARemote{
+		log:        log,
+		cfg:        cfg,
+		replies:    make(chan Packet, 16),
+		quit:       make(chan interface{}),
+		stopOnce:   &sync.Once{},
+		m:          &sync.Mutex{},
+		lastPreset: -1,
+	}
+}
+
+func (vr *VISCARemote) Start(ctx context.Context) error {
+	addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", vr.cfg.Host, vr.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to resolve camera address: %w", err)
+	}
+
+	vr.conn, err = net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return fmt.Errorf("failed to open udp socket: %w", err)
+	}
+
+	go vr.receive(ctx)
+
+	// The camera may be switched on later, so it is not fatal if it does not answer now.
+	if err := vr.reset(ctx); err != nil {
+		vr.log.Warnf(ctx, "VISCA camera at %s did not answer the sequence reset: %s", addr, err)
+	}
+	return nil
+}
+
+// Stop closes the socket, the pending requests return with an error.
+func (vr *VISCARemote) Stop(ctx context.Context) {
+	vr.stopOnce.Do(func() {
+		close(vr.quit)
+
+		if vr.conn != nil {
+			if err := vr.conn.Close(); err != nil {
+				vr.log.Err(ctx, err)
+			}
+		}
+	})
+}
+
+// receive reads the replies of the camera until the remote is stopped.
+func (vr *VISCARemote) receive(ctx context.Context) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := vr.conn.Read(buf)
+
+		select {
+		case <-vr.quit:
+			return
+		default:
+		}
+
+		if err != nil {
+			// E.g. "connection refused" is reported on a connected UDP socket, when the camera is not listening.
+			if vr.cfg.Debug {
+				vr.log.Debugf(ctx, "VISCA read failed: %s", err)
+			}
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		packet, err := DecodePacket(buf[:n])
+		if err != nil {
+			vr.log.Warnf(ctx, "Ignoring invalid VISCA packet: %s", err)
+			continue
+		}
+
+		if vr.cfg.Debug {
+			vr.log.Debugf(ctx, "VISCA received: type: %04X seq: %d payload: % X", packet.PayloadType, packet.Sequence, packet.Payload)
+		}
+
+		select {
+		case vr.replies <- packet:
+		default:
+			vr.log.Warnf(ctx, "Dropping VISCA reply, nobody is waiting for it: % X", packet.Payload)
+		}
+	}
+}
+
+// reset sets the sequence number of the camera and the remote back to zero.
+func (vr *VISCARemote) reset(ctx context.Context) error {
+	vr.m.Lock()
+	defer vr.m.Unlock()
+
+	return vr.resetLocked(ctx)
+}
+
+func (vr *VISCARemote) resetLocked(ctx context.Context) error {
+	vr.sequence = 0
+	if _, err := vr.request(ctx, PayloadTypeControl, ControlReset); err != nil {
+		return err
+	}
+	vr.needsReset = false
+	return nil
+}
+
+// command sends a VISCA command and waits for its completion.
+func (vr *VISCARemote) command(ctx context.Context, payload ...byte) error {
+	vr.m.Lock()
+	defer vr.m.Unlock()
+
+	_, err := vr.request(ctx, PayloadTypeCommand, payload)
+	return err
+}
+
+// inquiry sends a VISCA inquiry and returns the data of its answer, without the leading 90 50 and trailing FF.
+func (vr *VISCARemote) inquiry(ctx context.Context, payload ...byte) ([]byte, error) {
+	vr.m.Lock()
+	defer vr.m.Unlock()
+
+	return vr.request(ctx, PayloadTypeInquiry, payload)
+}
+
+// request sends a single packet and awaits the corresponding reply, it must be called holding the lock.
+func (vr *VISCARemote) request(ctx context.Context, payloadType uint16, payload []byte) ([]byte, error) {
+	select {
+	case <-vr.quit:
+		return nil, fmt.Errorf("not connected")
+	default:
+	}
+
+	if vr.needsReset && payloadType != PayloadTypeControl {
+		if err := vr.resetLocked(ctx); err != nil {
+			return nil, fmt.Errorf("failed to reset the sequence number: %w", err)
+		}
+	}
+
+	// Replies of former, timed out requests are obsolete.
+	vr.drainReplies()
+
+	vr.sequence++
+	packet := Packet{PayloadType: payloadType, Sequence: vr.sequence, Payload: payload}
+
+	if vr.cfg.Debug {
+		vr.log.Debugf(ctx, "VISCA sending: type: %04X seq: %d payload: % X", packet.PayloadType, packet.Sequence, packet.Payload)
+	}
+
+	if _, err := vr.conn.Write(packet.Encode()); err != nil {
+		return nil, fmt.Errorf("failed to send: %w", err)
+	}
+
+	timeout := time.NewTimer(time.Duration(vr.cfg.TimeoutMillis) * time.Millisecond)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case reply := <-vr.replies:
+			if reply.Sequence != packet.Sequence {
+				continue
+			}
+
+			data, done, err := vr.processReply(payloadType, reply)
+			if done || err != nil {
+				return data, err
+			}
+
+		case <-timeout.C:
+			return nil, fmt.Errorf("no reply from the camera within %dms", vr.cfg.TimeoutMillis)
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-vr.quit:
+			return nil, fmt.Errorf("not connected")
+		}
+	}
+}
+
+// processReply interprets a reply, done is false if it was an acknowledgement, and the completion is still to come.
+func (vr *VISCARemote) processReply(payloadType uint16, reply Packet) (data []byte, done bool, err error) {
+	if reply.PayloadType == PayloadTypeControlReply {
+		if len(reply.Payload) >= 2 && reply.Payload[0] == ControlSequenceError[0] && reply.Payload[1] == ControlSequenceError[1] {
+			vr.needsReset = true
+			return nil, true, fmt.Errorf("the camera rejected the sequence number")
+		}
+		return nil, payloadType == PayloadTypeControl, nil
+	}
+
+	p := reply.Payload
+	if len(p) < 3 || p[len(p)-1] != 0xFF {
+		return nil, true, fmt.Errorf("malformed reply: % X", p)
+	}
+
+	switch p[1] & 0xF0 {
+	case 0x40:
+		return nil, false, nil
+	case 0x50:
+		return p[2 : len(p)-1], true, nil
+	case 0x60:
+		return nil, true, fmt.Errorf("the camera returned an error: %w", replyError(p[2]))
+	default:
+		return nil, true, fmt.Errorf("unexpected reply: % X", p)
+	}
+}
+
+func (vr *VISCARemote) drainReplies() {
+	for {
+		select {
+		case <-vr.replies:
+		default:
+			return
+		}
+	}
+}
+
+// errNoData is returned, when the answer of an inquiry is shorter than expected.
+var errNoData = errors.New("the answer does not contain the expected data")
@@ -0,0 +1,147 @@
+package viscaremote_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"net.kopias.oscbridge/app/drivers/viscaremote"
+	"net.kopias.oscbridge/app/drivers/viscaremote/standin"
+	"net.kopias.oscbridge/app/pkg/logger"
+)
+
+// startRemote connects a remote to the UDP address, and stops it at the end of the test.
+func startRemote(t *testing.T, addr *net.UDPAddr, timeoutMillis int64) *viscaremote.VISCARemote {
+	t.Helper()
+
+	remote := viscaremote.NewVISCARemote(logger.New(), viscaremote.Config{
+		Host:          addr.IP.String(),
+		Port:          int64(addr.Port),
+		TimeoutMillis: timeoutMillis,
+	})
+	if err := remote.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start the remote: %s", err)
+	}
+	t.Cleanup(func() { remote.Stop(context.Background()) })
+	return remote
+}
+
+// startCamera starts a stand-in camera on a random port, and closes it at the end of the test.
+func startCamera(t *testing.T) *standin.Camera {
+	t.Helper()
+
+	camera, err := standin.NewCamera("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the camera: %s", err)
+	}
+	go func() {
+		// nolint:errcheck
+		camera.Serve()
+	}()
+	t.Cleanup(func() {
+		// nolint:errcheck
+		camera.Close()
+	})
+	return camera
+}
+
+func TestCommandsAndInquiries(t *testing.T) {
+	camera := startCamera(t)
+	remote := startRemote(t, camera.Addr(), 1000)
+	ctx := context.Background()
+
+	if err := remote.SetPower(ctx, false); err != nil {
+		t.Fatalf("SetPower failed: %s", err)
+	}
+	if camera.GetState().Power {
+		t.Errorf("the camera is still powered on")
+	}
+	if on, err := remote.GetPower(ctx); err != nil || on {
+		t.Errorf("GetPower = %t, %v, expected false", on, err)
+	}
+	if err := remote.SetPower(ctx, true); err != nil {
+		t.Fatalf("SetPower failed: %s", err)
+	}
+
+	if err := remote.PanTiltAbsolute(ctx, -300, 120, 10, 10); err != nil {
+		t.Fatalf("PanTiltAbsolute failed: %s", err)
+	}
+	if err := remote.PanTiltRelative(ctx, 50, -20, 10, 10); err != nil {
+		t.Fatalf("PanTiltRelative failed: %s", err)
+	}
+	if state := camera.GetState(); state.Pan != -250 || state.Tilt != 100 {
+		t.Errorf("the camera is at %d/%d, expected -250/100", state.Pan, state.Tilt)
+	}
+	if pan, tilt, err := remote.GetPanTiltPosition(ctx); err != nil || pan != -250 || tilt != 100 {
+		t.Errorf("GetPanTiltPosition = %d/%d, %v, expected -250/100", pan, tilt, err)
+	}
+
+	if err := remote.ZoomAbsolute(ctx, 0x1234); err != nil {
+		t.Fatalf("ZoomAbsolute failed: %s", err)
+	}
+	if zoom, err := remote.GetZoomPosition(ctx); err != nil || zoom != 0x1234 {
+		t.Errorf("GetZoomPosition = %d, %v, expected %d", zoom, err, 0x1234)
+	}
+
+	if err := remote.StorePreset(ctx, 3); err != nil {
+		t.Fatalf("StorePreset failed: %s", err)
+	}
+	if err := remote.PanTiltHome(ctx); err != nil {
+		t.Fatalf("PanTiltHome failed: %s", err)
+	}
+	if err := remote.RecallPreset(ctx, 3); err != nil {
+		t.Fatalf("RecallPreset failed: %s", err)
+	}
+	if state := camera.GetState(); state.Pan != -250 || state.Tilt != 100 || state.Zoom != 0x1234 {
+		t.Errorf("the camera is at %d/%d/%d after recalling the preset, expected -250/100/%d", state.Pan, state.Tilt, state.Zoom, 0x1234)
+	}
+	if preset, err := remote.GetPreset(ctx); err != nil || preset != 3 {
+		t.Errorf("GetPreset = %d, %v, expected 3", preset, err)
+	}
+}
+
+func TestErrorReply(t *testing.T) {
+	camera := startCamera(t)
+	remote := startRemote(t, camera.Addr(), 1000)
+
+	// The preset was never stored, so the camera answers "command not executable".
+	err := remote.RecallPreset(context.Background(), 7)
+	if err == nil || !strings.Contains(err.Error(), "command not executable") {
+		t.Fatalf("RecallPreset = %v, expected a command not executable error", err)
+	}
+
+	// The remote can still be used after an error reply.
+	if _, err := remote.GetPower(context.Background()); err != nil {
+		t.Errorf("GetPower failed after the error reply: %s", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	// A socket that never answers.
+	silent, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer silent.Close()
+
+	// The unanswered sequence reset is not fatal, the camera may be switched on later.
+	// nolint:forcetypeassert
+	remote := startRemote(t, silent.LocalAddr().(*net.UDPAddr), 100)
+
+	start := time.Now()
+	err = remote.SetPower(context.Background(), true)
+	if err == nil || !strings.Contains(err.Error(), "no reply from the camera within 100ms") {
+		t.Fatalf("SetPower = %v, expected a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the timeout took %s, expected about 100ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := remote.GetPower(ctx); err == nil {
+		t.Errorf("GetPower succeeded with a cancelled context")
+	}
+}
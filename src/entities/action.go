@@ -3,13 +3,81 @@ package entities
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
 
 var _ usecaseifs.IAction = &Action{}
 
+const (
+	// ConcurrencyParallel lets every triggered execution run, even if the action is already running.
+	ConcurrencyParallel = "parallel"
+	// ConcurrencyDropIfRunning ignores the trigger if the action is already running.
+	ConcurrencyDropIfRunning = "drop_if_running"
+	// ConcurrencyQueue serializes the executions, waiting in a bounded queue.
+	ConcurrencyQueue = "queue"
+	// ConcurrencyRestart cancels the running execution and starts over.
+	ConcurrencyRestart = "restart"
+
+	// DefaultQueueSize is used for ConcurrencyQueue if the queue size is not configured.
+	DefaultQueueSize = 10
+)
+
+const (
+	// ExclusiveFirstWins drops the executions of the group members while one of them is running.
+	ExclusiveFirstWins = "first_wins"
+	// ExclusiveHighestPriority lets a member with higher priority cancel the running one and take over.
+	ExclusiveHighestPriority = "highest_priority"
+	// ExclusiveLatestWins lets the latest triggered member cancel the running one and take over.
+	ExclusiveLatestWins = "latest_wins"
+)
+
+// ConcurrencyModes lists the valid concurrency modes.
+var ConcurrencyModes = []string{ConcurrencyParallel, ConcurrencyDropIfRunning, ConcurrencyQueue, ConcurrencyRestart}
+
+// ExclusivePolicies lists the valid contention policies of the exclusive groups.
+var ExclusivePolicies = []string{ExclusiveFirstWins, ExclusiveHighestPriority, ExclusiveLatestWins}
+
+// ActionOptions holds the optional settings of an action.
+type ActionOptions struct {
+	// DebounceMillis causes repeated evaluation with this delay to see if the condition is still true.
+	DebounceMillis int64
+
+	// Concurrency is one of the Concurrency* constants.
+	Concurrency string
+
+	// QueueSize is the maximum number of waiting executions in case of ConcurrencyQueue.
+	QueueSize int
+
+	// TimeoutMillis cancels the execution of the tasks if they take longer, 0 means no timeout.
+	TimeoutMillis int64
+
+	// Priority orders the evaluation of the actions, higher comes first.
+	Priority int
+
+	// StopAfterMatch skips the evaluation of the lower priority actions if this one fires.
+	StopAfterMatch bool
+
+	// ExclusiveGroup is the name of the group, within which only one action may execute at a time.
+	ExclusiveGroup string
+
+	// CooldownMillis is the minimum time between the start of two executions, 0 means no limit.
+	CooldownMillis int64
+
+	// MaxExecutionsPerMinute limits the executions within any one minute window, 0 means no limit.
+	MaxExecutionsPerMinute int
+
+	// Trailing runs the action once more with the latest state, when the limits allow, if executions were suppressed.
+	Trailing bool
+
+	// Modes lists the modes in which the action is active, empty means all of them.
+	Modes []string
+
+	// OnError is one of the OnError* constants, it determines what happens after a failed task.
+	OnError string
+}
+
 // Action represents a living, composed set of instances of triggers and tasks
 type Action struct {
 	name string
@@ -17,26 +85,73 @@ type Action struct {
 	triggerChain usecaseifs.IActionCondition
 
 	// tasks is a list of tasks that must be executed serially.
-	tasks []usecaseifs.IActionTask
+	tasks *TaskList
 
-	// debounceMillis causes repeated evaluation with this delay to see if the condition is still true.
-	debounceMillis int64
+	options ActionOptions
 }
 
 func (a *Action) GetDebounceMillis() int64 {
-	return a.debounceMillis
+	return a.options.DebounceMillis
 }
 
-func NewAction(name string, triggerChain usecaseifs.IActionCondition, tasks []usecaseifs.IActionTask, debounceMillis int64) *Action {
+func (a *Action) GetConcurrency() string {
+	return a.options.Concurrency
+}
+
+func (a *Action) GetQueueSize() int {
+	return a.options.QueueSize
+}
+
+func (a *Action) GetPriority() int {
+	return a.options.Priority
+}
+
+func (a *Action) ShouldStopAfterMatch() bool {
+	return a.options.StopAfterMatch
+}
+
+func (a *Action) GetExclusiveGroup() string {
+	return a.options.ExclusiveGroup
+}
+
+func (a *Action) GetCooldownMillis() int64 {
+	return a.options.CooldownMillis
+}
+
+func (a *Action) GetMaxExecutionsPerMinute() int {
+	return a.options.MaxExecutionsPerMinute
+}
+
+func (a *Action) ShouldRunTrailing() bool {
+	return a.options.Trailing
+}
+
+func (a *Action) GetModes() []string {
+	return a.options.Modes
+}
+
+func NewAction(name string, triggerChain usecaseifs.IActionCondition, tasks []usecaseifs.IActionTask, options ActionOptions) *Action {
+	if options.Concurrency == "" {
+		options.Concurrency = ConcurrencyParallel
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultQueueSize
+	}
+
 	return &Action{
-		name:           name,
-		triggerChain:   triggerChain,
-		tasks:          tasks,
-		debounceMillis: debounceMillis,
+		name:         name,
+		triggerChain: triggerChain,
+		tasks:        NewTaskList(tasks, options.OnError),
+		options:      options,
 	}
 }
 
+// Evaluate evaluates the trigger chain, actions without one (macros) always match.
 func (a *Action) Evaluate(ctx context.Context, store usecaseifs.IMessageStore) (bool, error) {
+	if a.triggerChain == nil {
+		return true, nil
+	}
+
 	matched, err := a.triggerChain.Evaluate(ctx, store)
 	if err != nil {
 		return false, fmt.Errorf("failed to evaluate trigger chain: %w", err)
@@ -45,18 +160,13 @@ func (a *Action) Evaluate(ctx context.Context, store usecaseifs.IMessageStore) (
 }
 
 func (a *Action) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
-	errs := []any{}
-	for i, task := range a.tasks {
-		if err := task.Execute(ctx, store); err != nil {
-			errs = append(errs, fmt.Errorf("failed to execute task %d: %w", i, err))
-		}
-	}
-
-	if len(errs) > 0 {
-		return fmt.Errorf("failed to exectue task(s) "+strings.Repeat(": %w", len(errs)), errs...)
+	if a.options.TimeoutMillis > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.options.TimeoutMillis)*time.Millisecond)
+		defer cancel()
 	}
 
-	return nil
+	return a.tasks.Execute(ctx, store)
 }
 
 func (a *Action) GetName() string {
@@ -0,0 +1,38 @@
+package entities
+
+import "strings"
+
+const (
+	// BridgeAddressPrefix is the reserved store namespace of the messages published by the bridge itself.
+	BridgeAddressPrefix = "/bridge/"
+
+	// BridgeModeAddress holds the active mode as a single string argument.
+	BridgeModeAddress = BridgeAddressPrefix + "mode"
+
+	// BridgeActionsAddressPrefix is the namespace of the per-action messages, e.g. /bridge/actions/<name>/enabled.
+	BridgeActionsAddressPrefix = BridgeAddressPrefix + "actions/"
+
+	// BridgeActionEnabled is the suffix of the address holding 1 if the action is enabled, 0 otherwise.
+	BridgeActionEnabled = "enabled"
+	// BridgeActionTrigger is the suffix of the address that executes the action upon any message. It is never stored.
+	BridgeActionTrigger = "trigger"
+)
+
+// GetBridgeActionAddress returns the address of the per-action message [suffix], e.g. /bridge/actions/<name>/enabled.
+func GetBridgeActionAddress(actionName string, suffix string) string {
+	return BridgeActionsAddressPrefix + actionName + "/" + suffix
+}
+
+// ParseBridgeActionAddress splits a per-action address into the action's name and the suffix.
+func ParseBridgeActionAddress(address string) (actionName string, suffix string, ok bool) {
+	rest, found := strings.CutPrefix(address, BridgeActionsAddressPrefix)
+	if !found {
+		return "", "", false
+	}
+
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
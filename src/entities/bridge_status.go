@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"sync"
+	"time"
+)
+
+// BridgeStatus collects the health & activity of the bridge, to be published by the status bridges.
+// It is safe for concurrent use.
+type BridgeStatus struct {
+	m *sync.RWMutex
+
+	startedAt   time.Time
+	storeSize   int
+	connections map[string]ConnectionStatus
+	actions     map[string]ActionStatus
+}
+
+// ConnectionStatus is the activity of a single connection.
+type ConnectionStatus struct {
+	LastMessageAt time.Time
+}
+
+// ActionStatus is the activity of a single action.
+type ActionStatus struct {
+	LastRunAt   time.Time
+	LastError   string
+	LastErrorAt time.Time
+	// Suppressed is the number of executions dropped by the rate limits.
+	Suppressed int64
+}
+
+// BridgeStatusSnapshot is a copy of the BridgeStatus at a given time.
+type BridgeStatusSnapshot struct {
+	StartedAt   time.Time
+	StoreSize   int
+	Connections map[string]ConnectionStatus
+	Actions     map[string]ActionStatus
+}
+
+func NewBridgeStatus() *BridgeStatus {
+	return &BridgeStatus{
+		m:           &sync.RWMutex{},
+		startedAt:   time.Now(),
+		connections: map[string]ConnectionStatus{},
+		actions:     map[string]ActionStatus{},
+	}
+}
+
+// RecordConnectionMessage registers that a message arrived from the named connection.
+func (s *BridgeStatus) RecordConnectionMessage(name string, at time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.connections[name] = ConnectionStatus{LastMessageAt: at}
+}
+
+// RecordActionRun registers a finished execution of the named action, [err] is nil if it succeeded.
+func (s *BridgeStatus) RecordActionRun(name string, at time.Time, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	status := s.actions[name]
+	status.LastRunAt = at
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorAt = at
+	}
+	s.actions[name] = status
+}
+
+// RecordActionSuppressed registers the number of executions of the named action dropped by the rate limits so far.
+func (s *BridgeStatus) RecordActionSuppressed(name string, suppressed int64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	status := s.actions[name]
+	status.Suppressed = suppressed
+	s.actions[name] = status
+}
+
+func (s *BridgeStatus) SetStoreSize(size int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.storeSize = size
+}
+
+// GetSnapshot returns a copy of the current status.
+func (s *BridgeStatus) GetSnapshot() BridgeStatusSnapshot {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	snapshot := BridgeStatusSnapshot{
+		StartedAt:   s.startedAt,
+		StoreSize:   s.storeSize,
+		Connections: make(map[string]ConnectionStatus, len(s.connections)),
+		Actions:     make(map[string]ActionStatus, len(s.actions)),
+	}
+	for name, status := range s.connections {
+		snapshot.Connections[name] = status
+	}
+	for name, status := range s.actions {
+		snapshot.Actions[name] = status
+	}
+	return snapshot
+}
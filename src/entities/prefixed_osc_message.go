@@ -41,3 +41,11 @@ func (p PrefixedOSCMessage) String() string {
 	}
 	return fmt.Sprintf("Message(address: %s, arguments: [%s])", p.GetAddress(), strings.Join(argStrings, ", "))
 }
+
+// UnprefixAddress returns the address of a message as its connection sent it, if [address] has the [prefix].
+func UnprefixAddress(prefix string, address string) (string, bool) {
+	if prefix == "" {
+		return address, true
+	}
+	return strings.CutPrefix(address, prefix+"/")
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &TaskList{}
+
+const (
+	// OnErrorContinue executes the rest of the tasks after a failed one, and reports all the errors at the end.
+	OnErrorContinue = "continue"
+	// OnErrorAbort skips the rest of the tasks after a failed one.
+	OnErrorAbort = "abort"
+)
+
+// OnErrorPolicies lists the valid error policies.
+var OnErrorPolicies = []string{OnErrorContinue, OnErrorAbort}
+
+// TaskList executes a list of tasks serially, following the error policy.
+type TaskList struct {
+	tasks   []usecaseifs.IActionTask
+	onError string
+}
+
+func NewTaskList(tasks []usecaseifs.IActionTask, onError string) *TaskList {
+	if onError == "" {
+		onError = OnErrorContinue
+	}
+	return &TaskList{tasks: tasks, onError: onError}
+}
+
+func (l *TaskList) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	errs := []any{}
+	for i, task := range l.tasks {
+		// The execution might have been cancelled (e.g. restarted) meanwhile, then the rest of the tasks are skipped.
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("execution cancelled before task %d: %w", i, err))
+			break
+		}
+
+		if err := task.Execute(ctx, store); err != nil {
+			errs = append(errs, fmt.Errorf("failed to execute task %d: %w", i, err))
+
+			if l.onError == OnErrorAbort {
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to execute task(s) "+strings.Repeat(": %w", len(errs)), errs...)
+	}
+
+	return nil
+}
+
+func (l *TaskList) SetParameters(_ map[string]interface{}) {}
+
+func (l *TaskList) Validate() error {
+	return nil
+}
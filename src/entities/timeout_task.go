@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+var _ usecaseifs.IActionTask = &TimeoutTask{}
+
+// TimeoutTask wraps a task, and cancels its execution if it takes longer than the configured time.
+type TimeoutTask struct {
+	task          usecaseifs.IActionTask
+	timeoutMillis int64
+}
+
+func NewTimeoutTask(task usecaseifs.IActionTask, timeoutMillis int64) *TimeoutTask {
+	return &TimeoutTask{task: task, timeoutMillis: timeoutMillis}
+}
+
+func (t *TimeoutTask) Execute(ctx context.Context, store usecaseifs.IMessageStore) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(t.timeoutMillis)*time.Millisecond)
+	defer cancel()
+
+	err := t.task.Execute(timeoutCtx, store)
+	if err != nil && timeoutCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return fmt.Errorf("task timed out after %d ms: %w", t.timeoutMillis, err)
+	}
+	return err
+}
+
+func (t *TimeoutTask) SetParameters(m map[string]interface{}) {
+	t.task.SetParameters(m)
+}
+
+func (t *TimeoutTask) Validate() error {
+	if t.timeoutMillis <= 0 {
+		return fmt.Errorf("timeout_millis must be positive")
+	}
+	return t.task.Validate()
+}
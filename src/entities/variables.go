@@ -0,0 +1,11 @@
+package entities
+
+import "strings"
+
+// VariablesAddressPrefix is the reserved store namespace of the variables, only the variable tasks may write it.
+const VariablesAddressPrefix = "/var/"
+
+// IsVariableAddress tells if the address is in the namespace of the variables.
+func IsVariableAddress(address string) bool {
+	return strings.HasPrefix(address, VariablesAddressPrefix)
+}
@@ -5,17 +5,17 @@ go 1.20
 require (
 	github.com/andreykaipov/goobs v0.12.1
 	github.com/google/uuid v1.3.1
-	github.com/hypebeast/go-osc v0.0.0-20220308234300-cec5a8a1e5f5
+	github.com/gorilla/websocket v1.5.0
 	github.com/ilyakaznacheev/cleanenv v1.5.0
 	github.com/loffa/gosc v0.0.0-20230901113444-a138fef9ff88
 	github.com/pkg/errors v0.9.1
 	github.com/scgolang/osc v0.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/BurntSushi/toml v1.3.2 // indirect
 	github.com/buger/jsonparser v1.1.1 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/logutils v1.0.0 // indirect
 	github.com/imdario/go-ulid v0.0.0-20180116185620-aeb52bf96595 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
@@ -23,6 +23,5 @@ require (
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d // indirect
 	github.com/stretchr/testify v1.8.1 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
 )
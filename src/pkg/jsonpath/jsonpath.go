@@ -0,0 +1,93 @@
+// Package jsonpath implements a subset of JSONPath, to select a single value from a decoded JSON document.
+// Supported: the root ($), child keys (.key or ['key']) and array indices ([0]), e.g. $.presets[0].name
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Get returns the value at [path] within [document], that is the result of json.Unmarshal into an interface{}.
+func Get(document interface{}, path string) (interface{}, error) {
+	steps, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+	for _, step := range steps {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[step]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' does not exist", step)
+			}
+			current = value
+
+		case []interface{}:
+			index, err := strconv.Atoi(step)
+			if err != nil {
+				return nil, fmt.Errorf("'%s' is not a valid array index", step)
+			}
+			if index < 0 {
+				index += len(node)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("index %s is out of range (length: %d)", step, len(node))
+			}
+			current = node[index]
+
+		default:
+			return nil, fmt.Errorf("can not select '%s' from a %T", step, current)
+		}
+	}
+	return current, nil
+}
+
+// Validate checks the syntax of [path].
+func Validate(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+// parse splits [path] into the keys and indices to be selected.
+func parse(path string) ([]string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid path '%s': it must start with $", path)
+	}
+
+	steps := []string{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path '%s': empty key", path)
+			}
+			steps = append(steps, rest[:end])
+			rest = rest[end:]
+
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path '%s': missing ]", path)
+			}
+			step := strings.Trim(rest[1:end], `'"`)
+			if step == "" {
+				return nil, fmt.Errorf("invalid path '%s': empty selector", path)
+			}
+			steps = append(steps, step)
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("invalid path '%s': unexpected '%c'", path, rest[0])
+		}
+	}
+	return steps, nil
+}
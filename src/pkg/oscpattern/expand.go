@@ -0,0 +1,93 @@
+package oscpattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxExpandedAddresses is the maximum number of addresses a template may expand to.
+const MaxExpandedAddresses = 10000
+
+// Expand returns the addresses of an address template, in which the {foo,bar} lists and the {01..32} ranges are
+// expanded, e.g. "/ch/{01..03}/mix/{on,fader}" -> /ch/01/mix/on, /ch/01/mix/fader, /ch/02/mix/on, ... /ch/03/mix/fader.
+// The numbers of a range are zero padded to the length of its first number, if that starts with a zero.
+func Expand(template string) ([]string, error) {
+	start := strings.IndexByte(template, '{')
+	if start == -1 {
+		if strings.ContainsAny(template, specialCharacters) {
+			return nil, fmt.Errorf("unexpected wildcard in template: %s", template)
+		}
+		return []string{template}, nil
+	}
+
+	end := strings.IndexByte(template[start:], '}')
+	if end == -1 {
+		return nil, fmt.Errorf("unclosed brace in template: %s", template)
+	}
+	end += start
+
+	alternatives, err := expandBraces(template[start+1 : end])
+	if err != nil {
+		return nil, fmt.Errorf("invalid braces in template %s: %w", template, err)
+	}
+
+	rests, err := Expand(template[end+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alternatives)*len(rests) > MaxExpandedAddresses {
+		return nil, fmt.Errorf("the template %s expands to more than %d addresses", template, MaxExpandedAddresses)
+	}
+
+	prefix := template[:start]
+	if strings.ContainsAny(prefix, specialCharacters) {
+		return nil, fmt.Errorf("unexpected wildcard in template: %s", template)
+	}
+
+	addresses := make([]string, 0, len(alternatives)*len(rests))
+	for _, a := range alternatives {
+		for _, r := range rests {
+			addresses = append(addresses, prefix+a+r)
+		}
+	}
+	return addresses, nil
+}
+
+// expandBraces returns the alternatives of the contents of a brace, a comma separated list or a range (01..32).
+func expandBraces(content string) ([]string, error) {
+	from, to, isRange := strings.Cut(content, "..")
+	if !isRange {
+		if strings.ContainsAny(content, specialCharacters) {
+			return nil, fmt.Errorf("unexpected wildcard in list: '%s'", content)
+		}
+		return strings.Split(content, ","), nil
+	}
+
+	first, err := strconv.Atoi(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range start: '%s'", from)
+	}
+	last, err := strconv.Atoi(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range end: '%s'", to)
+	}
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("invalid range: %d..%d", first, last)
+	}
+	if last-first >= MaxExpandedAddresses {
+		return nil, fmt.Errorf("the range %d..%d is too long", first, last)
+	}
+
+	width := 0
+	if len(from) > 1 && from[0] == '0' {
+		width = len(from)
+	}
+
+	values := make([]string, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		values = append(values, fmt.Sprintf("%0*d", width, i))
+	}
+	return values, nil
+}
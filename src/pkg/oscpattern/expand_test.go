@@ -0,0 +1,42 @@
+package oscpattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+		wantErr  bool
+	}{
+		{template: "/ch/01/mix/on", want: []string{"/ch/01/mix/on"}},
+		{template: "/ch/{01..03}/mix/on", want: []string{"/ch/01/mix/on", "/ch/02/mix/on", "/ch/03/mix/on"}},
+		{template: "/ch/{08..10}", want: []string{"/ch/08", "/ch/09", "/ch/10"}},
+		{template: "/ch/{9..11}", want: []string{"/ch/9", "/ch/10", "/ch/11"}},
+		{template: "/ch/{0..1}", want: []string{"/ch/0", "/ch/1"}},
+		{template: "/ch/{01..02}/mix/{on,fader}", want: []string{"/ch/01/mix/on", "/ch/01/mix/fader", "/ch/02/mix/on", "/ch/02/mix/fader"}},
+		{template: "/ch/{01,02}{a,b}", want: []string{"/ch/01a", "/ch/01b", "/ch/02a", "/ch/02b"}},
+		{template: "/ch/*/mix/on", wantErr: true},
+		{template: "/ch/{01,02}/mix/?", wantErr: true},
+		{template: "/ch/{01,0*}/mix/on", wantErr: true},
+		{template: "/ch/{01..03/mix/on", wantErr: true},
+		{template: "/ch/{03..01}", wantErr: true},
+		{template: "/ch/{a..3}", wantErr: true},
+		{template: "/ch/{1..b}", wantErr: true},
+		{template: "/ch/{0..10000}", wantErr: true},
+		{template: "/ch/{0..99}/{0..99}/{0..1}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Expand(tt.template)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Expand(%q) error = %v, wantErr %t", tt.template, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expand(%q) = %v, want %v", tt.template, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package oscpattern implements the address pattern matching of the OSC 1.0 specification.
+//
+// The supported wildcards within a single address part (between two slashes) are:
+//
+//	?        matches any single character
+//	*        matches any sequence of zero or more characters
+//	[abc]    matches any character in the list, ranges (a-z) are allowed, [!abc] negates
+//	{foo,ba} matches any of the comma separated strings
+package oscpattern
+
+import (
+	"fmt"
+	"strings"
+)
+
+const specialCharacters = "?*[]{}"
+
+// Split cuts an address or a pattern to its parts, e.g. "/ch/01/mix" -> ["", "ch", "01", "mix"].
+func Split(address string) []string {
+	return strings.Split(address, "/")
+}
+
+// IsLiteral tells if the given pattern (or pattern part) contains no wildcards at all.
+func IsLiteral(pattern string) bool {
+	return !strings.ContainsAny(pattern, specialCharacters)
+}
+
+// Validate checks if the brackets and braces are properly closed in the pattern.
+func Validate(pattern string) error {
+	for i, part := range Split(pattern) {
+		inBracket, inBrace := false, false
+		for _, r := range part {
+			switch r {
+			case '[':
+				if inBracket || inBrace {
+					return fmt.Errorf("part %d (%s) of '%s' has a nested '['", i, part, pattern)
+				}
+				inBracket = true
+			case ']':
+				if !inBracket {
+					return fmt.Errorf("part %d (%s) of '%s' has an unopened ']'", i, part, pattern)
+				}
+				inBracket = false
+			case '{':
+				if inBracket || inBrace {
+					return fmt.Errorf("part %d (%s) of '%s' has a nested '{'", i, part, pattern)
+				}
+				inBrace = true
+			case '}':
+				if !inBrace {
+					return fmt.Errorf("part %d (%s) of '%s' has an unopened '}'", i, part, pattern)
+				}
+				inBrace = false
+			}
+		}
+		if inBracket || inBrace {
+			return fmt.Errorf("part %d (%s) of '%s' is not closed", i, part, pattern)
+		}
+	}
+	return nil
+}
+
+// Match tells if the full [address] matches the [pattern]. Wildcards never match a '/'.
+func Match(pattern string, address string) bool {
+	patternParts := Split(pattern)
+	addressParts := Split(address)
+
+	if len(patternParts) != len(addressParts) {
+		return false
+	}
+
+	for i := range patternParts {
+		if !MatchPart(patternParts[i], addressParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchPart matches a single address part against a single pattern part.
+func MatchPart(pattern string, part string) bool {
+	if IsLiteral(pattern) {
+		return pattern == part
+	}
+	return matchRunes([]rune(pattern), []rune(part))
+}
+
+// matchRunes does the grunt work for MatchPart.
+// nolint:cyclop
+func matchRunes(p []rune, s []rune) bool {
+	for len(p) > 0 {
+		switch p[0] {
+		case '*':
+			for len(p) > 0 && p[0] == '*' {
+				p = p[1:]
+			}
+			if len(p) == 0 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchRunes(p, s[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			p, s = p[1:], s[1:]
+
+		case '[':
+			end := indexOf(p, ']')
+			if end == -1 || len(s) == 0 {
+				return false
+			}
+			if !matchCharacterClass(p[1:end], s[0]) {
+				return false
+			}
+			p, s = p[end+1:], s[1:]
+
+		case '{':
+			end := indexOf(p, '}')
+			if end == -1 {
+				return false
+			}
+			rest := p[end+1:]
+			for _, alternative := range strings.Split(string(p[1:end]), ",") {
+				alt := []rune(alternative)
+				if hasPrefix(s, alt) && matchRunes(rest, s[len(alt):]) {
+					return true
+				}
+			}
+			return false
+
+		default:
+			if len(s) == 0 || s[0] != p[0] {
+				return false
+			}
+			p, s = p[1:], s[1:]
+		}
+	}
+
+	return len(s) == 0
+}
+
+// matchCharacterClass matches a single character against the inside of a [...] expression.
+func matchCharacterClass(class []rune, c rune) bool {
+	negate := false
+	if len(class) > 0 && class[0] == '!' {
+		negate = true
+		class = class[1:]
+	}
+
+	matched := false
+	for i := 0; i < len(class); i++ {
+		// A range, e.g. a-z. A '-' at the end is just a literal.
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] <= c && c <= class[i+2] {
+				matched = true
+			}
+			i += 2
+			continue
+		}
+		if class[i] == c {
+			matched = true
+		}
+	}
+
+	return matched != negate
+}
+
+func indexOf(s []rune, r rune) int {
+	for i, c := range s {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasPrefix(s []rune, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
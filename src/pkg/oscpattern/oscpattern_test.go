@@ -0,0 +1,68 @@
+package oscpattern
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		address string
+		want    bool
+	}{
+		{pattern: "/ch/01/mix/on", address: "/ch/01/mix/on", want: true},
+		{pattern: "/ch/01/mix/on", address: "/ch/02/mix/on", want: false},
+		{pattern: "/ch/*/mix/on", address: "/ch/01/mix/on", want: true},
+		{pattern: "/ch/*/mix/on", address: "/ch//mix/on", want: true},
+		{pattern: "/ch/*", address: "/ch/01/mix/on", want: false},
+		{pattern: "/ch/0*1", address: "/ch/0001", want: true},
+		{pattern: "/ch/0*1", address: "/ch/0010", want: false},
+		{pattern: "/ch/**", address: "/ch/01", want: true},
+		{pattern: "/ch/0?", address: "/ch/05", want: true},
+		{pattern: "/ch/0?", address: "/ch/0", want: false},
+		{pattern: "/ch/0?", address: "/ch/005", want: false},
+		{pattern: "/ch/[0-1][1-3]", address: "/ch/13", want: true},
+		{pattern: "/ch/[0-1][1-3]", address: "/ch/14", want: false},
+		{pattern: "/ch/[135]", address: "/ch/3", want: true},
+		{pattern: "/ch/[135]", address: "/ch/2", want: false},
+		{pattern: "/ch/[!a-z]", address: "/ch/1", want: true},
+		{pattern: "/ch/[!a-z]", address: "/ch/b", want: false},
+		{pattern: "/ch/[!a-z]", address: "/ch/", want: false},
+		{pattern: "/ch/[a-]", address: "/ch/-", want: true},
+		{pattern: "/ch/{01,02}/mix/on", address: "/ch/02/mix/on", want: true},
+		{pattern: "/ch/{01,02}/mix/on", address: "/ch/03/mix/on", want: false},
+		{pattern: "/ch/01/mix/{on,fader}", address: "/ch/01/mix/fader", want: true},
+		{pattern: "/ch/{0,01}1", address: "/ch/011", want: true},
+		{pattern: "/ch/x{,y}", address: "/ch/x", want: true},
+		{pattern: "/ch/{01,02}", address: "/ch/01/mix", want: false},
+		{pattern: "/*/{01,02}/?ix/[a-z]*", address: "/bus/02/mix/fader", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.address); got != tt.want {
+			t.Errorf("Match(%q, %q) = %t, want %t", tt.pattern, tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "/ch/01/mix/on"},
+		{pattern: "/ch/*/mix/[a-z]?"},
+		{pattern: "/ch/{01,02}/mix/[!a]"},
+		{pattern: "/ch/[01", wantErr: true},
+		{pattern: "/ch/01]", wantErr: true},
+		{pattern: "/ch/{01,02", wantErr: true},
+		{pattern: "/ch/01}", wantErr: true},
+		{pattern: "/ch/{01,[02]}", wantErr: true},
+		{pattern: "/ch/[0[1]]", wantErr: true},
+		{pattern: "/ch/{01/02}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := Validate(tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %t", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package oscpattern
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WildcardParts returns the indices of the parts of the pattern that contain wildcards.
+func WildcardParts(pattern string) []int {
+	indices := []int{}
+	for i, part := range Split(pattern) {
+		if !IsLiteral(part) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// ValidateTranslation checks if the addresses matching [from] can be translated to [to]: both must be valid patterns,
+// with the same number of parts containing wildcards.
+func ValidateTranslation(from string, to string) error {
+	for _, pattern := range []string{from, to} {
+		if err := Validate(pattern); err != nil {
+			return err
+		}
+	}
+
+	if len(WildcardParts(from)) != len(WildcardParts(to)) {
+		return fmt.Errorf("%s and %s have a different number of parts with wildcards", from, to)
+	}
+	return nil
+}
+
+// Translate converts an [address] matching the [from] pattern to the [to] pattern: the parts of the address matched by
+// wildcards are copied, in their order, into the parts of [to] that contain wildcards, the rest of [to] is kept as is.
+// E.g. /ch/05/mix/on translated from /ch/*/mix/on to /channel/*/mute is /channel/05/mute.
+// It returns false if the address does not match [from], or the result does not match [to].
+func Translate(from string, to string, address string) (string, bool) {
+	if !Match(from, address) {
+		return "", false
+	}
+
+	fromWildcards := WildcardParts(from)
+	toWildcards := WildcardParts(to)
+	if len(fromWildcards) != len(toWildcards) {
+		return "", false
+	}
+
+	addressParts := Split(address)
+	toParts := Split(to)
+	for i, fromIndex := range fromWildcards {
+		toParts[toWildcards[i]] = addressParts[fromIndex]
+	}
+
+	translated := strings.Join(toParts, "/")
+	if !Match(to, translated) {
+		return "", false
+	}
+	return translated, true
+}
@@ -0,0 +1,52 @@
+package oscpattern
+
+import "testing"
+
+func TestValidateTranslation(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{from: "/ch/01/mix/on", to: "/channel/1/mute"},
+		{from: "/ch/*/mix/on", to: "/channel/*/mute"},
+		{from: "/ch/*/mix/{on,fader}", to: "/channel/[0-9][0-9]/*"},
+		{from: "/ch/*/mix/on", to: "/channel/1/mute", wantErr: true},
+		{from: "/ch/*/mix/*", to: "/channel/*/mute", wantErr: true},
+		{from: "/ch/[01/mix/on", to: "/channel/*/mute", wantErr: true},
+		{from: "/ch/*/mix/on", to: "/channel/{1,2/mute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateTranslation(tt.from, tt.to); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTranslation(%q, %q) error = %v, wantErr %t", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		address string
+		want    string
+		wantOk  bool
+	}{
+		{from: "/ch/01/mix/on", to: "/channel/1/mute", address: "/ch/01/mix/on", want: "/channel/1/mute", wantOk: true},
+		{from: "/ch/*/mix/on", to: "/channel/*/mute", address: "/ch/05/mix/on", want: "/channel/05/mute", wantOk: true},
+		{from: "/ch/*/mix/*", to: "/*/strip/*", address: "/ch/05/mix/fader", want: "/05/strip/fader", wantOk: true},
+		{from: "/ch/{01,02}/mix/on", to: "/bus/??/on", address: "/ch/02/mix/on", want: "/bus/02/on", wantOk: true},
+		{from: "/ch/*/mix/on", to: "/channel/*/mute", address: "/bus/05/mix/on", wantOk: false},
+		{from: "/ch/*/mix/on", to: "/channel/*/mute", address: "/ch/05/mix/on/extra", wantOk: false},
+		// The copied part must match the target pattern too.
+		{from: "/ch/*", to: "/bus/0[1-4]", address: "/ch/07", wantOk: false},
+		{from: "/ch/*/mix/*", to: "/channel/*/mute", address: "/ch/05/mix/on", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Translate(tt.from, tt.to, tt.address)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("Translate(%q, %q, %q) = %q, %t, want %q, %t", tt.from, tt.to, tt.address, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package x32 implements the value conversions of the Behringer X32/M32 and X-Air (XR) consoles.
+//
+// The faders (and sends) are controlled with a float between 0 and 1, that maps to -inf..+10 dB in four linear
+// segments:
+//
+//	0.5    - 1      -10 dB - +10 dB
+//	0.25   - 0.5    -30 dB - -10 dB
+//	0.0625 - 0.25   -60 dB - -30 dB
+//	0      - 0.0625 -inf   - -60 dB
+package x32
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinDB is the lowest level of a fader above -inf.
+	MinDB = -90
+	// MaxDB is the level of a fader at its top.
+	MaxDB = 10
+
+	// NodeMinusInf is how the consoles print -inf dB in the node responses, e.g. /ch/01/mix ON -oo ON +0 OFF -oo.
+	NodeMinusInf = "-oo"
+)
+
+// FaderToDB converts a fader float (0..1) to dB, 0 is -inf.
+func FaderToDB(fader float64) float64 {
+	switch {
+	case fader <= 0:
+		return math.Inf(-1)
+	case fader >= 1:
+		return MaxDB
+	case fader >= 0.5:
+		return fader*40 - 30
+	case fader >= 0.25:
+		return fader*80 - 50
+	case fader >= 0.0625:
+		return fader*160 - 70
+	default:
+		return fader*480 - 90
+	}
+}
+
+// DBToFader converts a dB level to a fader float (0..1), the levels out of -90..+10 dB are clamped.
+func DBToFader(db float64) float64 {
+	switch {
+	case db <= MinDB:
+		return 0
+	case db >= MaxDB:
+		return 1
+	case db < -60:
+		return (db + 90) / 480
+	case db < -30:
+		return (db + 70) / 160
+	case db < -10:
+		return (db + 50) / 80
+	default:
+		return (db + 30) / 40
+	}
+}
+
+// ParseDB parses a dB level, e.g. "-10", "+3.5", "-inf", or "-oo" as the consoles print -inf in the node responses.
+func ParseDB(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "-inf") || s == NodeMinusInf {
+		return math.Inf(-1), nil
+	}
+
+	db, err := strconv.ParseFloat(strings.TrimPrefix(s, "+"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dB level: '%s'", s)
+	}
+	return db, nil
+}
+
+// FormatDB formats a dB level with one decimal, as the consoles print it in the node responses, e.g. "-10.0", "-oo".
+func FormatDB(db float64) string {
+	if math.IsInf(db, -1) {
+		return NodeMinusInf
+	}
+	return strconv.FormatFloat(db, 'f', 1, 64)
+}
+
+// RoundDB rounds a dB level to 0.1 dB, the resolution the consoles display.
+func RoundDB(db float64) float64 {
+	if math.IsInf(db, 0) {
+		return db
+	}
+	return math.Round(db*10) / 10
+}
+
+// colors are the channel colors in the order of their OSC values, the inverted variants follow them with an "i" suffix.
+var colors = []string{"OFF", "RD", "GN", "YE", "BL", "MG", "CY", "WH"}
+
+// ColorToInt converts a color name of the node responses (e.g. "YE", "RDi") to its OSC value (0..15).
+func ColorToInt(name string) (int, error) {
+	for i, c := range colors {
+		switch name {
+		case c:
+			return i, nil
+		case c + "i":
+			return i + len(colors), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid color: '%s'", name)
+}
+
+// ColorName converts an OSC color value (0..15) to its name, e.g. 3 -> "YE", 9 -> "RDi".
+func ColorName(color int) string {
+	if color < 0 || color >= 2*len(colors) {
+		return ""
+	}
+	if color >= len(colors) {
+		return colors[color-len(colors)] + "i"
+	}
+	return colors[color]
+}
@@ -0,0 +1,47 @@
+package x32
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseDB(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{in: "-10", want: -10},
+		{in: "+3.5", want: 3.5},
+		{in: " 0 ", want: 0},
+		{in: "-inf", want: math.Inf(-1)},
+		{in: "-INF", want: math.Inf(-1)},
+		{in: "-oo", want: math.Inf(-1)},
+		{in: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDB(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDB(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseDB(%q) = %f, want %f", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDBRoundTrip(t *testing.T) {
+	for _, db := range []float64{math.Inf(-1), -90, -10.5, 0, 10} {
+		s := FormatDB(db)
+		got, err := ParseDB(s)
+		if err != nil || got != db {
+			t.Errorf("ParseDB(FormatDB(%f) = %q) = %f, %v", db, s, got, err)
+		}
+	}
+
+	if s := FormatDB(FaderToDB(0)); s != "-oo" {
+		t.Errorf("FormatDB of a pulled down fader = %q, want -oo", s)
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// initActionStates publishes the enabled state of every action at start, unless there is a valid persisted one.
+func (e *oscMessageStoreManager) initActionStates(ctx context.Context) {
+	for _, action := range e.actions {
+		address := entities.GetBridgeActionAddress(action.GetName(), entities.BridgeActionEnabled)
+
+		if record, ok := e.store.GetRecord(address, false); ok {
+			if enabled, err := parseEnabledMessage(record.GetMessage()); err == nil {
+				if !enabled {
+					e.log.Infof(ctx, "Action %s is disabled (persisted)", action.GetName())
+				}
+				continue
+			}
+		}
+
+		e.updateRecord(ctx, newEnabledMessage(action.GetName(), true))
+	}
+}
+
+// handleActionControlMessage processes the messages of the /bridge/actions/<name>/ namespace.
+// It returns the message to be stored (normalized), or nil if it must not be stored.
+func (e *oscMessageStoreManager) handleActionControlMessage(ctx context.Context, msg usecaseifs.IOSCMessage) usecaseifs.IOSCMessage {
+	name, suffix, ok := entities.ParseBridgeActionAddress(msg.GetAddress())
+	if !ok {
+		return msg
+	}
+
+	switch suffix {
+	case entities.BridgeActionEnabled:
+		if _, ok := e.getAction(name); !ok {
+			e.log.Warnf(ctx, "Ignoring %v: there is no action named '%s'", msg, name)
+			return nil
+		}
+
+		enabled, err := parseEnabledMessage(msg)
+		if err != nil {
+			e.log.Warnf(ctx, "Ignoring %v: %s", msg, err)
+			return nil
+		}
+		return newEnabledMessage(name, enabled)
+
+	case entities.BridgeActionTrigger:
+		if err := e.triggerAction(ctx, name); err != nil {
+			e.log.Warnf(ctx, "Ignoring %v: %s", msg, err)
+		}
+		return nil
+
+	default:
+		return msg
+	}
+}
+
+// isEnabled tells if [action] was enabled when [store] was taken. Actions without a stored state are enabled.
+func (e *oscMessageStoreManager) isEnabled(action usecaseifs.IAction, store usecaseifs.IMessageStore) bool {
+	record, ok := store.GetRecord(entities.GetBridgeActionAddress(action.GetName(), entities.BridgeActionEnabled), false)
+	if !ok {
+		return true
+	}
+
+	enabled, err := parseEnabledMessage(record.GetMessage())
+	return err != nil || enabled
+}
+
+// setActionEnabled updates the enabled state of the named action with the result of [update].
+func (e *oscMessageStoreManager) setActionEnabled(ctx context.Context, name string, update func(enabled bool) bool) (bool, error) {
+	action, ok := e.getAction(name)
+	if !ok {
+		return false, fmt.Errorf("there is no action named '%s'", name)
+	}
+
+	var result bool
+	err := e.updateRecordWith(ctx, entities.GetBridgeActionAddress(name, entities.BridgeActionEnabled), func(_ usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		result = update(e.isEnabled(action, e.store))
+		return newEnabledMessage(name, result), nil
+	})
+	return result, err
+}
+
+// triggerAction executes the named action in the background, regardless of its trigger chain, mode and rate limits.
+// The concurrency policy and the exclusive group still apply, and disabled actions can not be triggered.
+func (e *oscMessageStoreManager) triggerAction(ctx context.Context, name string) error {
+	action, ok := e.getAction(name)
+	if !ok {
+		return fmt.Errorf("there is no action named '%s'", name)
+	}
+
+	if !e.isEnabled(action, e.store) {
+		return fmt.Errorf("action %s is disabled", name)
+	}
+
+	if !e.startEvaluation() {
+		return fmt.Errorf("action %s was not triggered, the bridge is shutting down", name)
+	}
+
+	e.log.Infof(ctx, "Action %s was triggered.", name)
+	currentStore := e.store.Clone()
+
+	go func() {
+		defer e.evaluations.Done()
+
+		ctx := getTaskExecutionSessionContext(e.execCtx)
+		e.runners[name].run(ctx, func(ctx context.Context) {
+			e.executeAction(ctx, action, currentStore)
+		})
+	}()
+	return nil
+}
+
+func (e *oscMessageStoreManager) getAction(name string) (usecaseifs.IAction, bool) {
+	for _, action := range e.actions {
+		if action.GetName() == name {
+			return action, true
+		}
+	}
+	return nil, false
+}
+
+// parseEnabledMessage accepts a single argument, either a boolean ("true", "false") or a number (non-zero is enabled).
+func parseEnabledMessage(msg usecaseifs.IOSCMessage) (bool, error) {
+	args := msg.GetArguments()
+	if len(args) != 1 {
+		return false, fmt.Errorf("the enabled state must be a single argument, got %d", len(args))
+	}
+
+	if enabled, err := strconv.ParseBool(args[0].GetValue()); err == nil {
+		return enabled, nil
+	}
+
+	value, err := strconv.ParseFloat(args[0].GetValue(), 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid enabled state: '%s', it must be a number or a boolean", args[0].GetValue())
+	}
+	return value != 0, nil
+}
+
+func newEnabledMessage(actionName string, enabled bool) usecaseifs.IOSCMessage {
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+
+	return osc_message.NewMessage(
+		entities.GetBridgeActionAddress(actionName, entities.BridgeActionEnabled),
+		[]usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("int32", value)},
+	)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// rateLimitWindow is the window of the max executions per minute limit.
+const rateLimitWindow = time.Minute
+
+// actionRateLimiter enforces the cooldown and the max executions per minute of a single action.
+type actionRateLimiter struct {
+	cooldown     time.Duration
+	maxPerMinute int
+	trailing     bool
+
+	m *sync.Mutex
+
+	lastExecution time.Time
+	// window holds the start of the executions within the last minute, oldest first.
+	window []time.Time
+
+	// suppressed counts the executions dropped by the limits.
+	suppressed int64
+
+	// pending is the snapshot of the latest suppressed trigger, to be executed when the limits allow, if trailing is enabled.
+	pending usecaseifs.IMessageStore
+}
+
+func newActionRateLimiter(action usecaseifs.IAction) *actionRateLimiter {
+	return &actionRateLimiter{
+		cooldown:     time.Duration(action.GetCooldownMillis()) * time.Millisecond,
+		maxPerMinute: action.GetMaxExecutionsPerMinute(),
+		trailing:     action.ShouldRunTrailing(),
+		m:            &sync.Mutex{},
+	}
+}
+
+// take registers an execution at [now] if the limits allow it. Otherwise, it returns the earliest time an execution is allowed.
+// A permitted execution supersedes the pending trailing one, as it runs with a newer state anyway.
+func (l *actionRateLimiter) take(now time.Time) (bool, time.Time) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	allowed, next := l.takeLocked(now)
+	if allowed {
+		l.pending = nil
+	}
+	return allowed, next
+}
+
+// suppress records a dropped execution. If trailing is enabled, [store] is kept for the trailing execution,
+// the second return value tells if the trailing execution has to be scheduled (it was not pending yet).
+func (l *actionRateLimiter) suppress(store usecaseifs.IMessageStore) (int64, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.suppressed++
+	if !l.trailing {
+		return l.suppressed, false
+	}
+
+	schedule := l.pending == nil
+	l.pending = store
+	return l.suppressed, schedule
+}
+
+// takeTrailing returns the pending snapshot if the limits allow its execution at [now].
+// If they don't, it returns the time to try again, if there is nothing pending anymore, both return values are zero.
+func (l *actionRateLimiter) takeTrailing(now time.Time) (usecaseifs.IMessageStore, time.Time) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.pending == nil {
+		return nil, time.Time{}
+	}
+
+	allowed, next := l.takeLocked(now)
+	if !allowed {
+		return nil, next
+	}
+
+	store := l.pending
+	l.pending = nil
+	return store, time.Time{}
+}
+
+// takeLocked does the grunt work for take & takeTrailing. Must be called with the lock held.
+func (l *actionRateLimiter) takeLocked(now time.Time) (bool, time.Time) {
+	for len(l.window) > 0 && now.Sub(l.window[0]) >= rateLimitWindow {
+		l.window = l.window[1:]
+	}
+
+	next := now
+	if l.cooldown > 0 && !l.lastExecution.IsZero() && l.lastExecution.Add(l.cooldown).After(next) {
+		next = l.lastExecution.Add(l.cooldown)
+	}
+	if l.maxPerMinute > 0 && len(l.window) >= l.maxPerMinute && l.window[0].Add(rateLimitWindow).After(next) {
+		next = l.window[0].Add(rateLimitWindow)
+	}
+
+	if next.After(now) {
+		return false, next
+	}
+
+	l.lastExecution = now
+	if l.maxPerMinute > 0 {
+		l.window = append(l.window, now)
+	}
+	return true, time.Time{}
+}
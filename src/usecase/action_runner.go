@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// actionRunner guards the executions of a single action according to its concurrency policy.
+type actionRunner struct {
+	action usecaseifs.IAction
+	log    usecaseifs.ILogger
+
+	m    *sync.Mutex
+	cond *sync.Cond
+
+	// running is the number of the currently running executions.
+	running int
+
+	// nextTicket and servingTicket implement a FIFO for the queue policy, waiting is the number of queued executions.
+	nextTicket    uint64
+	servingTicket uint64
+	waiting       int
+
+	// cancelRunning cancels the current execution, runningDone is closed when it returned. Used by the restart policy.
+	cancelRunning context.CancelFunc
+	runningDone   chan interface{}
+}
+
+func newActionRunner(log usecaseifs.ILogger, action usecaseifs.IAction) *actionRunner {
+	m := &sync.Mutex{}
+	return &actionRunner{
+		action: action,
+		log:    log,
+		m:      m,
+		cond:   sync.NewCond(m),
+	}
+}
+
+// run calls [execute] if the concurrency policy of the action allows, it blocks until the execution is finished.
+func (r *actionRunner) run(ctx context.Context, execute func(ctx context.Context)) {
+	switch r.action.GetConcurrency() {
+	case entities.ConcurrencyDropIfRunning:
+		r.runOrDrop(ctx, execute)
+	case entities.ConcurrencyQueue:
+		r.runQueued(ctx, execute)
+	case entities.ConcurrencyRestart:
+		r.runRestarting(ctx, execute)
+	default:
+		r.runParallel(ctx, execute)
+	}
+}
+
+func (r *actionRunner) runParallel(ctx context.Context, execute func(ctx context.Context)) {
+	r.m.Lock()
+	r.running++
+	r.m.Unlock()
+
+	r.execute(ctx, execute)
+}
+
+func (r *actionRunner) runOrDrop(ctx context.Context, execute func(ctx context.Context)) {
+	if !r.tryStart() {
+		r.log.Infof(ctx, "Action %s is already running, dropping this execution.", r.action.GetName())
+		return
+	}
+
+	r.execute(ctx, execute)
+}
+
+// tryStart registers an execution, unless one is running already. The check and the registration are atomic,
+// so two executions can not start at the same time.
+func (r *actionRunner) tryStart() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.running > 0 {
+		return false
+	}
+	r.running++
+	return true
+}
+
+// execute calls [execute], and unregisters the execution afterwards.
+func (r *actionRunner) execute(ctx context.Context, execute func(ctx context.Context)) {
+	execute(ctx)
+
+	r.m.Lock()
+	r.running--
+	r.m.Unlock()
+}
+
+func (r *actionRunner) runQueued(ctx context.Context, execute func(ctx context.Context)) {
+	r.m.Lock()
+	if r.running > 0 && r.waiting >= r.action.GetQueueSize() {
+		r.m.Unlock()
+		r.log.Warnf(ctx, "Action %s has %d executions queued already, dropping this execution.", r.action.GetName(), r.waiting)
+		return
+	}
+
+	ticket := r.nextTicket
+	r.nextTicket++
+	r.waiting++
+	for r.servingTicket != ticket {
+		r.cond.Wait()
+	}
+	r.waiting--
+	r.running++
+	r.m.Unlock()
+
+	execute(ctx)
+
+	r.m.Lock()
+	r.running--
+	r.servingTicket++
+	r.cond.Broadcast()
+	r.m.Unlock()
+}
+
+func (r *actionRunner) runRestarting(ctx context.Context, execute func(ctx context.Context)) {
+	r.m.Lock()
+	for r.cancelRunning != nil {
+		r.log.Infof(ctx, "Action %s is already running, cancelling it to restart.", r.action.GetName())
+		r.cancelRunning()
+		done := r.runningDone
+		r.m.Unlock()
+		<-done
+		r.m.Lock()
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan interface{})
+	r.cancelRunning = cancel
+	r.runningDone = done
+	r.running++
+	r.m.Unlock()
+
+	execute(runCtx)
+
+	r.m.Lock()
+	r.running--
+	r.cancelRunning = nil
+	r.runningDone = nil
+	r.m.Unlock()
+
+	cancel()
+	close(done)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"net.kopias.oscbridge/app/entities"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// exclusiveGroup makes sure that only one action of the group executes at a time.
+type exclusiveGroup struct {
+	name    string
+	policy  string
+	minHold time.Duration
+	log     usecaseifs.ILogger
+
+	m *sync.Mutex
+
+	// holder is the action that took the group last, heldSince is the time the group got busy with it.
+	holder    usecaseifs.IAction
+	heldSince time.Time
+
+	// executions are the running executions of the holder, by their id.
+	executions      map[uint64]*groupExecution
+	nextExecutionID uint64
+}
+
+// groupExecution is a running execution of the holder of the group.
+type groupExecution struct {
+	cancel context.CancelFunc
+	done   chan interface{}
+}
+
+func newExclusiveGroup(log usecaseifs.ILogger, name string, policy string, minHoldMillis int64) *exclusiveGroup {
+	return &exclusiveGroup{
+		name:       name,
+		policy:     policy,
+		minHold:    time.Duration(minHoldMillis) * time.Millisecond,
+		log:        log,
+		m:          &sync.Mutex{},
+		executions: map[uint64]*groupExecution{},
+	}
+}
+
+// acquire takes the group for [action] if the policy allows, cancelling the running member if needed.
+// It returns the context for the execution and the function that must be called once it is finished.
+// If the group could not be taken, the last return value is false, and the execution must be dropped.
+func (g *exclusiveGroup) acquire(ctx context.Context, action usecaseifs.IAction) (context.Context, func(), bool) {
+	g.m.Lock()
+	for !g.isAvailableFor(action) {
+		if time.Since(g.heldSince) < g.minHold {
+			g.m.Unlock()
+			g.log.Infof(ctx, "Exclusive group %s is held by %s for at least %s, dropping the execution of %s.",
+				g.name, g.holder.GetName(), g.minHold, action.GetName())
+			return nil, nil, false
+		}
+
+		if !g.canTakeOver(action) {
+			g.m.Unlock()
+			g.log.Infof(ctx, "Exclusive group %s is used by %s, dropping the execution of %s.", g.name, g.holder.GetName(), action.GetName())
+			return nil, nil, false
+		}
+
+		g.log.Infof(ctx, "Action %s takes over exclusive group %s, cancelling %s.", action.GetName(), g.name, g.holder.GetName())
+		dones := []chan interface{}{}
+		for _, execution := range g.executions {
+			execution.cancel()
+			dones = append(dones, execution.done)
+		}
+		g.m.Unlock()
+
+		for _, done := range dones {
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return nil, nil, false
+			}
+		}
+
+		// Someone else might have taken the group meanwhile, so everything is checked again.
+		g.m.Lock()
+	}
+
+	// The hold starts over whenever the group gets busy, even if the same action takes it again.
+	if g.holder == nil || g.holder.GetName() != action.GetName() || len(g.executions) == 0 {
+		g.holder = action
+		g.heldSince = time.Now()
+	}
+
+	id := g.nextExecutionID
+	g.nextExecutionID++
+	runCtx, cancel := context.WithCancel(ctx)
+	execution := &groupExecution{cancel: cancel, done: make(chan interface{})}
+	g.executions[id] = execution
+	g.m.Unlock()
+
+	release := func() {
+		g.m.Lock()
+		delete(g.executions, id)
+		g.m.Unlock()
+
+		cancel()
+		close(execution.done)
+	}
+	return runCtx, release, true
+}
+
+// isAvailableFor tells if [action] can execute right away. Must be called with the lock held.
+func (g *exclusiveGroup) isAvailableFor(action usecaseifs.IAction) bool {
+	// The executions of the very same action are governed by its own concurrency policy.
+	if g.holder == nil || g.holder.GetName() == action.GetName() {
+		return true
+	}
+	return len(g.executions) == 0 && time.Since(g.heldSince) >= g.minHold
+}
+
+// canTakeOver tells if [action] may cancel the running holder according to the policy. Must be called with the lock held.
+func (g *exclusiveGroup) canTakeOver(action usecaseifs.IAction) bool {
+	switch g.policy {
+	case entities.ExclusiveHighestPriority:
+		return action.GetPriority() > g.holder.GetPriority()
+	case entities.ExclusiveLatestWins:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/logger"
+)
+
+func TestExclusiveGroupReacquireRestartsHold(t *testing.T) {
+	const minHold = 100 * time.Millisecond
+
+	g := newExclusiveGroup(logger.New(), "cameras", entities.ExclusiveLatestWins, minHold.Milliseconds())
+	a := entities.NewAction("a", nil, nil, entities.ActionOptions{ExclusiveGroup: "cameras"})
+	b := entities.NewAction("b", nil, nil, entities.ActionOptions{ExclusiveGroup: "cameras"})
+	ctx := context.Background()
+
+	_, release, ok := g.acquire(ctx, a)
+	if !ok {
+		t.Fatalf("a could not take the idle group")
+	}
+	release()
+
+	// Long after its first hold, a takes the group again, and its hold starts over.
+	time.Sleep(minHold + 50*time.Millisecond)
+	runCtx, release, ok := g.acquire(ctx, a)
+	if !ok {
+		t.Fatalf("a could not take the idle group again")
+	}
+	go func() {
+		<-runCtx.Done()
+		release()
+	}()
+
+	if _, _, ok := g.acquire(ctx, b); ok {
+		t.Fatalf("b took over within the min hold of the re-acquired group")
+	}
+
+	// Once the hold is over, the latest one wins.
+	time.Sleep(minHold + 50*time.Millisecond)
+	_, releaseB, ok := g.acquire(ctx, b)
+	if !ok {
+		t.Fatalf("b could not take over after the min hold")
+	}
+	defer releaseB()
+
+	if runCtx.Err() == nil {
+		t.Errorf("the execution of a was not cancelled by the takeover")
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/slicetools"
+
+	"net.kopias.oscbridge/app/usecase/usecaseifs"
+)
+
+// initMode publishes the active mode at start: the persisted one if it is still valid, the default one otherwise.
+func (e *oscMessageStoreManager) initMode(ctx context.Context) {
+	if len(e.cfg.GetModes()) == 0 {
+		return
+	}
+
+	if record, ok := e.store.GetRecord(entities.BridgeModeAddress, false); ok {
+		if err := e.validateModeMessage(record.GetMessage()); err == nil {
+			e.log.Infof(ctx, "Active mode: %s (persisted)", record.GetMessage().GetArguments()[0].GetValue())
+			return
+		}
+	}
+
+	e.log.Infof(ctx, "Active mode: %s", e.cfg.GetDefaultMode())
+	e.updateRecord(ctx, newModeMessage(e.cfg.GetDefaultMode()))
+}
+
+// validateModeMessage checks if [msg] holds a single, configured mode name.
+func (e *oscMessageStoreManager) validateModeMessage(msg usecaseifs.IOSCMessage) error {
+	modes := e.cfg.GetModes()
+	if len(modes) == 0 {
+		return fmt.Errorf("there are no modes configured")
+	}
+
+	args := msg.GetArguments()
+	if len(args) != 1 {
+		return fmt.Errorf("the mode must be a single argument, got %d", len(args))
+	}
+	if !slicetools.Contains(modes, args[0].GetValue()) {
+		return fmt.Errorf("invalid mode: '%s', valid values: %s", args[0].GetValue(), strings.Join(modes, ","))
+	}
+	return nil
+}
+
+// getActiveMode returns the mode in the current store, false if there is none.
+func (e *oscMessageStoreManager) getActiveMode() (string, bool) {
+	record, ok := e.store.GetRecord(entities.BridgeModeAddress, false)
+	if !ok || len(record.GetMessage().GetArguments()) == 0 {
+		return "", false
+	}
+	return record.GetMessage().GetArguments()[0].GetValue(), true
+}
+
+// setMode validates and stores the new active mode.
+func (e *oscMessageStoreManager) setMode(ctx context.Context, mode string) error {
+	msg := newModeMessage(mode)
+	if err := e.validateModeMessage(msg); err != nil {
+		return err
+	}
+
+	return e.updateRecordWith(ctx, entities.BridgeModeAddress, func(_ usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error) {
+		return msg, nil
+	})
+}
+
+// isActiveInMode tells if [action] is active in the mode that was active when [store] was taken.
+func (e *oscMessageStoreManager) isActiveInMode(action usecaseifs.IAction, store usecaseifs.IMessageStore) bool {
+	if len(action.GetModes()) == 0 {
+		return true
+	}
+
+	record, ok := store.GetRecord(entities.BridgeModeAddress, false)
+	if !ok || len(record.GetMessage().GetArguments()) == 0 {
+		return false
+	}
+	return slicetools.Contains(action.GetModes(), record.GetMessage().GetArguments()[0].GetValue())
+}
+
+func newModeMessage(mode string) usecaseifs.IOSCMessage {
+	return osc_message.NewMessage(entities.BridgeModeAddress, []usecaseifs.IOSCMessageArgument{osc_message.NewMessageArgument("string", mode)})
+}
@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"net.kopias.oscbridge/app/entities"
+	"net.kopias.oscbridge/app/pkg/oscpattern"
 
 	"net.kopias.oscbridge/app/usecase/usecaseifs"
 )
@@ -18,15 +21,37 @@ type oscListener struct {
 	cfg usecaseifs.IConfiguration
 
 	oscConnections []entities.OscConnectionDetails
+	status         *entities.BridgeStatus
 	quit           chan interface{}
+
+	// stopped is closed when the listening loop returned.
+	stopped chan interface{}
+
+	// watchersM guards the watchers, which receive the matching incoming messages, changed or not.
+	watchersM *sync.Mutex
+	watchers  map[*messageWatcher]bool
 }
 
-func newOscListener(log usecaseifs.ILogger, cfg usecaseifs.IConfiguration, oscConnections []entities.OscConnectionDetails) *oscListener {
+// messageWatcher receives the messages of a connection, with an address matching the pattern.
+type messageWatcher struct {
+	connection string
+	pattern    string
+	messages   chan usecaseifs.IOSCMessage
+}
+
+// watcherBufferSize is the number of messages a watcher can fall behind with, the further messages are dropped.
+const watcherBufferSize = 16
+
+func newOscListener(log usecaseifs.ILogger, cfg usecaseifs.IConfiguration, oscConnections []entities.OscConnectionDetails, status *entities.BridgeStatus) *oscListener {
 	return &oscListener{
 		log:            log,
 		cfg:            cfg,
 		oscConnections: oscConnections,
+		status:         status,
 		quit:           make(chan interface{}, 1),
+		stopped:        make(chan interface{}),
+		watchersM:      &sync.Mutex{},
+		watchers:       map[*messageWatcher]bool{},
 	}
 }
 
@@ -39,31 +64,110 @@ func (e *oscListener) Start(ctx context.Context) error {
 	return nil
 }
 
+// Stop stops the ingestion of messages, it returns after the last message has been processed.
 func (e *oscListener) Stop(ctx context.Context) error {
 	e.quit <- true
+	<-e.stopped
 	return nil
 }
 
+// idleSleep is the pause of the listening loop, after a pass over the connections found no message.
+const idleSleep = 10 * time.Millisecond
+
 func (e *oscListener) listeningLoop(ctx context.Context) {
+	defer close(e.stopped)
+
 	for {
-		//
+		// Each connection gets a message per pass, so a busy one (e.g. the meters of a console) can not starve the rest.
+		received := false
 		for _, cd := range e.oscConnections {
-			// fmt.Println("CHECKING ON ", cd.Name)
 			select {
 			case msg := <-cd.Connection.GetEventChan(ctx):
-				// e.log.Infof(ctx, "Incoming message from: %s: %s", cd.Name, msg.String())
-
-				prefixedMessage := entities.NewPrefixedOSCMessage(cd.Prefix, msg)
-
-				e.ucs.oscMessageStore.updateRecord(ctx, prefixedMessage)
+				received = true
+				e.handleMessage(ctx, cd, msg)
 
 			case <-e.quit:
 				return
 
 			default:
 			}
+		}
+
+		// The loop only pauses when every connection is idle, the waiting messages are drained right away.
+		if !received {
+			select {
+			case <-e.quit:
+				return
+			case <-time.After(idleSleep):
+			}
+		}
+	}
+}
+
+// handleMessage stores an incoming message of the connection, and notifies the watchers.
+func (e *oscListener) handleMessage(ctx context.Context, cd entities.OscConnectionDetails, msg usecaseifs.IOSCMessage) {
+	e.status.RecordConnectionMessage(cd.Name, time.Now())
+
+	prefixedMessage := entities.NewPrefixedOSCMessage(cd.Prefix, msg)
+
+	// The variables are set by the variable tasks only, the sources can not override them.
+	if entities.IsVariableAddress(prefixedMessage.GetAddress()) {
+		e.log.Warnf(ctx, "Ignoring %v from %s: the %s namespace is reserved for the variables", msg, cd.Name, entities.VariablesAddressPrefix)
+		return
+	}
+
+	e.ucs.oscMessageStore.updateRecord(ctx, prefixedMessage)
+
+	// The watchers are notified after the store update, so the message is already in the store by then.
+	e.notifyWatchers(cd.Name, msg)
+}
+
+// watchMessages registers a watcher for the messages of [connection] matching [pattern], until stop is called.
+func (e *oscListener) watchMessages(connection string, pattern string) (<-chan usecaseifs.IOSCMessage, func(), error) {
+	found := false
+	for _, cd := range e.oscConnections {
+		found = found || cd.Name == connection
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("there is no osc connection named '%s'", connection)
+	}
+
+	if err := oscpattern.Validate(pattern); err != nil {
+		return nil, nil, fmt.Errorf("invalid address pattern '%s': %w", pattern, err)
+	}
+
+	w := &messageWatcher{
+		connection: connection,
+		pattern:    pattern,
+		messages:   make(chan usecaseifs.IOSCMessage, watcherBufferSize),
+	}
+
+	e.watchersM.Lock()
+	e.watchers[w] = true
+	e.watchersM.Unlock()
+
+	stop := func() {
+		e.watchersM.Lock()
+		delete(e.watchers, w)
+		e.watchersM.Unlock()
+	}
+	return w.messages, stop, nil
+}
+
+// notifyWatchers passes the message to the matching watchers, without blocking the listening loop.
+func (e *oscListener) notifyWatchers(connection string, msg usecaseifs.IOSCMessage) {
+	e.watchersM.Lock()
+	defer e.watchersM.Unlock()
+
+	for w := range e.watchers {
+		if w.connection != connection || !oscpattern.Match(w.pattern, msg.GetAddress()) {
+			continue
+		}
 
-			time.Sleep(10 * time.Millisecond)
+		select {
+		case w.messages <- msg:
+		default:
+			e.log.Warnf(context.Background(), "Dropping message %v, the watcher of %s is busy.", msg, w.pattern)
 		}
 	}
 }
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"net.kopias.oscbridge/app/drivers/osc_message"
+	"net.kopias.oscbridge/app/entities"
 
 	"net.kopias.oscbridge/app/pkg/filetools"
 
@@ -32,39 +35,98 @@ type oscMessageStoreManager struct {
 	cfg usecaseifs.IConfiguration
 
 	store            usecaseifs.IMessageStore
-	storeVersion     int
+	status           *entities.BridgeStatus
+	storeVersion     *atomic.Int64 // Changed by the listener and the internal writers (e.g. tasks) concurrently.
 	actions          []usecaseifs.IAction
+	runners          map[string]*actionRunner
+	groups           map[string]*exclusiveGroup
+	limiters         map[string]*actionRateLimiter
 	storePersistPath string
-	notify           chan error
-	quit             chan interface{}
+
+	// updateM serializes the read-modify-write updates of updateRecordWith.
+	updateM *sync.Mutex
+
+	// workers limits the number of concurrently executing actions, nil means unlimited.
+	workers chan interface{}
+
+	// execCtx is the parent of every evaluation & execution, cancelExec cancels them all upon shutdown.
+	execCtx    context.Context
+	cancelExec context.CancelFunc
+	// evaluations tracks the running evaluations (and executions) for the shutdown to wait on.
+	evaluations *sync.WaitGroup
+	// stoppingM guards stopping, which is set once the shutdown began, after that no new evaluation is started,
+	// while the store updates are still accepted.
+	stoppingM *sync.Mutex
+	stopping  bool
+
+	notify chan error
+	quit   chan interface{}
 }
 
 func newOscMessageStoreManager(
 	log usecaseifs.ILogger,
 	cfg usecaseifs.IConfiguration,
 	store usecaseifs.IMessageStore,
-	actions []usecaseifs.IAction,
 	storePersistPath string,
+	status *entities.BridgeStatus,
 ) *oscMessageStoreManager {
+	var workers chan interface{}
+	if cfg.GetMaxConcurrentActions() > 0 {
+		workers = make(chan interface{}, cfg.GetMaxConcurrentActions())
+	}
+
 	return &oscMessageStoreManager{
 		log:              log,
 		cfg:              cfg,
-		actions:          actions,
+		actions:          []usecaseifs.IAction{},
+		runners:          map[string]*actionRunner{},
+		groups:           map[string]*exclusiveGroup{},
+		limiters:         map[string]*actionRateLimiter{},
 		store:            store,
-		storeVersion:     0,
+		status:           status,
+		storeVersion:     &atomic.Int64{},
 		storePersistPath: storePersistPath,
+		workers:          workers,
+		updateM:          &sync.Mutex{},
+		evaluations:      &sync.WaitGroup{},
+		stoppingM:        &sync.Mutex{},
 		notify:           make(chan error, 1),
 		quit:             make(chan interface{}, 1),
 	}
 }
 
+// setActions registers the actions to be evaluated upon store changes, it must be called before Start.
+func (e *oscMessageStoreManager) setActions(actions []usecaseifs.IAction) {
+	e.actions = actions
+
+	for _, action := range actions {
+		e.runners[action.GetName()] = newActionRunner(e.log, action)
+
+		if action.GetCooldownMillis() > 0 || action.GetMaxExecutionsPerMinute() > 0 {
+			e.limiters[action.GetName()] = newActionRateLimiter(action)
+		}
+
+		name := action.GetExclusiveGroup()
+		if _, ok := e.groups[name]; name != "" && !ok {
+			policy, minHoldMillis := e.cfg.GetExclusiveGroup(name)
+			e.groups[name] = newExclusiveGroup(e.log, name, policy, minHoldMillis)
+		}
+	}
+}
+
 func (e *oscMessageStoreManager) Start(ctx context.Context) error {
+	e.execCtx, e.cancelExec = context.WithCancel(ctx)
+
 	if e.storePersistPath != "" {
 		if err := e.loadJSONDump(ctx); err != nil {
 			return fmt.Errorf("failed to load persistence file: %w", err)
 		}
 	}
 
+	e.status.SetStoreSize(e.store.GetSize())
+	e.initMode(ctx)
+	e.initActionStates(ctx)
+
 	go e.jsonSync(ctx)
 	return nil
 }
@@ -75,7 +137,7 @@ func (e *oscMessageStoreManager) jsonSync(ctx context.Context) {
 		return
 	}
 
-	lastStoreVersion := e.storeVersion
+	lastStoreVersion := e.storeVersion.Load()
 	for {
 		select {
 		case <-e.quit:
@@ -84,9 +146,10 @@ func (e *oscMessageStoreManager) jsonSync(ctx context.Context) {
 		default:
 		}
 
-		if lastStoreVersion != e.storeVersion {
+		// The version is read before dumping, so the changes made meanwhile are dumped next time.
+		if version := e.storeVersion.Load(); lastStoreVersion != version {
 			e.dumpStoreToJSON(ctx)
-			lastStoreVersion = e.storeVersion
+			lastStoreVersion = version
 		}
 		time.Sleep(1 * time.Second)
 	}
@@ -96,31 +159,97 @@ func (e *oscMessageStoreManager) setUseCases(ucs *UseCases) {
 	e.ucs = ucs
 }
 
+// updateRecord stores the message and evaluates the actions if it changed the store. Once the shutdown began,
+// the messages are still stored (e.g. the results of the actions being drained), so they get persisted,
+// but they do not start new evaluations.
 func (e *oscMessageStoreManager) updateRecord(ctx context.Context, msg usecaseifs.IOSCMessage) {
+	if msg.GetAddress() == entities.BridgeModeAddress {
+		if err := e.validateModeMessage(msg); err != nil {
+			e.log.Warnf(ctx, "Ignoring mode change to %v: %s", msg, err)
+			return
+		}
+	}
+
+	if msg = e.handleActionControlMessage(ctx, msg); msg == nil {
+		return
+	}
+
 	if e.store.SetRecord(msg) {
-		e.storeVersion++
+		e.storeVersion.Add(1)
+		e.status.SetStoreSize(e.store.GetSize())
 
 		e.log.Infof(ctx, "Store updated with: %v", msg)
-		go e.evaluateActions(ctx, msg)
+
+		if !e.startEvaluation() {
+			e.log.Infof(ctx, "Not evaluating the actions, the bridge is shutting down.")
+			return
+		}
+
+		// Cloning is cheap, and taking the snapshot here makes sure the evaluation sees the store as it was after this very change.
+		go e.evaluateActions(e.execCtx, msg, e.store.Clone())
 	}
 }
 
-func (e *oscMessageStoreManager) evaluateActions(ctx context.Context, latestUpdatedMessage usecaseifs.IOSCMessage) {
+// updateRecordWith calls [update] with the current message at [address] (nil if there is none),
+// and stores the message it returns (if not nil). The updates are serialized, so they can safely build on the current value.
+func (e *oscMessageStoreManager) updateRecordWith(ctx context.Context, address string, update func(current usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error)) error {
+	e.updateM.Lock()
+	defer e.updateM.Unlock()
+
+	var current usecaseifs.IOSCMessage
+	if record, ok := e.store.GetRecord(address, false); ok {
+		current = record.GetMessage()
+	}
+
+	msg, err := update(current)
+	if err != nil {
+		return err
+	}
+
+	if msg != nil {
+		e.updateRecord(ctx, msg)
+	}
+	return nil
+}
+
+func (e *oscMessageStoreManager) evaluateActions(ctx context.Context, latestUpdatedMessage usecaseifs.IOSCMessage, currentStore usecaseifs.IMessageStore) {
+	defer e.evaluations.Done()
+
 	ctx = getTaskExecutionSessionContext(ctx)
-	currentStore := e.store.Clone()
 	currentStore.WatchRecordAccess(&latestUpdatedMessage)
 
 	if e.cfg.ShouldDebugOSCConditions() {
 		e.log.Info(ctx, "Evaluating actions because of a change in the osc message store.")
 	}
 	for _, action := range e.actions {
-		e.evaluateAction(ctx, action, currentStore)
+		if e.evaluateAction(ctx, action, currentStore) && action.ShouldStopAfterMatch() {
+			if e.cfg.ShouldDebugOSCConditions() {
+				e.log.Infof(ctx, "Action %s fired with stop_after_match, skipping the rest of the actions.", action.GetName())
+			}
+			break
+		}
 	}
 
 	e.log.Info(ctx, "finished.")
 }
 
-func (e *oscMessageStoreManager) evaluateAction(ctx context.Context, action usecaseifs.IAction, currentStore usecaseifs.IMessageStore) {
+// evaluateAction evaluates the trigger chain of the action and executes it if it matched.
+// Returns true if the action fired.
+func (e *oscMessageStoreManager) evaluateAction(ctx context.Context, action usecaseifs.IAction, currentStore usecaseifs.IMessageStore) bool {
+	if !e.isEnabled(action, currentStore) {
+		if e.cfg.ShouldDebugOSCConditions() {
+			e.log.Infof(ctx, "Skipping action %s, it is disabled.", action.GetName())
+		}
+		return false
+	}
+
+	if !e.isActiveInMode(action, currentStore) {
+		if e.cfg.ShouldDebugOSCConditions() {
+			e.log.Infof(ctx, "Skipping action %s, it is not active in the current mode.", action.GetName())
+		}
+		return false
+	}
+
 	if e.cfg.ShouldDebugOSCConditions() {
 		e.log.Infof(ctx, "Evaluating action: %s", action.GetName())
 	}
@@ -130,16 +259,21 @@ func (e *oscMessageStoreManager) evaluateAction(ctx context.Context, action usec
 	}
 
 	if !matched {
-		return
+		return false
 	}
 
 	if currentStore.GetWatchedRecordAccesses() == 0 {
 		e.log.Infof(ctx, "Although the triggers matched, none of them selected the newly changed record, therefore skipping execution.")
-		return
+		return false
 	}
 
 	if action.GetDebounceMillis() != 0 {
-		time.Sleep(time.Duration(action.GetDebounceMillis()) * time.Millisecond)
+		select {
+		case <-time.After(time.Duration(action.GetDebounceMillis()) * time.Millisecond):
+		case <-ctx.Done():
+			return false
+		}
+
 		matched, err = action.Evaluate(ctx, currentStore)
 		if err != nil {
 			e.log.Err(ctx, fmt.Errorf("error during evaluation of %s: %w", action.GetName(), err))
@@ -150,14 +284,99 @@ func (e *oscMessageStoreManager) evaluateAction(ctx context.Context, action usec
 		}
 
 		if !matched {
+			return false
+		}
+	}
+
+	// A suppressed execution still counts as fired, so stop_after_match is not affected by the rate limits.
+	if limiter, ok := e.limiters[action.GetName()]; ok && !e.allowExecution(ctx, action, limiter, currentStore) {
+		return true
+	}
+
+	e.runners[action.GetName()].run(ctx, func(ctx context.Context) {
+		e.executeAction(ctx, action, currentStore)
+	})
+	return true
+}
+
+// allowExecution checks the rate limits of the action, and schedules the trailing execution if the execution is suppressed.
+func (e *oscMessageStoreManager) allowExecution(ctx context.Context, action usecaseifs.IAction, limiter *actionRateLimiter, currentStore usecaseifs.IMessageStore) bool {
+	allowed, retryAt := limiter.take(time.Now())
+	if allowed {
+		return true
+	}
+
+	suppressed, scheduleTrailing := limiter.suppress(currentStore)
+	e.status.RecordActionSuppressed(action.GetName(), suppressed)
+	e.log.Infof(ctx, "Action %s is rate limited until %s, suppressed executions so far: %d.",
+		action.GetName(), retryAt.Format("15:04:05.000"), suppressed)
+
+	if scheduleTrailing && e.startEvaluation() {
+		go e.runTrailing(action, limiter, retryAt)
+	}
+	return false
+}
+
+// runTrailing waits until the rate limits allow, then executes the action with the latest suppressed state.
+func (e *oscMessageStoreManager) runTrailing(action usecaseifs.IAction, limiter *actionRateLimiter, retryAt time.Time) {
+	defer e.evaluations.Done()
+
+	ctx := getTaskExecutionSessionContext(e.execCtx)
+	for {
+		select {
+		case <-time.After(time.Until(retryAt)):
+		case <-ctx.Done():
+			return
+		}
+
+		var store usecaseifs.IMessageStore
+		store, retryAt = limiter.takeTrailing(time.Now())
+		if store != nil && !e.isEnabled(action, e.store) {
+			e.log.Infof(ctx, "Skipping the trailing execution of %s, it has been disabled.", action.GetName())
+			return
+		}
+		if store != nil {
+			e.log.Infof(ctx, "Running the trailing execution of %s.", action.GetName())
+			e.runners[action.GetName()].run(ctx, func(ctx context.Context) {
+				e.executeAction(ctx, action, store)
+			})
+			return
+		}
+
+		// A regular execution took place meanwhile, that superseded the trailing one.
+		if retryAt.IsZero() {
+			return
+		}
+	}
+}
+
+// executeAction runs the tasks of the action, once its exclusive group (if any) is taken, and a worker is available.
+func (e *oscMessageStoreManager) executeAction(ctx context.Context, action usecaseifs.IAction, currentStore usecaseifs.IMessageStore) {
+	if group, ok := e.groups[action.GetExclusiveGroup()]; ok {
+		groupCtx, release, acquired := group.acquire(ctx, action)
+		if !acquired {
+			return
+		}
+		defer release()
+		ctx = groupCtx
+	}
+
+	if e.workers != nil {
+		select {
+		case e.workers <- true:
+			defer func() { <-e.workers }()
+		case <-ctx.Done():
+			e.log.Infof(ctx, "Action %s was cancelled while waiting for a free worker.", action.GetName())
 			return
 		}
 	}
 
 	e.log.Infof(ctx, "Executing action: %s", action.GetName())
-	if err := action.Execute(ctx, currentStore); err != nil {
+	err := action.Execute(ctx, currentStore)
+	if err != nil {
 		e.log.Err(ctx, err)
 	}
+	e.status.RecordActionRun(action.GetName(), time.Now(), err)
 }
 
 // Notify returns the notification channel that can be used to listen for the client's exit
@@ -165,8 +384,61 @@ func (e *oscMessageStoreManager) Notify() <-chan error {
 	return e.notify
 }
 
+// Stop waits for the running actions to finish in the configured grace period, then cancels the rest,
+// and finally flushes the store to the persistence file.
 func (e *oscMessageStoreManager) Stop(ctx context.Context) {
 	e.quit <- true
+
+	e.stoppingM.Lock()
+	e.stopping = true
+	e.stoppingM.Unlock()
+
+	grace := time.Duration(e.cfg.GetShutdownGraceMillis()) * time.Millisecond
+	e.log.Infof(ctx, "Waiting for the running actions to finish (at most %s)...", grace)
+
+	if !e.waitForEvaluations(grace) {
+		e.log.Warn(ctx, "Cancelling the actions that are still running.")
+		e.cancelExec()
+
+		// Tasks honor the cancellation, this is just a safety net not to hang forever on a misbehaving one.
+		if !e.waitForEvaluations(grace) {
+			e.log.Warn(ctx, "Some actions did not stop after the cancellation, giving up on them.")
+		}
+	}
+	e.cancelExec()
+
+	if e.storePersistPath != "" {
+		e.dumpStoreToJSON(ctx)
+	}
+}
+
+// startEvaluation registers an evaluation (or execution) for the shutdown to wait on.
+// It returns false once the shutdown began, then the evaluation must not be started.
+func (e *oscMessageStoreManager) startEvaluation() bool {
+	e.stoppingM.Lock()
+	defer e.stoppingM.Unlock()
+
+	if e.stopping {
+		return false
+	}
+	e.evaluations.Add(1)
+	return true
+}
+
+// waitForEvaluations waits for the evaluations to finish, returns false if they did not in [timeout].
+func (e *oscMessageStoreManager) waitForEvaluations(timeout time.Duration) bool {
+	done := make(chan interface{})
+	go func() {
+		e.evaluations.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
 func (e *oscMessageStoreManager) dumpStoreToJSON(ctx context.Context) {
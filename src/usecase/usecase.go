@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"net.kopias.oscbridge/app/entities"
 
@@ -15,7 +16,13 @@ type (
 	}
 )
 
-var _ usecaseifs.IUseCases = UseCases{}
+var (
+	_ usecaseifs.IUseCases         = UseCases{}
+	_ usecaseifs.IRecordUpdater    = UseCases{}
+	_ usecaseifs.IActionController = UseCases{}
+	_ usecaseifs.IStoreReader      = UseCases{}
+	_ usecaseifs.IMessageWatcher   = UseCases{}
+)
 
 // UseCases	are the root to all the usecase groups in the system.
 type UseCases struct {
@@ -32,12 +39,12 @@ func New(
 	cfg usecaseifs.IConfiguration,
 	oscConnections []entities.OscConnectionDetails,
 	store usecaseifs.IMessageStore,
-	actions []usecaseifs.IAction,
 	storePersistPath string,
+	status *entities.BridgeStatus,
 ) *UseCases {
 	ucs := &UseCases{
-		oscMessageStore: newOscMessageStoreManager(log, cfg, store, actions, storePersistPath),
-		oscListener:     newOscListener(log, cfg, oscConnections),
+		oscMessageStore: newOscMessageStoreManager(log, cfg, store, storePersistPath, status),
+		oscListener:     newOscListener(log, cfg, oscConnections, status),
 
 		notify: make(chan error, 1),
 		quit:   make(chan interface{}, 1),
@@ -51,6 +58,11 @@ func New(
 	return ucs
 }
 
+// SetActions registers the actions. It is separate from New, as the tasks of the actions might depend on the use cases.
+func (u UseCases) SetActions(actions []usecaseifs.IAction) {
+	u.oscMessageStore.setActions(actions)
+}
+
 func (u UseCases) Start(ctx context.Context) error {
 	if err := u.oscMessageStore.Start(ctx); err != nil {
 		return err
@@ -59,16 +71,70 @@ func (u UseCases) Start(ctx context.Context) error {
 	return u.oscListener.Start(ctx)
 }
 
+// Stop shuts down gracefully: first the ingestion of new messages stops, then the running actions are drained.
 func (u UseCases) Stop(ctx context.Context) {
-	u.oscMessageStore.Stop(ctx)
-
 	if err := u.oscListener.Stop(ctx); err != nil {
 		u.log.Err(ctx, err)
 	}
 
+	u.oscMessageStore.Stop(ctx)
+
 	u.quit <- true
 }
 
 func (u UseCases) Notify() <-chan error {
 	return u.oscMessageStore.Notify()
 }
+
+// UpdateRecord updates a record in the message store, based on its current value.
+func (u UseCases) UpdateRecord(ctx context.Context, address string, update func(current usecaseifs.IOSCMessage) (usecaseifs.IOSCMessage, error)) error {
+	return u.oscMessageStore.updateRecordWith(ctx, address, update)
+}
+
+func (u UseCases) GetActionNames() []string {
+	names := []string{}
+	for _, action := range u.oscMessageStore.actions {
+		names = append(names, action.GetName())
+	}
+	return names
+}
+
+func (u UseCases) IsActionEnabled(name string) (bool, error) {
+	action, ok := u.oscMessageStore.getAction(name)
+	if !ok {
+		return false, fmt.Errorf("there is no action named '%s'", name)
+	}
+	return u.oscMessageStore.isEnabled(action, u.oscMessageStore.store), nil
+}
+
+// SetActionEnabled updates the enabled state of the action, which is stored at /bridge/actions/<name>/enabled.
+func (u UseCases) SetActionEnabled(ctx context.Context, name string, update func(enabled bool) bool) (bool, error) {
+	return u.oscMessageStore.setActionEnabled(ctx, name, update)
+}
+
+func (u UseCases) TriggerAction(ctx context.Context, name string) error {
+	return u.oscMessageStore.triggerAction(ctx, name)
+}
+
+func (u UseCases) GetModes() []string {
+	return u.oscMessageStore.cfg.GetModes()
+}
+
+func (u UseCases) GetActiveMode() (string, bool) {
+	return u.oscMessageStore.getActiveMode()
+}
+
+// SetMode switches the active mode, which is stored at /bridge/mode.
+func (u UseCases) SetMode(ctx context.Context, mode string) error {
+	return u.oscMessageStore.setMode(ctx, mode)
+}
+
+// GetStoreSnapshot returns a snapshot of the current state of the message store.
+func (u UseCases) GetStoreSnapshot() usecaseifs.IMessageStore {
+	return u.oscMessageStore.store.Clone()
+}
+
+// WatchMessages returns a channel receiving the incoming messages of a connection matching the pattern, until stop is called.
+func (u UseCases) WatchMessages(connection string, pattern string) (<-chan usecaseifs.IOSCMessage, func(), error) {
+	return u.oscListener.watchMessages(connection, pattern)
+}
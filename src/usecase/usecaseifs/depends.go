@@ -11,6 +11,11 @@ type (
 	// IConfiguration determines the used configuration values & methods by the use-cases.
 	IConfiguration interface {
 		ShouldDebugOSCConditions() bool
+		GetMaxConcurrentActions() int
+		GetShutdownGraceMillis() int64
+		GetExclusiveGroup(name string) (policy string, minHoldMillis int64)
+		GetModes() []string
+		GetDefaultMode() string
 	}
 
 	// ILogger specifies an interface for general logging.
@@ -37,6 +42,11 @@ type (
 		SendMessage(ctx context.Context, msg IOSCMessage) error
 	}
 
+	// IConnectionHealth is optionally implemented by the IOSCConnections that are able to tell if their peer is reachable.
+	IConnectionHealth interface {
+		IsUp() bool
+	}
+
 	IOSCMessage interface {
 		Equal(msg IOSCMessage) bool
 		GetAddress() string
@@ -65,6 +75,81 @@ type (
 		IsStreaming(ctx context.Context) (bool, error)
 		IsRecording(ctx context.Context) (bool, error)
 		VendorRequest(ctx context.Context, vendorName string, requestType string, requestData interface{}) (responseData interface{}, err error)
+		ListInputs(ctx context.Context, kind string) ([]string, error)
+		IsInputMuted(ctx context.Context, inputName string) (bool, error)
+		GetInputVolume(ctx context.Context, inputName string) (db float64, mul float64, err error)
+		GetCurrentTransition(ctx context.Context) (name string, durationMillis int, err error)
+		IsStudioModeEnabled(ctx context.Context) (bool, error)
+		ListSceneItems(ctx context.Context, sceneName string) ([]OBSSceneItem, error)
+		ListSourceFilters(ctx context.Context, sourceName string) ([]OBSFilter, error)
+		IsReplayBufferActive(ctx context.Context) (bool, error)
+		IsVirtualCamActive(ctx context.Context) (bool, error)
+		GetMediaInputStatus(ctx context.Context, inputName string) (OBSMediaStatus, error)
+		StartRecording(ctx context.Context) error
+		StopRecording(ctx context.Context) error
+		ToggleRecording(ctx context.Context) error
+		StartStreaming(ctx context.Context) error
+		StopStreaming(ctx context.Context) error
+		ToggleStreaming(ctx context.Context) error
+		SaveReplayBuffer(ctx context.Context) error
+		SetInputMute(ctx context.Context, inputName string, muted bool) error
+		ToggleInputMute(ctx context.Context, inputName string) (bool, error)
+		SetInputVolumeDb(ctx context.Context, inputName string, db float64) error
+		SetInputVolumeMul(ctx context.Context, inputName string, mul float64) error
+		SetInputText(ctx context.Context, inputName string, text string) error
+		IsSceneItemEnabled(ctx context.Context, sceneName string, sourceName string) (bool, error)
+		SetSceneItemEnabled(ctx context.Context, sceneName string, sourceName string, enabled bool) error
+		TriggerStudioModeTransition(ctx context.Context, transitionName string, durationMillis int) error
+		TriggerHotkeyByName(ctx context.Context, hotkeyName string) error
+		TriggerHotkeyByKeySequence(ctx context.Context, keyID string, modifiers OBSKeyModifiers) error
+		IsSourceFilterEnabled(ctx context.Context, sourceName string, filterName string) (bool, error)
+		SetSourceFilterEnabled(ctx context.Context, sourceName string, filterName string, enabled bool) error
+		SaveSourceScreenshot(ctx context.Context, sourceName string, filePath string, format string, width int, height int, quality int) error
+	}
+
+	// OBSKeyModifiers are the modifier keys of a hotkey triggered by key sequence.
+	OBSKeyModifiers struct {
+		Shift   bool
+		Control bool
+		Alt     bool
+		Command bool
+	}
+
+	// OBSSceneItem is a source placed on a scene.
+	OBSSceneItem struct {
+		ID         int
+		SourceName string
+		Enabled    bool
+	}
+
+	// OBSFilter is a filter of a source.
+	OBSFilter struct {
+		Name    string
+		Enabled bool
+	}
+
+	// OBSMediaStatus is the playback state of a media input, State is e.g. OBS_MEDIA_STATE_PLAYING.
+	OBSMediaStatus struct {
+		State          string
+		CursorMillis   int
+		DurationMillis int
+	}
+
+	// IVISCARemote controls a PTZ camera. Positions are in the units of the camera, speeds start from 1.
+	IVISCARemote interface {
+		SetPower(ctx context.Context, on bool) error
+		RecallPreset(ctx context.Context, preset int) error
+		StorePreset(ctx context.Context, preset int) error
+		PanTiltDrive(ctx context.Context, direction string, panSpeed int, tiltSpeed int) error
+		PanTiltAbsolute(ctx context.Context, pan int, tilt int, panSpeed int, tiltSpeed int) error
+		PanTiltRelative(ctx context.Context, pan int, tilt int, panSpeed int, tiltSpeed int) error
+		PanTiltHome(ctx context.Context) error
+		ZoomDrive(ctx context.Context, direction string, speed int) error
+		ZoomAbsolute(ctx context.Context, position int) error
+		GetPower(ctx context.Context) (bool, error)
+		GetPanTiltPosition(ctx context.Context) (pan int, tilt int, err error)
+		GetZoomPosition(ctx context.Context) (int, error)
+		GetPreset(ctx context.Context) (int, error)
 	}
 )
 
@@ -76,9 +161,11 @@ type (
 		GetOneRecordByRegexp(re string, trackAccess bool) (IMessageStoreRecord, error)
 		GetRecordsByRegexp(re string, trackAccess bool) ([]IMessageStoreRecord, error)
 		GetRecordsByPrefix(prefix string, trackAccess bool) []IMessageStoreRecord
+		GetRecordsByPattern(pattern string, trackAccess bool) ([]IMessageStoreRecord, error)
 		SetRecord(msg IOSCMessage) (updated bool)
 		WatchRecordAccess(msg *IOSCMessage)
 		GetWatchedRecordAccesses() int64
+		GetSize() int
 	}
 
 	IMessageStoreRecord interface {
@@ -94,11 +181,38 @@ type (
 		Validate() error
 	}
 
+	// IActionReferencingTask is a task that executes another action (or macro), resolved by the action composer.
+	IActionReferencingTask interface {
+		IActionTask
+		// GetReferencedAction returns the name of the action to execute, and whether its trigger chain must be checked.
+		GetReferencedAction() (name string, checkConditions bool)
+		SetReferencedAction(action IAction)
+	}
+
+	// IControlFlowTask is a task that executes nested tasks (e.g. if, parallel), composed recursively by the action composer.
+	IControlFlowTask interface {
+		IActionTask
+		// SetCondition sets the composed condition tree, it is called only if the task has a condition configured.
+		SetCondition(condition IActionCondition)
+		// SetBranches sets the composed nested task lists by their keys (tasks, then, else, on_error_tasks),
+		// [onError] is the error policy of the action.
+		SetBranches(branches map[string][]IActionTask, onError string)
+	}
+
 	IAction interface {
 		GetName() string
 		Evaluate(ctx context.Context, store IMessageStore) (bool, error)
 		Execute(ctx context.Context, store IMessageStore) error
 		GetDebounceMillis() int64
+		GetConcurrency() string
+		GetQueueSize() int
+		GetPriority() int
+		ShouldStopAfterMatch() bool
+		GetExclusiveGroup() string
+		GetCooldownMillis() int64
+		GetMaxExecutionsPerMinute() int
+		ShouldRunTrailing() bool
+		GetModes() []string
 	}
 
 	ActionConditionFactory func(path string) IActionCondition
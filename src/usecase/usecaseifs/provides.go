@@ -12,4 +12,45 @@ type (
 	}
 
 	IOSCMessageStore interface{}
+
+	// IRecordUpdater enables the drivers (e.g. tasks) to write the message store, which triggers the evaluation of the actions.
+	IRecordUpdater interface {
+		// UpdateRecord calls [update] with the current message at [address] (nil if there is none),
+		// and stores the message it returns, unless it is nil. Updates are serialized.
+		UpdateRecord(ctx context.Context, address string, update func(current IOSCMessage) (IOSCMessage, error)) error
+	}
+
+	// IStoreReader enables the drivers (e.g. tasks) to see the current state of the message store, not just the snapshot
+	// their execution was started with.
+	IStoreReader interface {
+		GetStoreSnapshot() IMessageStore
+	}
+
+	// IMessageWatcher enables the drivers (e.g. tasks) to wait for the messages arriving on a connection,
+	// including the ones that do not change the store (e.g. the reply to a query).
+	IMessageWatcher interface {
+		// WatchMessages returns a channel receiving the messages of [connection] with an address (without the prefix of
+		// the connection) matching the OSC [pattern]. The watching must be ended by calling [stop].
+		WatchMessages(connection string, pattern string) (messages <-chan IOSCMessage, stop func(), err error)
+	}
+
+	// IActionController enables the drivers (e.g. tasks, the admin API) to enable, disable and trigger the actions at runtime.
+	IActionController interface {
+		GetActionNames() []string
+		IsActionEnabled(name string) (bool, error)
+		// SetActionEnabled updates the enabled state of the action with the result of [update], and returns the new state.
+		SetActionEnabled(ctx context.Context, name string, update func(enabled bool) bool) (bool, error)
+		// TriggerAction executes the action in the background, regardless of its trigger chain.
+		TriggerAction(ctx context.Context, name string) error
+	}
+
+	// IModeController enables the drivers (e.g. the admin API) to see and switch the active mode at runtime.
+	IModeController interface {
+		// GetModes returns the configured modes, empty if the modes are not used.
+		GetModes() []string
+		// GetActiveMode returns the active mode, false if there is none.
+		GetActiveMode() (string, bool)
+		// SetMode switches to [mode], it fails if the mode is not configured.
+		SetMode(ctx context.Context, mode string) error
+	}
 )